@@ -1,23 +1,44 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/mstrand/rig/pkg/clipboard"
 	"github.com/mstrand/rig/pkg/config"
 	"github.com/mstrand/rig/pkg/crew"
 	"github.com/mstrand/rig/pkg/git"
+	"github.com/mstrand/rig/pkg/picker"
 	"github.com/mstrand/rig/pkg/polecat"
+	"github.com/mstrand/rig/pkg/suggest"
 	"github.com/mstrand/rig/pkg/tmux"
+	"github.com/mstrand/rig/pkg/ui"
 	"github.com/mstrand/rig/pkg/work"
 	"github.com/spf13/cobra"
 )
 
 var cfg *config.Config
 
+// Build metadata, set at build time with -ldflags "-X main.version=..."
+var (
+	version = "dev"
+	commit  = "unknown"
+	date    = "unknown"
+)
+
 // condensePath replaces the home directory with ~ for shorter display
 func condensePath(path string) string {
 	homeDir, err := os.UserHomeDir()
@@ -30,9 +51,331 @@ func condensePath(path string) string {
 	return path
 }
 
+// resolveSessionPath resolves a rig or crew name to its directory. Names of
+// the form <rig>@<crew> resolve to the crew worktree; anything else resolves
+// to the rig's repo.
+func resolveSessionPath(cfg *config.Config, name string) (string, error) {
+	if rigName, crewName, isCrew := config.ParseSessionName(name); isCrew {
+		crewPath := crew.Locate(cfg, rigName, crewName)
+		if _, err := os.Stat(crewPath); err != nil {
+			return "", fmt.Errorf("crew workspace not found: %s", crewPath)
+		}
+		return crewPath, nil
+	}
+
+	repoPath := cfg.GetRepoPath(name)
+	if !git.IsGitRepo(repoPath) {
+		return "", fmt.Errorf("repo not found: %s", repoPath)
+	}
+	return repoPath, nil
+}
+
+// resolvePath returns path with symlinks resolved, or path unchanged if it
+// can't be resolved, so paths reported by git compare equal to config paths
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// lookupBranches returns the current branch for each path, keyed like paths.
+// Lookups run on a bounded worker pool; paths whose branch can't be read map
+// to "unknown".
+func lookupBranches(paths map[string]string) map[string]string {
+	return lookupPaths(paths, git.GetCurrentBranch, "unknown")
+}
+
+// lookupCommits returns the short HEAD commit for each path, keyed like
+// paths. Paths without commits map to "—".
+func lookupCommits(paths map[string]string) map[string]string {
+	return lookupPaths(paths, git.CurrentCommitHash, "—")
+}
+
+// lookupStale returns a " ⏰ last commit 9d ago" label for each path whose
+// last commit is older than maxAge, keyed like paths. Paths whose last commit
+// can't be read aren't flagged.
+func lookupStale(paths map[string]string, maxAge time.Duration) map[string]string {
+	labels := lookupPaths(paths, func(path string) (string, error) {
+		last, err := git.LastCommitTime(path)
+		if err != nil || time.Since(last) <= maxAge {
+			return "", err
+		}
+		return " ⏰ last commit " + formatAge(time.Since(last)), nil
+	}, "")
+
+	stale := make(map[string]string)
+	for key, label := range labels {
+		if label != "" {
+			stale[key] = label
+		}
+	}
+	return stale
+}
+
+// lookupPaths runs lookup for each path on a bounded worker pool, using
+// fallback for paths where it fails
+func lookupPaths(paths map[string]string, lookup func(path string) (string, error), fallback string) map[string]string {
+	type job struct {
+		key  string
+		path string
+	}
+
+	jobs := make(chan job)
+	results := make(map[string]string, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				value, err := lookup(j.path)
+				if err != nil {
+					value = fallback
+				}
+				mu.Lock()
+				results[j.key] = value
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for key, path := range paths {
+		jobs <- job{key: key, path: path}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// listRepoNames returns the names of all git repos in RigsBase
+func listRepoNames(cfg *config.Config) []string {
+	names := []string{}
+	entries, err := os.ReadDir(cfg.RigsBase)
+	if err != nil {
+		return names
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && git.IsGitRepo(filepath.Join(cfg.RigsBase, entry.Name())) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// listSessionNames returns the names of all running sessions, as rig and
+// crew names rather than their normalized tmux names
+func listSessionNames(cfg *config.Config) []string {
+	sessions, err := tmux.ListSessions()
+	if err != nil {
+		return nil
+	}
+	return originalSessionNames(cfg, sessions)
+}
+
+// withSuggestion returns an error with msg, adding a "did you mean" hint
+// when one of candidates looks like a typo of name
+func withSuggestion(msg, name string, candidates []string) error {
+	if match := suggest.Closest(name, candidates); match != "" {
+		return fmt.Errorf("%s\nDid you mean '%s'?", msg, match)
+	}
+	return errors.New(msg)
+}
+
+// listCrewNames returns the names of all crew workspaces for a rig
+func listCrewNames(cfg *config.Config, rigName string) []string {
+	names := []string{}
+	entries, err := os.ReadDir(filepath.Join(cfg.CrewBase, rigName))
+	if err != nil {
+		return names
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// originalSessionNames maps tmux session names back to the rig and crew names
+// they were created from. tmux turns "." into "_", so a rig named my.app
+// shows up as my_app; names with no known original are returned unchanged.
+func originalSessionNames(cfg *config.Config, sessions []string) []string {
+	originals := make(map[string]string)
+	for _, rigName := range listRepoNames(cfg) {
+		originals[tmux.NormalizeSessionName(rigName)] = rigName
+	}
+	rigDirs, _ := os.ReadDir(cfg.CrewBase)
+	for _, rigDir := range rigDirs {
+		if !rigDir.IsDir() {
+			continue
+		}
+		for _, crewName := range listCrewNames(cfg, rigDir.Name()) {
+			name := cfg.GetCrewSessionName(rigDir.Name(), crewName)
+			originals[tmux.NormalizeSessionName(name)] = name
+		}
+	}
+
+	names := make([]string, len(sessions))
+	for i, session := range sessions {
+		if original, ok := originals[session]; ok {
+			names[i] = original
+		} else {
+			names[i] = session
+		}
+	}
+	return names
+}
+
+// filterPrefix returns the candidates that start with prefix
+func filterPrefix(candidates []string, prefix string) []string {
+	matches := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// completeRepoNames completes the first positional argument with repo names
+func completeRepoNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(listRepoNames(cfg), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSessionNames completes the first positional argument with running tmux sessions
+func completeSessionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(listSessionNames(cfg), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCrewNames completes the first positional argument with crew names
+// for the rig given by --rig (or inferred from the current context)
+func completeCrewNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	rigName, _ := cmd.Flags().GetString("rig")
+	rigName, err := crew.InferRig(cfg, rigName)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(listCrewNames(cfg, rigName), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCrewFlag completes a flag that takes a crew name
+func completeCrewFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeCrewNames(cmd, nil, toComplete)
+}
+
+// completeBranchFlag completes a flag that takes a branch of the rig
+func completeBranchFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rigName, _ := cmd.Flags().GetString("rig")
+	rigName, err := crew.InferRig(cfg, rigName)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	branches, err := git.ListBranches(cfg.GetRepoPath(rigName))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(branches, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePaneNames completes the --pane flag with pane names
+func completePaneNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{tmux.PaneClaude, tmux.PaneTerminal}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLayouts completes the --layout flag with layout names
+func completeLayouts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{config.LayoutNative, config.LayoutCC}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRigFlag completes the --rig flag with repo names
+func completeRigFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterPrefix(listRepoNames(cfg), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFormulas completes the --formula flag with formulas from the current repo
+func completeFormulas(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repoPath, err := git.GetRepoRoot(pwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	formulas, err := work.ListFormulas(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(formulas, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeWorkPaths completes the first positional argument with work/<name> paths
+// from the current repo
+func completeWorkPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repoPath, err := git.GetRepoRoot(pwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := work.List(repoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	paths := []string{}
+	for _, name := range names {
+		paths = append(paths, "work/"+name)
+	}
+	return filterPrefix(paths, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
 func main() {
 	cfg = config.Load()
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
 
+// newRootCmd builds the rig command tree
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "rig",
 		Short: "Manage tmux-based development environments",
@@ -44,16 +387,33 @@ Examples:
     rig status              Show all running rigs and crew
     rig down myapp          Shut down the myapp rig
     rig down                Shut down current rig (infers from context)`,
+		// Config warnings wait until flags are parsed so --quiet can silence
+		// them
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if ui.Quiet {
+				return
+			}
+			for _, warning := range cfg.Warnings() {
+				ui.Warnf("%s", warning)
+			}
+		},
 	}
 
 	// Rig commands
 	rootCmd.AddCommand(upCmd())
+	rootCmd.AddCommand(cloneCmd())
 	rootCmd.AddCommand(downCmd())
 	rootCmd.AddCommand(statusCmd())
 	rootCmd.AddCommand(listCmd())
 	rootCmd.AddCommand(switchCmd())
 	rootCmd.AddCommand(atCmd())
+	rootCmd.AddCommand(execCmd())
+	rootCmd.AddCommand(peekCmd())
 	rootCmd.AddCommand(killallCmd())
+	rootCmd.AddCommand(logCmd())
+	rootCmd.AddCommand(diffCmd())
+	rootCmd.AddCommand(whichCmd())
+	rootCmd.AddCommand(openCmd())
 
 	// Crew commands
 	rootCmd.AddCommand(crewCmd())
@@ -62,20 +422,48 @@ Examples:
 	rootCmd.AddCommand(workCmd())
 	rootCmd.AddCommand(hookCmd())
 	rootCmd.AddCommand(slingCmd())
+	rootCmd.AddCommand(mvWorkCmd())
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	// Shell completion
+	rootCmd.AddCommand(completionCmd())
+	rootCmd.AddCommand(versionCmd())
+
+	rootCmd.PersistentFlags().BoolVarP(&ui.Quiet, "quiet", "q", false, "Only print errors and command results")
+
+	return rootCmd
 }
 
 func upCmd() *cobra.Command {
-	return &cobra.Command{
+	var window string
+	var printPath bool
+	var layout string
+
+	cmd := &cobra.Command{
 		Use:   "up [name]",
 		Short: "Bring up a rig (creates or switches)",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Bring up a rig: create its session if needed and attach to it.
+
+With no name, the rig is inferred from the current directory.
+
+With --print-path, only prints the rig's repo path, without touching tmux,
+for use in shell functions:
+    cd "$(rig up --print-path notes)"
+
+With --layout, a new session uses the native (window per pane) or cc
+(iTerm2 control mode) layout instead of the configured one:
+    rig up notes --layout native`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepoNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if printPath && window != "" {
+				return fmt.Errorf("--window can't be combined with --print-path")
+			}
+			useCC, err := cfg.LayoutUseCC(layout)
+			if err != nil {
+				return err
+			}
+
 			var name string
-			var err error
 
 			if len(args) == 0 {
 				// Infer rig from current context
@@ -83,42 +471,188 @@ func upCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				fmt.Printf("Inferred rig: %s\n", name)
+				if !printPath {
+					ui.Printf("Inferred rig: %s\n", name)
+				}
 			} else {
 				name = args[0]
 			}
 
-			repoPath := cfg.GetRepoPath(name)
+			if printPath {
+				repoPath, err := resolveRepoPath(cfg, name)
+				if err != nil {
+					return err
+				}
+				fmt.Println(repoPath)
+				return nil
+			}
+
+			// The layout applies to this invocation only
+			upCfg := *cfg
+			upCfg.UseCC = useCC
+			return upRig(&upCfg, name, window)
+		},
+	}
+
+	cmd.Flags().StringVar(&window, "window", "", "Window to start in (claude or terminal; a pane in CC mode)")
+	cmd.Flags().BoolVar(&printPath, "print-path", false, "Print the rig's repo path and exit, without starting a session")
+	cmd.Flags().StringVar(&layout, "layout", "", "Session layout (native or cc) instead of the configured one")
+	cmd.RegisterFlagCompletionFunc("layout", completeLayouts)
+
+	return cmd
+}
+
+// resolveRepoPath returns the repo path for a rig, checking that it's a git
+// repo
+func resolveRepoPath(cfg *config.Config, name string) (string, error) {
+	if err := config.ValidateRigName(name); err != nil {
+		return "", err
+	}
+
+	repoPath := cfg.GetRepoPath(name)
+	if !git.IsGitRepo(repoPath) {
+		return "", withSuggestion(fmt.Sprintf("repo not found: %s", repoPath), name, listRepoNames(cfg))
+	}
+	return repoPath, nil
+}
+
+// upRig creates a rig's session if needed and attaches to it, optionally
+// landing on a given window
+func upRig(cfg *config.Config, name, window string) error {
+	repoPath, err := resolveRepoPath(cfg, name)
+	if err != nil {
+		return err
+	}
+	if window != "" {
+		if _, err := tmux.PaneTarget(name, window, cfg.UseCC); err != nil {
+			return err
+		}
+	}
 
-			if !git.IsGitRepo(repoPath) {
-				return fmt.Errorf("repo not found: %s", repoPath)
+	sessionName := name
+
+	// Select the requested window first so attaching lands on it
+	attach := func() error {
+		if window != "" {
+			if err := tmux.SelectPane(sessionName, window, cfg.UseCC); err != nil {
+				return err
 			}
+		}
+		return tmux.AttachSession(sessionName, cfg.UseCC)
+	}
+
+	if tmux.SessionExists(sessionName) {
+		if tmux.SessionHealthy(sessionName) {
+			ui.Printf("Switching to existing rig: %s\n", name)
+			return attach()
+		}
+
+		ui.Warnf("Session %s exists but has no live panes", sessionName)
+		fmt.Print("Recreate it? [Y/n] ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) == "n" {
+			return fmt.Errorf("session %s has no live panes", sessionName)
+		}
+		if err := tmux.KillSession(sessionName); err != nil {
+			return fmt.Errorf("failed to kill session %s: %w", sessionName, err)
+		}
+	}
+
+	ui.Printf("Creating new rig: %s\n", name)
+	ui.Printf("Repo: %s\n", repoPath)
+
+	opts := crew.SessionOptions(cfg, repoPath)
+	repos, err := cfg.GetRepos(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", config.ReposFile, err)
+	}
+	for _, repo := range repos {
+		if info, err := os.Stat(repo); err != nil || !info.IsDir() {
+			return fmt.Errorf("repo listed in %s not found: %s", config.ReposFile, repo)
+		}
+	}
+	opts.Repos = repos
+
+	if err := tmux.CreateRigSession(sessionName, repoPath, cfg.UseCC, opts); err != nil {
+		return fmt.Errorf("failed to create rig session: %w", err)
+	}
+
+	ui.Printf("✓ Rig created: %s\n", name)
+	return attach()
+}
+
+func cloneCmd() *cobra.Command {
+	var up bool
+
+	cmd := &cobra.Command{
+		Use:   "clone <url> [name]",
+		Short: "Clone a repo into the rigs directory",
+		Long: `Clone a repo into the rigs directory.
+
+Clones into $RIGS_BASE/<name>, where the name defaults to the last part of
+the URL without ".git". Use --up to bring the new rig up straight away.
 
-			sessionName := name
+Examples:
+    rig clone git@github.com:me/notes.git        Clone into ~/git/notes
+    rig clone https://github.com/me/api backend  Clone into ~/git/backend
+    rig clone git@github.com:me/notes.git --up   Clone, then rig up notes`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			url := args[0]
+			name := repoNameFromURL(url)
+			if len(args) == 2 {
+				name = args[1]
+			}
+			if err := config.ValidateRigName(name); err != nil {
+				return err
+			}
 
-			if tmux.SessionExists(sessionName) {
-				fmt.Printf("Switching to existing rig: %s\n", name)
-				return tmux.AttachSession(sessionName, cfg.UseCC)
+			repoPath := cfg.GetRepoPath(name)
+			if _, err := os.Stat(repoPath); err == nil {
+				return fmt.Errorf("destination already exists: %s", repoPath)
 			}
 
-			fmt.Printf("Creating new rig: %s\n", name)
-			fmt.Printf("Repo: %s\n", repoPath)
+			if err := cfg.EnsureRigsBase(); err != nil {
+				return err
+			}
 
-			if err := tmux.CreateRigSession(sessionName, repoPath, cfg.UseCC, cfg.ClaudeInitPrompt); err != nil {
-				return fmt.Errorf("failed to create rig session: %w", err)
+			ui.Printf("Cloning %s into %s...\n", url, condensePath(repoPath))
+			if err := git.Clone(url, repoPath); err != nil {
+				return err
 			}
+			ui.Printf("✓ Cloned %s\n", name)
 
-			fmt.Printf("✓ Rig created: %s\n", name)
-			return tmux.AttachSession(sessionName, cfg.UseCC)
+			if up {
+				return upRig(cfg, name, "")
+			}
+			ui.Printf("Bring it up with: rig up %s\n", name)
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&up, "up", false, "Bring the rig up after cloning")
+
+	return cmd
+}
+
+// repoNameFromURL returns the repo name at the end of a clone URL or path,
+// e.g. "notes" for git@github.com:me/notes.git
+func repoNameFromURL(url string) string {
+	name := strings.TrimRight(url, "/")
+	name = strings.TrimSuffix(name, ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
 }
 
 func downCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "down [name]",
-		Short: "Shut down a rig",
-		Args:  cobra.MaximumNArgs(1),
+		Use:               "down [name]",
+		Short:             "Shut down a rig",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var name string
 			var err error
@@ -129,159 +663,342 @@ func downCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				fmt.Printf("Inferred rig: %s\n", name)
+				ui.Printf("Inferred rig: %s\n", name)
 			} else {
 				name = args[0]
 			}
 
 			if !tmux.SessionExists(name) {
-				return fmt.Errorf("rig not found: %s", name)
+				return withSuggestion(fmt.Sprintf("rig not found: %s", name), name, listSessionNames(cfg))
 			}
 
 			if err := tmux.KillSession(name); err != nil {
 				return err
 			}
 
-			fmt.Printf("✓ Rig shut down: %s\n", name)
+			ui.Printf("✓ Rig shut down: %s\n", name)
 			return nil
 		},
 	}
 }
 
 func statusCmd() *cobra.Command {
+	var watch int
+	var stale string
+	var opts statusOptions
+
 	cmd := &cobra.Command{
 		Use:     "status",
 		Aliases: []string{"ls"},
 		Short:   "Show all active rigs and crew",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			sessions, err := tmux.ListSessions()
-			if err != nil {
-				return err
-			}
+		Long: `Show all active rigs and crew.
 
-			if len(sessions) == 0 {
-				fmt.Println("No active rigs or crew")
-				fmt.Println()
-				fmt.Println("Start a rig with: rig up <name>")
-				fmt.Println("Start crew with: rig crew add <name>")
-				return nil
-			}
+With --watch, the status is re-rendered every 2 seconds (or the given
+number of seconds) until interrupted:
+    rig status --watch
+    rig status --watch 5
 
-			currentSession := tmux.GetCurrentSession()
+With --sha, each rig and crew also shows its short HEAD commit:
+    rig status --sha
 
-			var rigSessions []string
-			var crewSessions []string
+With --stale, sessions whose last commit is older than the given duration
+are flagged with ⏰, to help decide what to prune:
+    rig status --stale 3d
 
-			for _, session := range sessions {
-				if strings.Contains(session, "@") {
-					// Crew session
-					parts := strings.Split(session, "@")
-					rigPart, namePart := parts[0], parts[1]
-					crewPath := cfg.GetCrewPath(rigPart, namePart)
-					if _, err := os.Stat(crewPath); err == nil {
-						crewSessions = append(crewSessions, session)
-					}
-				} else {
-					// Rig session
-					repoPath := cfg.GetRepoPath(session)
-					if git.IsGitRepo(repoPath) {
-						rigSessions = append(rigSessions, session)
-					}
+Use --crew-only or --rigs-only to show just one section:
+    rig status --crew-only
+    rig status --rigs-only`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.CrewOnly && opts.RigsOnly {
+				return fmt.Errorf("--crew-only can't be combined with --rigs-only")
+			}
+			if stale != "" {
+				maxAge, err := parseAge(stale)
+				if err != nil {
+					return err
 				}
+				if maxAge == 0 {
+					return fmt.Errorf("--stale must be greater than zero")
+				}
+				opts.Stale = maxAge
 			}
 
-			// Display rig sessions
-			fmt.Println("🏗️  Active Rigs")
-			fmt.Println()
-
-			if len(rigSessions) == 0 {
-				fmt.Println("  No active rigs")
-			} else {
-				for _, session := range rigSessions {
-					activeMarker := " "
-					if session == currentSession {
-						activeMarker = "✓"
-					}
-					repoPath := cfg.GetRepoPath(session)
-					branch, err := git.GetCurrentBranch(repoPath)
-					if err != nil {
-						branch = "unknown"
-					}
-
-					// Condense path with ~
-					displayPath := condensePath(repoPath)
-
-					fmt.Printf("  %s %s\n", activeMarker, session)
-					fmt.Printf("      %-50s 🌿 %s\n", displayPath, branch)
-					fmt.Println()
+			if !cmd.Flags().Changed("watch") {
+				if len(args) > 0 {
+					return fmt.Errorf("unexpected argument: %s", args[0])
 				}
+				return renderStatus(cfg, opts)
 			}
 
-			// Display crew sessions
-			fmt.Println("👥 Crew")
-			fmt.Println()
-
-			if len(crewSessions) == 0 {
-				fmt.Println("  No active crew")
-			} else {
-				for _, session := range crewSessions {
-					activeMarker := " "
-					if session == currentSession {
-						activeMarker = "✓"
-					}
-					parts := strings.Split(session, "@")
-					rigPart, namePart := parts[0], parts[1]
-					crewPath := cfg.GetCrewPath(rigPart, namePart)
-
-					emoji := "👤"
-					if polecat.IsPolecat(namePart) {
-						emoji = "🐱"
-					}
-
-					branch, err := git.GetCurrentBranch(crewPath)
-					if err != nil {
-						branch = "unknown"
-					}
-
-					// Condense path with ~
-					displayPath := condensePath(crewPath)
-
-					fmt.Printf("  %s %s %s\n", activeMarker, emoji, session)
-					fmt.Printf("      %-50s 🌿 %s\n", displayPath, branch)
-					fmt.Println()
+			// Allow "--watch 5" in addition to "--watch=5"
+			if len(args) == 1 {
+				seconds, err := strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid watch interval: %s", args[0])
 				}
+				watch = seconds
 			}
-
-			if len(rigSessions) == 0 && len(crewSessions) == 0 {
-				fmt.Println()
-				fmt.Println("Start a rig with: rig up <name>")
-				fmt.Println("Start crew with: rig crew add <name>")
+			if watch <= 0 {
+				return fmt.Errorf("watch interval must be positive, got %d", watch)
 			}
 
-			return nil
+			return watchStatus(cfg, time.Duration(watch)*time.Second, opts)
 		},
 	}
+
+	cmd.Flags().IntVar(&watch, "watch", 0, "Refresh every N seconds until interrupted (default 2)")
+	cmd.Flags().Lookup("watch").NoOptDefVal = "2"
+	cmd.Flags().BoolVar(&opts.ShowSHA, "sha", false, "Show the short commit each rig and crew is on")
+	cmd.Flags().BoolVar(&opts.CrewOnly, "crew-only", false, "Only show crew sessions")
+	cmd.Flags().BoolVar(&opts.RigsOnly, "rigs-only", false, "Only show rig sessions")
+	cmd.Flags().StringVar(&stale, "stale", "", "Flag sessions whose last commit is older than this (e.g. 36h, 7d)")
+
 	return cmd
 }
 
-func listCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "list",
-		Short: "List available repos",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("🏗️  Available Repos")
+// watchStatus clears the screen and re-renders the status on every interval
+// until interrupted
+func watchStatus(cfg *config.Config, interval time.Duration, opts statusOptions) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Move cursor home and clear the screen
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s: rig status    %s\n\n", interval, time.Now().Format("15:04:05"))
+		if err := renderStatus(cfg, opts); err != nil {
+			return err
+		}
+
+		select {
+		case <-sigs:
 			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
 
-			entries, err := os.ReadDir(cfg.RigsBase)
-			if err != nil {
-				return fmt.Errorf("base directory does not exist: %s", cfg.RigsBase)
-			}
+// statusOptions controls what rig status shows
+type statusOptions struct {
+	ShowSHA  bool
+	CrewOnly bool          // hide the rigs section
+	RigsOnly bool          // hide the crew section
+	Stale    time.Duration // flag sessions with no commit this recent; 0 disables
+}
 
-			count := 0
-			for _, entry := range entries {
-				if entry.IsDir() {
-					path := filepath.Join(cfg.RigsBase, entry.Name())
-					if git.IsGitRepo(path) {
+// colorCrewStatus colors the parts of a crew status like "running, dirty"
+func colorCrewStatus(status string) string {
+	parts := strings.Split(status, ", ")
+	for i, part := range parts {
+		switch part {
+		case "running":
+			parts[i] = ui.Green(part)
+		case "dirty":
+			parts[i] = ui.Red(part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// colorWorkStatus colors a work status from progress.md, which may be
+// padded for alignment
+func colorWorkStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "done", "complete", "completed":
+		return ui.Green(status)
+	case "in progress":
+		return ui.Yellow(status)
+	case "blocked", "no progress file", "malformed progress":
+		return ui.Red(status)
+	}
+	return status
+}
+
+// attachedLabel marks sessions that have a client attached somewhere. The
+// map is keyed by tmux (normalized) session name.
+func attachedLabel(attached map[string]bool, session string) string {
+	if attached[tmux.NormalizeSessionName(session)] {
+		return " (attached)"
+	}
+	return ""
+}
+
+// renderStatus prints all active rigs and crew sessions
+func renderStatus(cfg *config.Config, opts statusOptions) error {
+	detailed, err := tmux.ListSessionsDetailed()
+	if err != nil {
+		return err
+	}
+	sessions := []string{}
+	attached := make(map[string]bool)
+	for _, s := range detailed {
+		sessions = append(sessions, s.Name)
+		attached[s.Name] = s.Attached > 0
+	}
+	running := tmux.NewSessionSet(sessions)
+	sessions = originalSessionNames(cfg, sessions)
+	showRigs, showCrew := !opts.CrewOnly, !opts.RigsOnly
+
+	if len(sessions) == 0 && showRigs && showCrew {
+		fmt.Println("No active rigs or crew")
+		fmt.Println()
+		fmt.Println("Start a rig with: rig up <name>")
+		fmt.Println("Start crew with: rig crew add <name>")
+		return nil
+	}
+
+	currentSession := tmux.GetCurrentSession()
+
+	var rigSessions []string
+	var crewSessions []crew.CrewInfo
+
+	if showRigs {
+		for _, session := range sessions {
+			if _, _, isCrew := config.ParseSessionName(session); !isCrew && git.IsGitRepo(cfg.GetRepoPath(session)) {
+				rigSessions = append(rigSessions, session)
+			}
+		}
+	}
+	if showCrew {
+		workspaces, err := crew.DiscoverWith(cfg, running)
+		if err != nil {
+			return err
+		}
+		for _, info := range workspaces {
+			if info.Running {
+				crewSessions = append(crewSessions, info)
+			}
+		}
+	}
+
+	// Look up branches for all sessions at once
+	sessionPaths := make(map[string]string)
+	for _, session := range rigSessions {
+		sessionPaths[session] = cfg.GetRepoPath(session)
+	}
+	for _, info := range crewSessions {
+		sessionPaths[info.SessionName] = info.Path
+	}
+	branches := lookupBranches(sessionPaths)
+	var commits map[string]string
+	if opts.ShowSHA {
+		commits = lookupCommits(sessionPaths)
+	}
+	var stale map[string]string
+	if opts.Stale > 0 {
+		stale = lookupStale(sessionPaths, opts.Stale)
+	}
+
+	// Display rig sessions
+	if showRigs {
+		fmt.Println("🏗️  Active Rigs")
+		fmt.Println()
+		if len(rigSessions) == 0 {
+			fmt.Println("  No active rigs")
+		} else {
+			for _, session := range rigSessions {
+				activeMarker := " "
+				if tmux.NormalizeSessionName(session) == currentSession {
+					activeMarker = "✓"
+				}
+				repoPath := sessionPaths[session]
+				branch := branches[session]
+				if opts.ShowSHA {
+					branch += " @ " + commits[session]
+				}
+
+				// Condense path with ~
+				displayPath := condensePath(repoPath)
+
+				fmt.Printf("  %s %s%s%s\n", activeMarker, ui.Green(session), attachedLabel(attached, session), ui.Yellow(stale[session]))
+				fmt.Printf("      %-50s 🌿 %s\n", displayPath, branch)
+				fmt.Println()
+			}
+		}
+	}
+
+	// Display crew sessions
+	if showCrew {
+		fmt.Println("👥 Crew")
+		fmt.Println()
+		if len(crewSessions) == 0 {
+			fmt.Println("  No active crew")
+		} else {
+			for _, info := range crewSessions {
+				session := info.SessionName
+				activeMarker := " "
+				if tmux.NormalizeSessionName(session) == currentSession {
+					activeMarker = "✓"
+				}
+				crewPath := info.Path
+
+				emoji := "👤"
+				if info.IsPolecat {
+					emoji = "🐱"
+				}
+
+				branch := branches[session]
+				if opts.ShowSHA {
+					branch += " @ " + commits[session]
+				}
+
+				// Condense path with ~
+				displayPath := condensePath(crewPath)
+
+				fmt.Printf("  %s %s %s%s%s\n", activeMarker, emoji, ui.Green(session), attachedLabel(attached, session), ui.Yellow(stale[session]))
+				fmt.Printf("      %-50s 🌿 %s\n", displayPath, branch)
+				fmt.Println()
+			}
+		}
+	}
+
+	// Hint at how to start whatever shown category is empty
+	needRigs := showRigs && len(rigSessions) == 0
+	needCrew := showCrew && len(crewSessions) == 0
+	if needRigs || needCrew {
+		fmt.Println()
+	}
+	if needRigs {
+		fmt.Println("Start a rig with: rig up <name>")
+	}
+	if needCrew {
+		fmt.Println("Start crew with: rig crew add <name>")
+	}
+
+	return nil
+}
+
+func listCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "list",
+		Short:             "List available repos",
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println("🏗️  Available Repos")
+			fmt.Println()
+
+			entries, err := os.ReadDir(cfg.RigsBase)
+			if os.IsNotExist(err) {
+				fmt.Printf("  No repos yet (%s doesn't exist)\n", condensePath(cfg.RigsBase))
+				fmt.Println()
+				fmt.Println("Clone one with: rig clone <url>")
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", cfg.RigsBase, err)
+			}
+
+			count := 0
+			for _, entry := range entries {
+				if entry.IsDir() {
+					path := filepath.Join(cfg.RigsBase, entry.Name())
+					if git.IsGitRepo(path) {
 						status := ""
 						if tmux.SessionExists(entry.Name()) {
 							status = " [running]"
@@ -293,7 +1010,7 @@ func listCmd() *cobra.Command {
 			}
 
 			if count == 0 {
-				fmt.Println("  No git repos found")
+				fmt.Printf("  No git repos found in %s\n", condensePath(cfg.RigsBase))
 			}
 
 			fmt.Println()
@@ -303,29 +1020,78 @@ func listCmd() *cobra.Command {
 	}
 }
 
+// sessionPicker chooses a session for --select; replaced in tests
+var sessionPicker picker.Picker = picker.Prompt{}
+
+// attachSession attaches to a tmux session; replaced in tests
+var attachSession = tmux.AttachSession
+
+// killSession kills a tmux session; replaced in tests
+var killSession = tmux.KillSession
+
+// selectAndAttach lets the user pick a running session and attaches to it
+func selectAndAttach(cfg *config.Config) error {
+	sessions, err := tmux.ListSessions()
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no tmux sessions running")
+	}
+	sessions = originalSessionNames(cfg, sessions)
+
+	sessionName, err := sessionPicker.Pick("Session", sessions)
+	if err != nil {
+		return err
+	}
+
+	return attachSession(sessionName, crew.SessionUseCC(cfg, sessionName))
+}
+
 func switchCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "switch <name>",
-		Short: "Switch to a rig or crew session",
-		Args:  cobra.ExactArgs(1),
+	var selectSession bool
+
+	cmd := &cobra.Command{
+		Use:               "switch [name]",
+		Short:             "Switch to a rig or crew session",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if selectSession {
+				return selectAndAttach(cfg)
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("session name required (or use --select)")
+			}
+
 			sessionName := args[0]
 
 			if !tmux.SessionExists(sessionName) {
-				return fmt.Errorf("session not found: %s", sessionName)
+				return withSuggestion(fmt.Sprintf("session not found: %s", sessionName), sessionName, listSessionNames(cfg))
 			}
 
-			return tmux.AttachSession(sessionName, cfg.UseCC)
+			return attachSession(sessionName, crew.SessionUseCC(cfg, sessionName))
 		},
 	}
+
+	cmd.Flags().BoolVarP(&selectSession, "select", "s", false, "Pick the session interactively")
+
+	return cmd
 }
 
 func atCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "at [name]",
-		Short: "Attach to a tmux session (default session if no name provided)",
-		Args:  cobra.MaximumNArgs(1),
+	var selectSession bool
+
+	cmd := &cobra.Command{
+		Use:               "at [name]",
+		Short:             "Attach to a tmux session (default session if no name provided)",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if selectSession {
+				return selectAndAttach(cfg)
+			}
+
 			if len(args) == 0 {
 				// No name provided, attach to default tmux session
 				return tmux.AttachDefault(cfg.UseCC)
@@ -333,13 +1099,159 @@ func atCmd() *cobra.Command {
 
 			// Name provided, attach to specific session
 			sessionName := args[0]
+			if !tmux.SessionExists(sessionName) {
+				return withSuggestion(fmt.Sprintf("session not found: %s", sessionName), sessionName, listSessionNames(cfg))
+			}
+
+			return attachSession(sessionName, crew.SessionUseCC(cfg, sessionName))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&selectSession, "select", "s", false, "Pick the session interactively")
+
+	return cmd
+}
+
+func execCmd() *cobra.Command {
+	var pane string
+
+	cmd := &cobra.Command{
+		Use:   "exec <session> -- <command>",
+		Short: "Run a command in a session's pane",
+		Long: `Type a command into a rig or crew session's pane and press enter.
+The terminal pane is used unless --pane selects another.
+
+Examples:
+    rig exec notes -- make test
+    rig exec notes@tracy --pane claude -- rig hook`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 1 {
+				return fmt.Errorf("usage: rig exec <session> -- <command>")
+			}
+
+			sessionName := args[0]
+			command := strings.Join(args[1:], " ")
+
 			if !tmux.SessionExists(sessionName) {
 				return fmt.Errorf("session not found: %s", sessionName)
 			}
 
-			return tmux.AttachSession(sessionName, cfg.UseCC)
+			target, err := tmux.PaneTarget(sessionName, pane, crew.SessionUseCC(cfg, sessionName))
+			if err != nil {
+				return err
+			}
+
+			if err := tmux.SendCommand(target, command); err != nil {
+				return err
+			}
+
+			ui.Printf("✓ Sent to %s: %s\n", target, command)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pane, "pane", tmux.PaneTerminal, "Pane to run in (claude or terminal)")
+	cmd.RegisterFlagCompletionFunc("pane", completePaneNames)
+
+	return cmd
+}
+
+func peekCmd() *cobra.Command {
+	var pane string
+	var lines int
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "peek <name>",
+		Short: "Show the recent output of a session's pane without attaching",
+		Long: `Print the last lines of a rig or crew session's Claude Code pane
+without attaching, so a working polecat isn't disturbed. With --follow,
+keeps printing new output as it appears until interrupted.
+
+Examples:
+    rig peek notes@polecat_emma
+    rig peek notes@polecat_emma --follow
+    rig peek notes --pane terminal --lines 50`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionName := args[0]
+
+			if !tmux.SessionExists(sessionName) {
+				return fmt.Errorf("session not found: %s", sessionName)
+			}
+			return showPane(sessionName, pane, lines, follow)
 		},
 	}
+
+	cmd.Flags().StringVar(&pane, "pane", tmux.PaneClaude, "Pane to show (claude or terminal)")
+	cmd.RegisterFlagCompletionFunc("pane", completePaneNames)
+	cmd.Flags().IntVarP(&lines, "lines", "n", 20, "Number of lines to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new output until interrupted")
+
+	return cmd
+}
+
+// showPane prints the last lines of a session's pane and, with follow, keeps
+// printing its new output until interrupted
+func showPane(sessionName, pane string, lines int, follow bool) error {
+	target, err := tmux.PaneTarget(sessionName, pane, crew.SessionUseCC(cfg, sessionName))
+	if err != nil {
+		return err
+	}
+
+	output, err := tmux.CapturePane(target)
+	if err != nil {
+		return err
+	}
+	fmt.Println(lastLines(output, lines))
+
+	if !follow {
+		return nil
+	}
+	return followPane(sessionName, target, output, time.Second)
+}
+
+// lastLines returns the last n lines of s, ignoring trailing blank lines
+func lastLines(s string, n int) string {
+	all := strings.Split(strings.TrimRight(s, "\n "), "\n")
+	if n > 0 && len(all) > n {
+		all = all[len(all)-n:]
+	}
+	return strings.Join(all, "\n")
+}
+
+// newLines returns the lines of cur that weren't already in prev, treating
+// both as snapshots of a scrolling pane. When cur doesn't continue prev (the
+// pane was cleared or redrawn), all of cur is new.
+func newLines(prev, cur string) string {
+	curLines := splitPane(cur)
+	prevLines := splitPane(prev)
+
+	// Find the smallest scroll offset at which the rest of prev is a prefix
+	// of cur; the lines after that overlap are new
+	for shift := 0; shift < len(prevLines); shift++ {
+		overlap := prevLines[shift:]
+		if len(overlap) > len(curLines) {
+			continue
+		}
+		if slices.Equal(overlap, curLines[:len(overlap)]) {
+			return strings.Join(curLines[len(overlap):], "\n")
+		}
+	}
+	return strings.Join(curLines, "\n")
+}
+
+// splitPane splits captured pane output into lines, ignoring trailing blank
+// lines
+func splitPane(s string) []string {
+	s = strings.TrimRight(s, "\n ")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
 }
 
 func killallCmd() *cobra.Command {
@@ -360,10 +1272,24 @@ func killallCmd() *cobra.Command {
 				return nil
 			}
 
+			// Crew sessions only count if their workspace still exists
+			workspaces, err := crew.DiscoverWith(cfg, tmux.NewSessionSet(sessions))
+			if err != nil {
+				return err
+			}
+			crewSessions := make(map[string]bool)
+			for _, info := range workspaces {
+				if info.Running {
+					crewSessions[tmux.NormalizeSessionName(info.SessionName)] = true
+				}
+			}
+			sessions = originalSessionNames(cfg, sessions)
+
 			killedCount := 0
+			var failures []string
 
 			for _, session := range sessions {
-				isCrew := strings.Contains(session, "@")
+				_, _, isCrew := config.ParseSessionName(session)
 				isRig := false
 
 				if !isCrew {
@@ -372,12 +1298,7 @@ func killallCmd() *cobra.Command {
 						isRig = true
 					}
 				} else {
-					parts := strings.Split(session, "@")
-					rigPart, namePart := parts[0], parts[1]
-					crewPath := cfg.GetCrewPath(rigPart, namePart)
-					if _, err := os.Stat(crewPath); err != nil {
-						isCrew = false
-					}
+					isCrew = crewSessions[tmux.NormalizeSessionName(session)]
 				}
 
 				shouldKill := false
@@ -390,16 +1311,23 @@ func killallCmd() *cobra.Command {
 				}
 
 				if shouldKill {
-					tmux.KillSession(session)
-					fmt.Printf("  Killed: %s\n", session)
+					if err := killSession(session); err != nil {
+						failures = append(failures, fmt.Sprintf("%s: %v", session, err))
+						continue
+					}
+					ui.Printf("  Killed: %s\n", session)
 					killedCount++
 				}
 			}
 
+			if len(failures) > 0 {
+				return fmt.Errorf("killed %d, failed %d (%s)", killedCount, len(failures), strings.Join(failures, "; "))
+			}
+
 			if killedCount == 0 {
-				fmt.Println("No matching sessions to kill")
+				ui.Println("No matching sessions to kill")
 			} else {
-				fmt.Printf("Killed %d session(s)\n", killedCount)
+				ui.Printf("Killed %d session(s)\n", killedCount)
 			}
 
 			return nil
@@ -412,29 +1340,78 @@ func killallCmd() *cobra.Command {
 	return cmd
 }
 
-func crewCmd() *cobra.Command {
+func logCmd() *cobra.Command {
+	var count int
+	var since string
+
 	cmd := &cobra.Command{
-		Use:   "crew",
-		Short: "Manage crew workspaces",
+		Use:   "log <name>",
+		Short: "Show recent commits for a rig or crew (<rig>@<crew>)",
+		Long: `Show recent commits for a rig or crew workspace without attaching.
+
+Examples:
+    rig log myapp                     Recent commits in ~/git/myapp
+    rig log myapp@polecat_emma        Recent commits in a crew worktree
+    rig log myapp@tracy --since="2 days ago"`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			path, err := resolveSessionPath(cfg, name)
+			if err != nil {
+				return err
+			}
+
+			commits, err := git.RecentCommitsSince(path, count, since)
+			if err != nil {
+				return err
+			}
+
+			branch, err := git.GetCurrentBranch(path)
+			if err != nil {
+				branch = "unknown"
+			}
+
+			fmt.Printf("📜 %s  🌿 %s\n\n", name, branch)
+
+			if len(commits) == 0 {
+				fmt.Println("  No commits found")
+				return nil
+			}
+
+			for _, commit := range commits {
+				fmt.Printf("  %s\n", commit)
+			}
+
+			return nil
+		},
 	}
 
-	cmd.AddCommand(crewAddCmd())
-	cmd.AddCommand(crewStartCmd())
-	cmd.AddCommand(crewRemoveCmd())
-	cmd.AddCommand(crewListCmd())
-	cmd.AddCommand(crewStatusCmd())
-	cmd.AddCommand(crewPruneCmd())
+	cmd.Flags().IntVarP(&count, "count", "n", 20, "Number of commits to show")
+	cmd.Flags().StringVar(&since, "since", "", "Only show commits newer than this date (e.g. \"2 weeks ago\", 2024-01-01)")
 
 	return cmd
 }
 
-func crewAddCmd() *cobra.Command {
+func diffCmd() *cobra.Command {
 	var rigName string
+	var full bool
 
 	cmd := &cobra.Command{
-		Use:   "add <name>",
-		Short: "Create crew workspace",
-		Args:  cobra.ExactArgs(1),
+		Use:   "diff <crew>",
+		Short: "Preview a crew member's changes against the base branch",
+		Long: `Preview a crew member's changes against the base branch.
+
+Shows a diffstat of everything on the crew's branch since it diverged from
+the base branch. Use --full for the complete patch.
+
+Examples:
+    rig diff polecat_emma             Diffstat for polecat_emma
+    rig diff tracy --rig=notes        Explicit rig
+    rig diff tracy --full             Full patch`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -447,51 +1424,509 @@ func crewAddCmd() *cobra.Command {
 				}
 			}
 
-			return crew.Add(cfg, name, rigName)
+			repoPath := cfg.GetRepoPath(rigName)
+			crewPath := crew.Locate(cfg, rigName, name)
+			if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+				return fmt.Errorf("crew workspace not found: %s", crewPath)
+			}
+
+			branch, err := git.GetCurrentBranch(crewPath)
+			if err != nil {
+				return fmt.Errorf("failed to get current branch: %w", err)
+			}
+			if branch == "" {
+				return fmt.Errorf("crew workspace %s is not on a branch (detached HEAD)", name)
+			}
+
+			baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+			if err != nil {
+				return err
+			}
+
+			var output string
+			if full {
+				output, err = git.Diff(repoPath, baseBranch, branch)
+			} else {
+				output, err = git.DiffStat(repoPath, baseBranch, branch)
+			}
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				fmt.Printf("No changes on %s relative to %s\n", branch, baseBranch)
+				return nil
+			}
+
+			fmt.Printf("🌿 %s...%s\n\n", baseBranch, branch)
+			fmt.Print(output)
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().BoolVar(&full, "full", false, "Show the complete patch instead of a diffstat")
 
 	return cmd
 }
 
-func crewStartCmd() *cobra.Command {
-	var rigName string
+func whichCmd() *cobra.Command {
+	var asJSON bool
 
 	cmd := &cobra.Command{
-		Use:   "start <name>",
-		Short: "Attach to crew workspace",
-		Args:  cobra.ExactArgs(1),
+		Use:   "which",
+		Short: "Show which rig and crew rig infers from here",
+		Long: `Show which rig and crew rig infers from the current directory or tmux
+session, along with the repo root and branch.
+
+Examples:
+    rig which           Print rig@crew, path and branch
+    rig which --json    Machine-readable output`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
+			info, err := currentContext(cfg)
+			if err != nil {
+				return err
+			}
 
-			// Infer rig if not provided
-			if rigName == "" {
-				var err error
-				rigName, err = crew.InferRig(cfg, rigName)
+			if asJSON {
+				data, err := json.MarshalIndent(info, "", "  ")
 				if err != nil {
 					return err
 				}
+				fmt.Println(string(data))
+				return nil
 			}
 
-			return crew.Start(cfg, name, rigName)
+			name := info.Rig
+			if info.Crew != "" {
+				name = cfg.GetCrewSessionName(info.Rig, info.Crew)
+			}
+			fmt.Println(name)
+			fmt.Printf("  path:   %s\n", info.Path)
+			if info.Branch != "" {
+				fmt.Printf("  branch: %s\n", info.Branch)
+			} else {
+				fmt.Println("  branch: (detached HEAD)")
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+
+	return cmd
+}
+
+// contextInfo is the rig context inferred from where rig is run
+type contextInfo struct {
+	Rig    string `json:"rig"`
+	Crew   string `json:"crew,omitempty"`
+	Path   string `json:"path"`
+	Branch string `json:"branch"`
+}
+
+// currentContext infers the rig, crew and repo root from the current
+// directory, falling back to the current tmux session
+func currentContext(cfg *config.Config) (contextInfo, error) {
+	rigName, err := crew.InferRig(cfg, "")
+	if err != nil {
+		return contextInfo{}, err
+	}
+	info := contextInfo{Rig: rigName}
+
+	if pwd, err := os.Getwd(); err == nil {
+		if root, err := git.GetRepoRoot(pwd); err == nil {
+			info.Path = root
+			// Crew worktrees live at <CrewBase>/<rig>/<name>; git reports
+			// root with symlinks resolved, so CrewBase needs them resolved too
+			if rel, err := filepath.Rel(resolvePath(cfg.CrewBase), resolvePath(root)); err == nil {
+				if parts := strings.Split(rel, string(filepath.Separator)); len(parts) == 2 && parts[0] == rigName {
+					info.Crew = parts[1]
+				}
+			}
+			// Crew added with --here record who they are
+			if info.Crew == "" {
+				if meta, err := crew.ReadMeta(root); err == nil && meta != nil && meta.Rig == rigName {
+					info.Crew = meta.Name
+				}
+			}
+		}
+	}
+
+	if info.Path == "" {
+		info.Path = cfg.GetRepoPath(rigName)
+		if rig, name, isCrew := config.ParseSessionName(tmux.GetCurrentSession()); isCrew && rig == rigName {
+			info.Crew = name
+			info.Path = crew.Locate(cfg, rigName, name)
+		}
+	}
+
+	branch, err := git.GetCurrentBranch(info.Path)
+	if err != nil {
+		return contextInfo{}, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	info.Branch = branch
+
+	return info, nil
+}
+
+func openCmd() *cobra.Command {
+	var useEditor bool
+
+	cmd := &cobra.Command{
+		Use:   "open <name>",
+		Short: "Open a rig or crew directory in the file manager or editor",
+		Long: `Open a rig's repo, or a crew workspace with <rig>@<crew>, with the
+system's opener (open on macOS, xdg-open elsewhere). Use --editor to open it
+in $EDITOR (or $VISUAL) instead.
+
+Examples:
+    rig open notes
+    rig open notes@tracy --editor`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := resolveSessionPath(cfg, args[0])
+			if err != nil {
+				return err
+			}
+
+			if useEditor {
+				editor := editorCommand(path)
+				if editor == nil {
+					return fmt.Errorf("no editor set\nSet $EDITOR or $VISUAL, or leave out --editor")
+				}
+				if err := runEditor(editor); err != nil {
+					return fmt.Errorf("failed to run editor: %w", err)
+				}
+				return nil
+			}
+
+			if err := runOpener(openerCommand(runtime.GOOS, path)); err != nil {
+				return fmt.Errorf("failed to open %s: %w", path, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&useEditor, "editor", false, "Open in $EDITOR instead of the system opener")
+
+	return cmd
+}
+
+// openerCommand returns the command that opens path with the system's
+// opener on goos
+func openerCommand(goos, path string) *exec.Cmd {
+	if goos == "darwin" {
+		return exec.Command("open", path)
+	}
+	return exec.Command("xdg-open", path)
+}
+
+// runOpener runs the system opener; replaced in tests
+var runOpener = func(cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
+
+func completionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish>",
+		Short: "Generate shell completion script",
+		Long: `Generate a shell completion script for rig.
+
+Examples:
+    source <(rig completion bash)
+    rig completion zsh > "${fpath[1]}/_rig"
+    rig completion fish > ~/.config/fish/completions/rig.fish`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return fmt.Errorf("unsupported shell: %s (expected bash, zsh, or fish)", args[0])
+			}
+		},
+	}
+}
+
+func versionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print rig version and build info",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("rig %s (commit %s, built %s)\n", version, commit, date)
+		},
+	}
+}
+
+func crewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crew",
+		Short: "Manage crew workspaces",
+	}
+
+	cmd.AddCommand(crewAddCmd())
+	cmd.AddCommand(crewStartCmd())
+	cmd.AddCommand(crewRecoverCmd())
+	cmd.AddCommand(crewRemoveCmd())
+	cmd.AddCommand(crewListCmd())
+	cmd.AddCommand(crewStatusCmd())
+	cmd.AddCommand(crewLogsCmd())
+	cmd.AddCommand(crewPruneCmd())
+	cmd.AddCommand(crewGcCmd())
+	cmd.AddCommand(crewMergeCmd())
+	cmd.AddCommand(crewPullCmd())
+	cmd.AddCommand(crewSetUpstreamCmd())
+	cmd.AddCommand(crewExportCmd())
+	cmd.AddCommand(crewImportCmd())
+
+	return cmd
+}
+
+func crewAddCmd() *cobra.Command {
+	var rigName string
+	var detached bool
+	var from string
+	var count int
+	var noSession bool
+	var base string
+	var fromRef string
+	var here string
+	var forceNewBranch bool
+	var layout string
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create crew workspace",
+		Long: `Create a crew workspace: a git worktree on <name>/work plus a tmux session.
+
+With --count N, creates N polecats with generated names instead, all
+detached:
+    rig crew add --count 3
+
+With --no-session, only the worktree is created; start a session later
+with 'rig crew start':
+    rig crew add tracy --no-session
+
+With --base, the crew branches off another local branch instead of the
+base branch:
+    rig crew add tracy --base feat/login
+
+With --from-ref, the crew branches off any tag or commit:
+    rig crew add tracy --from-ref v1.2.0
+    rig crew add tracy --from-ref 3f2c1ab
+
+With --here, the worktree is created at the given path instead of under
+the crew directory; the session is still named <rig>@<name>:
+    rig crew add tracy --here ../notes-tracy
+
+With --force-new-branch, an existing <name>/work branch is deleted, after
+confirmation, and the crew starts over from the base branch:
+    rig crew add tracy --force-new-branch
+
+With --layout, the session uses the native (window per pane) or cc
+(iTerm2 control mode) layout instead of the configured one. The crew keeps
+it for later sessions:
+    rig crew add tracy --layout cc`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if count > 0 {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			if count > 0 {
+				if from != "" {
+					return fmt.Errorf("--from can't be combined with --count")
+				}
+				if base != "" {
+					return fmt.Errorf("--base can't be combined with --count")
+				}
+				if fromRef != "" {
+					return fmt.Errorf("--from-ref can't be combined with --count")
+				}
+				if noSession {
+					return fmt.Errorf("--no-session can't be combined with --count")
+				}
+				if here != "" {
+					return fmt.Errorf("--here can't be combined with --count")
+				}
+				if forceNewBranch {
+					return fmt.Errorf("--force-new-branch can't be combined with --count")
+				}
+				if layout != "" {
+					return fmt.Errorf("--layout can't be combined with --count")
+				}
+				created, err := crew.Spawn(cfg, rigName, count)
+				ui.Println()
+				for _, name := range created {
+					if ui.Quiet {
+						fmt.Println(cfg.GetCrewSessionName(rigName, name))
+					} else {
+						fmt.Printf("✓ %s\n", cfg.GetCrewSessionName(rigName, name))
+					}
+				}
+				return err
+			}
+
+			if from != "" && base != "" {
+				return fmt.Errorf("--from can't be combined with --base")
+			}
+			if fromRef != "" && (from != "" || base != "") {
+				return fmt.Errorf("--from-ref can't be combined with --from or --base")
+			}
+
+			name := args[0]
+			return crew.Add(cfg, name, rigName, crew.AddOptions{
+				Detached:       detached,
+				From:           from,
+				NoSession:      noSession,
+				Base:           base,
+				FromRef:        fromRef,
+				Here:           here,
+				ForceNewBranch: forceNewBranch,
+				Layout:         layout,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.Flags().BoolVarP(&detached, "detached", "d", false, "Create the session without attaching to it")
+	cmd.Flags().StringVar(&from, "from", "", "Branch off another crew member's branch instead of the base branch")
+	cmd.Flags().IntVarP(&count, "count", "n", 0, "Create this many polecats with generated names (detached)")
+	cmd.Flags().BoolVar(&noSession, "no-session", false, "Create only the worktree, without a tmux session")
+	cmd.Flags().StringVar(&base, "base", "", "Branch off this local branch instead of the base branch")
+	cmd.Flags().StringVar(&fromRef, "from-ref", "", "Branch off a tag or commit instead of the base branch")
+	cmd.Flags().StringVar(&here, "here", "", "Create the worktree at this path instead of under the crew directory")
+	cmd.Flags().BoolVar(&forceNewBranch, "force-new-branch", false, "Delete an existing <name>/work branch and start over from the base branch")
+	cmd.Flags().StringVar(&layout, "layout", "", "Session layout for this crew (native or cc) instead of the configured one")
+	cmd.RegisterFlagCompletionFunc("layout", completeLayouts)
+	cmd.RegisterFlagCompletionFunc("from", completeCrewFlag)
+	cmd.RegisterFlagCompletionFunc("base", completeBranchFlag)
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+
+	return cmd
+}
+
+func crewStartCmd() *cobra.Command {
+	var rigName string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "start <name>",
+		Short: "Attach to crew workspace",
+		Long: `Attach to a crew workspace, recreating its tmux session if needed.
+
+With --all, recreates the sessions of every stopped crew member of the rig
+without attaching (e.g. after a reboot):
+    rig crew start --all`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			if all {
+				started, err := crew.StartAll(cfg, rigName)
+				if len(started) == 0 && err == nil {
+					ui.Printf("No stopped crew on %s\n", rigName)
+				}
+				return err
+			}
+
+			return crew.Start(cfg, args[0], rigName)
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.Flags().BoolVar(&all, "all", false, "Start sessions for all stopped crew of the rig, detached")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+
+	return cmd
+}
+
+func crewRecoverCmd() *cobra.Command {
+	var rigName string
+
+	cmd := &cobra.Command{
+		Use:   "recover <name>",
+		Short: "Rebuild a crew session from its existing worktree",
+		Long: `Rebuild a crew member's tmux session from its existing worktree and attach.
+
+Use this after the tmux server has died: the worktree is used as-is, on
+whatever branch it's on, and a session whose panes have all exited is
+replaced.
+
+Examples:
+    rig crew recover tracy               Rebuild and attach to tracy's session
+    rig crew recover tracy --rig=notes   Explicit rig`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			return crew.Recover(cfg, args[0], rigName)
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
 
 	return cmd
 }
 
 func crewRemoveCmd() *cobra.Command {
 	var rigName string
+	var force bool
+	var push bool
 
 	cmd := &cobra.Command{
-		Use:     "remove <name>",
-		Aliases: []string{"rm"},
-		Short:   "Remove crew workspace",
-		Args:    cobra.ExactArgs(1),
+		Use:               "remove <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Remove crew workspace",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -504,11 +1939,14 @@ func crewRemoveCmd() *cobra.Command {
 				}
 			}
 
-			return crew.Remove(cfg, name, rigName)
+			return crew.Remove(cfg, name, rigName, crew.RemoveOptions{Force: force, Push: push})
 		},
 	}
 
 	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.Flags().BoolVar(&force, "force", false, "Remove the workspace even if it is locked or has uncommitted changes")
+	cmd.Flags().BoolVar(&push, "push", false, "Push the crew's branch to origin first; abort if the push fails")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
 
 	return cmd
 }
@@ -525,75 +1963,73 @@ func crewListCmd() *cobra.Command {
 				filterName = args[0]
 			}
 
-			if _, err := os.Stat(cfg.CrewBase); os.IsNotExist(err) {
-				fmt.Printf("No crew workspaces (directory doesn't exist: %s)\n", cfg.CrewBase)
-				return nil
-			}
-
 			// Build map of rigs to their crew members
 			type CrewMember struct {
-				Name   string
-				Branch string
-				Status string
+				crew.CrewInfo
+				Display  string // branch, or "(detached)" or "unknown"
+				Status   string
+				Mismatch bool
+				Created  string
+				Activity string
 			}
 			rigCrew := make(map[string][]CrewMember)
 
-			repoDirs, err := os.ReadDir(cfg.CrewBase)
+			workspaces, err := crew.Discover(cfg)
 			if err != nil {
 				return err
 			}
 
-			for _, repoDir := range repoDirs {
-				if !repoDir.IsDir() {
-					continue
-				}
-
-				rigName := repoDir.Name()
-				repoPath := filepath.Join(cfg.CrewBase, rigName)
+			// One worktree listing per rig gives branch and dirty state for all crew
+			rigWorktrees := make(map[string]map[string]git.WorktreeInfo)
 
-				workspaces, err := os.ReadDir(repoPath)
-				if err != nil {
+			for _, info := range workspaces {
+				// Filter by name if provided
+				if filterName != "" && info.Name != filterName {
 					continue
 				}
 
-				for _, workspace := range workspaces {
-					if !workspace.IsDir() {
-						continue
-					}
-
-					crewName := workspace.Name()
-
-					// Filter by name if provided
-					if filterName != "" && crewName != filterName {
-						continue
-					}
-
-					crewPath := filepath.Join(repoPath, crewName)
-					sessionName := cfg.GetCrewSessionName(rigName, crewName)
-
-					// Get branch
-					branch, err := git.GetCurrentBranch(crewPath)
-					if err != nil {
-						branch = "unknown"
+				worktrees, ok := rigWorktrees[info.Rig]
+				if !ok {
+					worktrees = make(map[string]git.WorktreeInfo)
+					if infos, err := git.WorktreeStatus(cfg.GetRepoPath(info.Rig)); err == nil {
+						for _, wt := range infos {
+							worktrees[resolvePath(wt.Path)] = wt
+						}
 					}
+					rigWorktrees[info.Rig] = worktrees
+				}
 
-					// Get status
-					status := "stopped"
-					if tmux.SessionExists(sessionName) {
-						status = "running"
+				member := CrewMember{
+					CrewInfo: info,
+					Display:  "unknown",
+					Status:   "stopped",
+					Created:  crewCreated(info.Path),
+					Activity: crewActivity(info.Path),
+				}
+				dirty := false
+				if wt, ok := worktrees[resolvePath(info.Path)]; ok {
+					member.Display = wt.Branch
+					if wt.Detached {
+						member.Display = "(detached)"
 					}
-
-					rigCrew[rigName] = append(rigCrew[rigName], CrewMember{
-						Name:   crewName,
-						Branch: branch,
-						Status: status,
-					})
+					dirty = wt.Dirty
+					member.Mismatch = member.Display != cfg.GetCrewBranchName(info.Name)
 				}
+				if info.Running {
+					member.Status = "running"
+				}
+				if dirty {
+					member.Status += ", dirty"
+				}
+
+				rigCrew[info.Rig] = append(rigCrew[info.Rig], member)
 			}
 
 			if len(rigCrew) == 0 {
 				if filterName != "" {
 					fmt.Printf("No workspaces found for: %s\n", filterName)
+				} else if _, err := os.Stat(cfg.CrewBase); os.IsNotExist(err) {
+					fmt.Printf("No crew workspaces yet (%s doesn't exist)\n", condensePath(cfg.CrewBase))
 				} else {
 					fmt.Println("No crew workspaces found")
 				}
@@ -603,20 +2039,43 @@ func crewListCmd() *cobra.Command {
 			}
 
 			// Display by rig
-			for rigName, crew := range rigCrew {
+			anyMismatch := false
+			for _, rigName := range sortedKeys(rigCrew) {
+				crew := rigCrew[rigName]
+				sort.Slice(crew, func(i, j int) bool { return crew[i].Name < crew[j].Name })
 				fmt.Printf("🏗️  %s\n", rigName)
 
 				for _, member := range crew {
 					emoji := "👤"
-					if polecat.IsPolecat(member.Name) {
+					if member.IsPolecat {
 						emoji = "🐱"
 					}
 
-					fmt.Printf("  %s %-18s %-26s [%s]\n", emoji, member.Name, member.Branch, member.Status)
+					branch := member.Display
+					if member.Mismatch {
+						branch += " ⚠"
+						anyMismatch = true
+					}
+
+					var ages []string
+					for _, age := range []string{member.Created, member.Activity} {
+						if age != "" {
+							ages = append(ages, age)
+						}
+					}
+					details := ""
+					if len(ages) > 0 {
+						details = " " + strings.Join(ages, ", ")
+					}
+					fmt.Printf("  %s %-18s %-26s [%s]%s\n", emoji, member.Name, branch, colorCrewStatus(member.Status), details)
 				}
 				fmt.Println()
 			}
 
+			if anyMismatch {
+				fmt.Println("⚠ = not on its crew branch (<name>/work)")
+			}
+
 			return nil
 		},
 	}
@@ -630,48 +2089,44 @@ func crewStatusCmd() *cobra.Command {
 			fmt.Println("👥 Active Crew Sessions")
 			fmt.Println()
 
-			sessions, err := tmux.ListSessions()
-			if err != nil || len(sessions) == 0 {
-				fmt.Println("  No active crew sessions")
-				return nil
+			running, err := tmux.LoadSessionSet()
+			if err != nil {
+				return err
+			}
+			workspaces, err := crew.DiscoverWith(cfg, running)
+			if err != nil {
+				return err
 			}
 
-			var crewSessions []string
-			for _, session := range sessions {
-				if strings.Contains(session, "@") {
-					parts := strings.Split(session, "@")
-					rigPart, namePart := parts[0], parts[1]
-					crewPath := cfg.GetCrewPath(rigPart, namePart)
-
-					if _, err := os.Stat(crewPath); err == nil {
-						crewSessions = append(crewSessions, session)
-					}
+			var active []crew.CrewInfo
+			for _, info := range workspaces {
+				if info.Running {
+					active = append(active, info)
 				}
 			}
 
-			if len(crewSessions) == 0 {
+			if len(active) == 0 {
 				fmt.Println("  No active crew sessions")
 				return nil
 			}
 
-			for _, session := range crewSessions {
-				parts := strings.Split(session, "@")
-				rigPart, namePart := parts[0], parts[1]
-				crewPath := cfg.GetCrewPath(rigPart, namePart)
-
+			for _, info := range active {
 				emoji := "👤"
-				if polecat.IsPolecat(namePart) {
+				if info.IsPolecat {
 					emoji = "🐱"
 				}
 
-				branch, err := git.GetCurrentBranch(crewPath)
-				if err != nil {
+				branch := info.Branch
+				if branch == "" {
 					branch = "unknown"
 				}
 
-				fmt.Printf("  %s %s\n", emoji, session)
-				fmt.Printf("      %s\n", crewPath)
+				fmt.Printf("  %s %s\n", emoji, info.SessionName)
+				fmt.Printf("      %s\n", info.Path)
 				fmt.Printf("      %s\n", branch)
+				if created := crewCreated(info.Path); created != "" {
+					fmt.Printf("      %s\n", created)
+				}
 				fmt.Println()
 			}
 
@@ -682,69 +2137,81 @@ func crewStatusCmd() *cobra.Command {
 
 func crewPruneCmd() *cobra.Command {
 	var polecatsOnly bool
+	var all bool
+	var stale string
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "prune",
 		Short: "Remove crew workspaces (with --polecats flag, removes only polecats)",
+		Long: `Remove crew workspaces.
+
+By default, removes all polecats. With --all, removes named crew too. With
+--stale, removes any crew member whose last commit, or creation if that's
+later, is older than the given age instead (e.g. 36h or 7d); add --polecats
+to limit that to polecats.
+
+Examples:
+    rig crew prune --dry-run           # List polecats that would be removed
+    rig crew prune --all               # Remove every crew workspace
+    rig crew prune --stale 7d          # Remove crew idle for a week
+    rig crew prune --stale 7d --polecats`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Find polecats across all rigs
-			type PolecatInfo struct {
-				Name    string
-				RigName string
-				Path    string
+			if polecatsOnly && all {
+				return fmt.Errorf("--polecats can't be combined with --all")
 			}
 
-			polecats := []PolecatInfo{}
-
-			if _, err := os.Stat(cfg.CrewBase); os.IsNotExist(err) {
-				fmt.Println("No crew workspaces found")
-				return nil
+			opts := crew.PruneOptions{All: all, Polecats: polecatsOnly}
+			if stale != "" {
+				maxAge, err := parseAge(stale)
+				if err != nil {
+					return err
+				}
+				if maxAge <= 0 {
+					return fmt.Errorf("--stale must be greater than zero")
+				}
+				opts.Stale = maxAge
 			}
 
-			rigDirs, err := os.ReadDir(cfg.CrewBase)
+			candidates, err := crew.PruneCandidates(cfg, opts)
 			if err != nil {
-				return fmt.Errorf("failed to read crew directory: %w", err)
+				return err
 			}
 
-			for _, rigDir := range rigDirs {
-				if !rigDir.IsDir() {
-					continue
+			if len(candidates) == 0 {
+				switch {
+				case stale != "":
+					fmt.Printf("No crew without commits in the last %s\n", stale)
+				case all:
+					fmt.Println("No crew workspaces found")
+				default:
+					fmt.Println("No polecats found")
 				}
+				return nil
+			}
 
-				rigName := rigDir.Name()
-				rigPath := filepath.Join(cfg.CrewBase, rigName)
-
-				crewDirs, err := os.ReadDir(rigPath)
-				if err != nil {
-					continue
+			// Display found crew
+			if dryRun {
+				fmt.Printf("Would remove %d workspace(s):\n", len(candidates))
+			} else {
+				fmt.Printf("Found %d workspace(s):\n", len(candidates))
+			}
+			for _, c := range candidates {
+				emoji := "👤"
+				if polecat.IsPolecat(c.Name) {
+					emoji = "🐱"
 				}
-
-				for _, crewDir := range crewDirs {
-					if !crewDir.IsDir() {
-						continue
-					}
-
-					crewName := crewDir.Name()
-					if polecat.IsPolecat(crewName) {
-						polecats = append(polecats, PolecatInfo{
-							Name:    crewName,
-							RigName: rigName,
-							Path:    filepath.Join(rigPath, crewName),
-						})
-					}
+				if c.Orphaned {
+					fmt.Printf("  - %s %s (rig: %s, session only)\n", emoji, c.Name, c.RigName)
+				} else if !c.LastActive.IsZero() {
+					fmt.Printf("  - %s %s (rig: %s, last active %s)\n", emoji, c.Name, c.RigName, formatAge(time.Since(c.LastActive)))
+				} else {
+					fmt.Printf("  - %s %s (rig: %s)\n", emoji, c.Name, c.RigName)
 				}
 			}
-
-			if len(polecats) == 0 {
-				fmt.Println("No polecats found")
+			if dryRun {
 				return nil
 			}
-
-			// Display found polecats
-			fmt.Printf("Found %d polecat(s):\n", len(polecats))
-			for _, p := range polecats {
-				fmt.Printf("  - 🐱 %s (rig: %s)\n", p.Name, p.RigName)
-			}
 			fmt.Println()
 
 			// Confirm removal
@@ -756,42 +2223,513 @@ func crewPruneCmd() *cobra.Command {
 				return nil
 			}
 
-			// Remove each polecat
-			for _, p := range polecats {
-				fmt.Printf("Removing 🐱 %s...\n", p.Name)
+			// Remove each workspace
+			removedCount := 0
+			var failures []string
+			for _, c := range candidates {
+				// Pruning respects `git worktree lock` and never discards
+				// uncommitted work
+				if !c.Orphaned && git.IsWorktreeLocked(cfg.GetRepoPath(c.RigName), c.Path) {
+					ui.Warnf("Skipping %s: it is locked (unlock it with 'git worktree unlock' or use 'rig crew remove %s --rig=%s --force')", c.Name, c.Name, c.RigName)
+					continue
+				}
+				if !c.Orphaned && git.IsDirty(c.Path) {
+					ui.Warnf("Skipping %s: it has uncommitted changes (use 'rig crew remove %s --rig=%s' to remove it anyway)", c.Name, c.Name, c.RigName)
+					continue
+				}
+
+				ui.Printf("Removing %s...\n", c.Name)
 
 				// Get repo path
-				repoPath := cfg.GetRepoPath(p.RigName)
-				sessionName := cfg.GetCrewSessionName(p.RigName, p.Name)
+				repoPath := cfg.GetRepoPath(c.RigName)
+				sessionName := c.Session
 
 				// Kill session if running
 				if tmux.SessionExists(sessionName) {
-					tmux.KillSession(sessionName)
-					fmt.Printf("  ✓ Killed session: %s\n", sessionName)
+					if err := killSession(sessionName); err != nil {
+						// Keep the worktree so the session isn't left without one
+						failures = append(failures, fmt.Sprintf("%s: %v", sessionName, err))
+						continue
+					}
+					ui.Printf("  ✓ Killed session: %s\n", sessionName)
+				}
+				if c.Orphaned {
+					removedCount++
+					continue
 				}
 
 				// Remove worktree
-				if _, err := os.Stat(p.Path); err == nil {
-					git.RemoveWorktree(repoPath, p.Path)
-					fmt.Printf("  ✓ Removed worktree: %s\n", p.Path)
+				if _, err := os.Stat(c.Path); err == nil {
+					if err := git.RemoveWorktree(repoPath, c.Path); err != nil {
+						failures = append(failures, fmt.Sprintf("%s: failed to remove worktree: %v", c.Name, err))
+						continue
+					}
+					ui.Printf("  ✓ Removed worktree: %s\n", c.Path)
 				}
 
 				// Prune stale worktree metadata
 				git.PruneWorktrees(repoPath)
 
 				// Remove empty rig directory if needed
-				rigDir := filepath.Dir(p.Path)
-				if entries, err := os.ReadDir(rigDir); err == nil && len(entries) == 0 {
+				rigDir := filepath.Dir(c.Path)
+				if entries, err := os.ReadDir(rigDir); err == nil && len(entries) == 0 && rigDir == filepath.Join(cfg.CrewBase, c.RigName) {
 					os.Remove(rigDir)
 				}
+				removedCount++
+			}
+
+			if len(failures) > 0 {
+				return fmt.Errorf("removed %d, failed %d (%s)", removedCount, len(failures), strings.Join(failures, "; "))
 			}
 
-			fmt.Printf("\n✓ Removed %d polecat(s)\n", len(polecats))
+			ui.Printf("\n✓ Removed %d workspace(s)\n", removedCount)
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&polecatsOnly, "polecats", false, "Remove only polecats (default behavior)")
+	cmd.Flags().BoolVar(&all, "all", false, "Include named crew, not just polecats")
+	cmd.Flags().StringVar(&stale, "stale", "", "Remove crew whose last commit is older than this (e.g. 36h, 7d)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be removed without removing anything")
+
+	return cmd
+}
+
+func crewGcCmd() *cobra.Command {
+	var rigName string
+	var deleteBranches bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete crew branches whose worktrees are gone",
+		Long: `Find crew branches (<name>/work) that no worktree has checked out, e.g.
+after a worktree was removed by hand. Lists them by default, with how many
+commits each has that its rig's base branch doesn't; use --delete to delete
+them. Branches with such commits are only deleted after confirming. Checks
+every rig unless --rig is given.
+
+Examples:
+    rig crew gc                        # List orphaned crew branches
+    rig crew gc --rig notes --delete   # Delete them on notes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rigNames := listRepoNames(cfg)
+			if rigName != "" {
+				if _, err := resolveRepoPath(cfg, rigName); err != nil {
+					return err
+				}
+				rigNames = []string{rigName}
+			}
+
+			type orphan struct {
+				Branch string
+				Ahead  int // commits not on the base branch; -1 if unknown
+			}
+			orphans := make(map[string][]orphan)
+			count := 0
+			unmerged := 0
+			for _, name := range rigNames {
+				branches, err := crew.OrphanBranches(cfg, name)
+				if err != nil {
+					return fmt.Errorf("failed to check %s: %w", name, err)
+				}
+				repoPath := cfg.GetRepoPath(name)
+				baseBranch, baseErr := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+				for _, branch := range branches {
+					o := orphan{Branch: branch, Ahead: -1}
+					if baseErr == nil {
+						if ahead, err := git.CommitsAhead(repoPath, baseBranch, branch); err == nil {
+							o.Ahead = ahead
+						}
+					}
+					if o.Ahead != 0 {
+						unmerged++
+					}
+					orphans[name] = append(orphans[name], o)
+					count++
+				}
+			}
+
+			if count == 0 {
+				fmt.Println("No orphaned crew branches")
+				return nil
+			}
+
+			if deleteBranches {
+				fmt.Printf("Deleting %d branch(es):\n", count)
+			} else {
+				fmt.Printf("Would delete %d branch(es):\n", count)
+			}
+			for _, name := range sortedKeys(orphans) {
+				for _, o := range orphans[name] {
+					switch {
+					case o.Ahead > 0:
+						fmt.Printf("  - %s (rig: %s, %d commit(s) not on the base branch)\n", o.Branch, name, o.Ahead)
+					case o.Ahead < 0:
+						fmt.Printf("  - %s (rig: %s, unknown if merged)\n", o.Branch, name)
+					default:
+						fmt.Printf("  - %s (rig: %s)\n", o.Branch, name)
+					}
+				}
+			}
+
+			// Commits only on an orphaned branch would be lost, so ask first
+			deleteUnmerged := false
+			if deleteBranches && unmerged > 0 {
+				fmt.Println()
+				fmt.Printf("%d branch(es) have commits that would be lost. Delete them too? (y/N) ", unmerged)
+				var response string
+				fmt.Scanln(&response)
+				deleteUnmerged = strings.ToLower(response) == "y"
+			}
+
+			var failures []string
+			deleted := 0
+			if deleteBranches {
+				for _, name := range sortedKeys(orphans) {
+					for _, o := range orphans[name] {
+						if o.Ahead != 0 && !deleteUnmerged {
+							continue
+						}
+						if err := git.DeleteBranch(cfg.GetRepoPath(name), o.Branch); err != nil {
+							failures = append(failures, fmt.Sprintf("%s on %s: %v", o.Branch, name, err))
+							continue
+						}
+						deleted++
+					}
+				}
+			}
+
+			if !deleteBranches {
+				fmt.Println()
+				if rigName != "" {
+					fmt.Printf("Run 'rig crew gc --rig %s --delete' to delete them\n", rigName)
+				} else {
+					fmt.Println("Run 'rig crew gc --delete' to delete them")
+				}
+				return nil
+			}
+			if len(failures) > 0 {
+				return fmt.Errorf("deleted %d, failed %d (%s)", deleted, len(failures), strings.Join(failures, "; "))
+			}
+
+			ui.Printf("\n✓ Deleted %d branch(es)\n", deleted)
+			if kept := count - deleted; kept > 0 {
+				ui.Printf("Kept %d branch(es) with unmerged commits\n", kept)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Only check this rig")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().BoolVar(&deleteBranches, "delete", false, "Delete the branches instead of listing them")
+
+	return cmd
+}
+
+func crewLogsCmd() *cobra.Command {
+	var rigName string
+	var pane string
+	var lines int
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "logs <name>",
+		Short: "Show a crew session's pane output",
+		Long: `Show the recent output of a crew session's Claude Code pane.
+
+With --follow, keeps capturing the pane and prints new output as it
+appears, like tail -f, until interrupted.
+
+Examples:
+    rig crew logs polecat_emma
+    rig crew logs polecat_emma --follow
+    rig crew logs tracy --pane terminal --lines 50`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			sessionName := cfg.GetCrewSessionName(rigName, name)
+			if !tmux.SessionExists(sessionName) {
+				return fmt.Errorf("session not found: %s\nStart it with: rig crew start %s", sessionName, name)
+			}
+			return showPane(sessionName, pane, lines, follow)
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().StringVar(&pane, "pane", tmux.PaneClaude, "Pane to show (claude or terminal)")
+	cmd.RegisterFlagCompletionFunc("pane", completePaneNames)
+	cmd.Flags().IntVarP(&lines, "lines", "n", 20, "Number of lines to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new output until interrupted")
+
+	return cmd
+}
+
+// followPane captures the target pane on every interval and prints whatever
+// is new since the last capture, until interrupted or the session ends
+func followPane(sessionName, target, last string, interval time.Duration) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigs:
+			return nil
+		case <-ticker.C:
+		}
+
+		if !tmux.SessionExists(sessionName) {
+			ui.Printf("Session %s ended\n", sessionName)
+			return nil
+		}
+
+		output, err := tmux.CapturePane(target)
+		if err != nil {
+			return err
+		}
+		if added := newLines(last, output); added != "" {
+			fmt.Println(added)
+		}
+		last = output
+	}
+}
+
+func crewExportCmd() *cobra.Command {
+	var rigName string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a crew's commits as patches",
+		Long: `Export a crew's commits as patches.
+
+Runs git format-patch for every commit on the crew's branch since the base
+branch. Patches go into a directory (default: <name>-patches), or into a
+single mbox file when --output ends in .patch.
+
+Examples:
+    rig crew export tracy                        Write tracy-patches/*.patch
+    rig crew export tracy --output=out           Write out/*.patch
+    rig crew export tracy --output=tracy.patch   Write a single file`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			files, err := crew.Export(cfg, name, rigName, output)
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				fmt.Println(file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output directory, or a .patch file for a single mbox")
+
+	return cmd
+}
+
+func crewImportCmd() *cobra.Command {
+	var rigName string
+	var patchFile string
+
+	cmd := &cobra.Command{
+		Use:   "import [name] --patch <file>",
+		Short: "Create a crew workspace from a patch",
+		Long: `Create a crew workspace from a patch.
+
+Creates a new crew worktree off the base branch and applies the patch with
+git am, one commit per patch. With no name a polecat name is generated. If
+the patch doesn't apply, the new workspace is removed again.
+
+Examples:
+    rig crew import --patch tracy.patch          Import into a new polecat
+    rig crew import tracy --patch tracy.patch    Import into a new crew named tracy`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if patchFile == "" {
+				return fmt.Errorf("--patch is required")
+			}
+
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err := crew.Import(cfg, name, rigName, patchFile)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().StringVar(&patchFile, "patch", "", "Patch file to apply (as written by crew export)")
+
+	return cmd
+}
+
+func crewMergeCmd() *cobra.Command {
+	var rigName string
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <name>",
+		Short: "Merge a crew branch back into the base branch",
+		Long: `Merge a crew branch back into the base branch.
+
+Checks out the base branch in the main repo and merges the crew's branch.
+If the merge conflicts it is aborted, leaving the repo unchanged, and the
+conflicting files are listed.
+
+Examples:
+    rig crew merge polecat_emma              Merge polecat_emma's branch
+    rig crew merge tracy --remove            Merge, then remove the workspace`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			return crew.Merge(cfg, name, rigName, remove)
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the crew workspace after a clean merge")
+
+	return cmd
+}
+
+func crewPullCmd() *cobra.Command {
+	var rigName string
+	var useMerge bool
+
+	cmd := &cobra.Command{
+		Use:   "pull <name>",
+		Short: "Update a crew branch from the base branch",
+		Long: `Update a crew branch from the base branch.
+
+Rebases the crew's branch onto the latest base branch (fetching first if
+the repo has a remote). Use --merge to merge the base branch in instead.
+If there are conflicts the operation is aborted and the files are listed.
+
+Examples:
+    rig crew pull tracy               Rebase tracy's branch onto base
+    rig crew pull tracy --merge       Merge base into tracy's branch`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			return crew.Pull(cfg, name, rigName, useMerge)
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+	cmd.Flags().BoolVar(&useMerge, "merge", false, "Merge the base branch instead of rebasing")
+
+	return cmd
+}
+
+func crewSetUpstreamCmd() *cobra.Command {
+	var rigName string
+
+	cmd := &cobra.Command{
+		Use:   "set-upstream <name>",
+		Short: "Track a crew branch on origin",
+		Long: `Make a crew's branch track the branch of the same name on origin.
+
+If origin already has the branch, it's set as the upstream. Otherwise the
+branch is pushed with -u.
+
+Examples:
+    rig crew set-upstream tracy               Track origin/<tracy's branch>
+    rig crew set-upstream tracy --rig=notes   Explicit rig`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCrewNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Infer rig if not provided
+			if rigName == "" {
+				var err error
+				rigName, err = crew.InferRig(cfg, rigName)
+				if err != nil {
+					return err
+				}
+			}
+
+			upstream, err := crew.SetUpstream(cfg, name, rigName)
+			if err != nil {
+				return err
+			}
+			ui.Printf("✓ %s now tracks %s\n", name, upstream)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rigName, "rig", "", "Explicit rig name")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
 
 	return cmd
 }
@@ -804,15 +2742,30 @@ func workCmd() *cobra.Command {
 
 	cmd.AddCommand(workCreateCmd())
 	cmd.AddCommand(workStatusCmd())
+	cmd.AddCommand(workArchiveCmd())
 
 	return cmd
 }
 
 func workCreateCmd() *cobra.Command {
-	return &cobra.Command{
+	var open bool
+	var noCommit bool
+
+	cmd := &cobra.Command{
 		Use:   "create <name>",
 		Short: "Create a new work directory with feature branch",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create a new work directory with feature branch.
+
+Creates work/<name>/ with a spec and progress file, commits it on a new
+feat/<name> branch and leaves you on that branch. With --open, the spec is
+opened in $EDITOR (or $VISUAL) afterwards. With --no-commit, the files are
+left uncommitted for you to review and commit yourself.
+
+Examples:
+    rig work create add-auth
+    rig work create add-auth --open
+    rig work create add-auth --no-commit`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			workName := args[0]
 
@@ -822,24 +2775,25 @@ func workCreateCmd() *cobra.Command {
 				return fmt.Errorf("failed to get current directory: %w", err)
 			}
 
-			repoPath, err := git.GetRepoRoot(pwd)
+			repo, err := git.OpenRepo(pwd)
 			if err != nil {
 				return fmt.Errorf("not in a git repository: %w", err)
 			}
+			repoPath := repo.Root
 
 			// Check if work directory already exists
 			workPath := work.GetWorkPath(repoPath, workName)
 			workExists := false
 			if _, err := os.Stat(workPath); err == nil {
 				workExists = true
-				fmt.Printf("⚠️  Warning: work/%s/ already exists\n", workName)
+				ui.Warnf("work/%s/ already exists", workName)
 			}
 
 			// Check if feature branch already exists
 			featureBranch := "feat/" + workName
-			branchExists := git.BranchExists(repoPath, featureBranch)
+			branchExists := repo.BranchExists(featureBranch)
 			if branchExists {
-				fmt.Printf("⚠️  Warning: Branch %s already exists\n", featureBranch)
+				ui.Warnf("Branch %s already exists", featureBranch)
 			}
 
 			// Create work directory and files
@@ -848,257 +2802,791 @@ func workCreateCmd() *cobra.Command {
 			}
 
 			if workExists {
-				fmt.Println("✓ Skipped existing files, created missing ones")
+				ui.Println("✓ Skipped existing files, created missing ones")
 			} else {
-				fmt.Printf("✓ Created work directory: work/%s/\n", workName)
+				ui.Printf("✓ Created work directory: work/%s/\n", workName)
 			}
 
 			// Get base branch
-			baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+			baseBranch, err := repo.BaseBranch(cfg.DefaultBranch)
 			if err != nil {
 				return err
 			}
 
 			// Create feature branch if it doesn't exist
 			if !branchExists {
-				if err := git.CreateFeatureBranch(repoPath, featureBranch, baseBranch); err != nil {
+				if err := repo.CreateFeatureBranch(featureBranch, baseBranch); err != nil {
 					return fmt.Errorf("failed to create feature branch: %w", err)
 				}
-				fmt.Printf("✓ Created feature branch: %s\n", featureBranch)
+				ui.Printf("✓ Created feature branch: %s\n", featureBranch)
 			} else {
 				// Checkout existing branch
-				if err := git.CheckoutBranch(repoPath, featureBranch); err != nil {
+				if err := repo.CheckoutBranch(featureBranch); err != nil {
 					return fmt.Errorf("failed to checkout branch: %w", err)
 				}
-				fmt.Printf("✓ Using existing branch: %s\n", featureBranch)
+				ui.Printf("✓ Using existing branch: %s\n", featureBranch)
 			}
 
 			// Check if formula was installed
 			formulaPath := work.GetFormulaPath(repoPath, "build")
 			if _, err := os.Stat(formulaPath); err == nil {
-				fmt.Println("✓ Ensured formula directory exists: work/formula/")
+				ui.Println("✓ Ensured formula directory exists: work/formula/")
 			}
 
 			// Create initial commit if work directory was newly created
-			if !workExists {
+			if !workExists && noCommit {
+				ui.Printf("✓ Left work/%s/ uncommitted; commit it when you're ready\n", workName)
+			} else if !workExists {
 				// Stage work directory
-				addCmd := exec.Command("git", "add", "work/"+workName+"/", "work/formula/")
-				addCmd.Dir = repoPath
-				if err := addCmd.Run(); err != nil {
-					fmt.Printf("⚠️  Warning: failed to stage files: %v\n", err)
+				if err := git.AddPaths(repoPath, "work/"+workName+"/", "work/formula/"); err != nil {
+					ui.Warnf("%v", err)
 				} else {
 					// Create commit
 					commitMsg := fmt.Sprintf("Initialize work: %s", workName)
-					commitCmd := exec.Command("git", "commit", "-m", commitMsg)
-					commitCmd.Dir = repoPath
-					if err := commitCmd.Run(); err != nil {
-						fmt.Printf("⚠️  Warning: failed to create initial commit: %v\n", err)
+					if err := git.Commit(repoPath, commitMsg); err != nil {
+						ui.Warnf("Failed to create initial commit: %v", err)
 					} else {
-						fmt.Printf("✓ Initial commit: \"%s\"\n", commitMsg)
+						ui.Printf("✓ Initial commit: \"%s\"\n", commitMsg)
+					}
+				}
+			}
+
+			ui.Println()
+			ui.Println("Next steps:")
+			ui.Printf("  1. Edit work/%s/spec.md\n", workName)
+			ui.Printf("  2. When ready: rig sling work/%s\n", workName)
+			ui.Printf("\nYou are now on branch: %s\n", featureBranch)
+
+			if open {
+				specPath := filepath.Join(workPath, "spec.md")
+				editor := editorCommand(specPath)
+				if editor == nil {
+					fmt.Printf("No editor set ($EDITOR or $VISUAL), spec is at %s\n", specPath)
+					return nil
+				}
+				if err := runEditor(editor); err != nil {
+					return fmt.Errorf("failed to run editor: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&open, "open", false, "Open spec.md in $EDITOR afterwards")
+	cmd.Flags().BoolVar(&noCommit, "no-commit", false, "Leave the new work files uncommitted")
+
+	return cmd
+}
+
+// editorCommand returns the command that opens path in $EDITOR, or $VISUAL
+// if that's unset, or nil if neither is set
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// runEditor runs an editor until it exits; replaced in tests
+var runEditor = func(cmd *exec.Cmd) error { return cmd.Run() }
+
+func workArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "archive <name>",
+		Short:             "Move completed work to work/.archive/ and commit",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeWorkPaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workName := strings.TrimSuffix(strings.TrimPrefix(args[0], "work/"), "/")
+
+			pwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+
+			repoPath, err := git.GetRepoRoot(pwd)
+			if err != nil {
+				return fmt.Errorf("not in a git repository: %w", err)
+			}
+
+			if err := work.Archive(repoPath, workName); err != nil {
+				return err
+			}
+			archivePath := "work/" + work.ArchiveDir + "/" + workName
+			ui.Printf("✓ Moved work/%s/ to %s/\n", workName, archivePath)
+
+			// Stage both sides of the move; the old path may never have been tracked
+			if err := git.RemoveCached(repoPath, "work/"+workName); err != nil {
+				return fmt.Errorf("failed to stage archive: %w", err)
+			}
+			if err := git.AddPaths(repoPath, archivePath); err != nil {
+				return fmt.Errorf("failed to stage archive: %w", err)
+			}
+
+			commitMsg := fmt.Sprintf("Archive work: %s", workName)
+			if err := git.Commit(repoPath, commitMsg); err != nil {
+				ui.Warnf("Failed to commit archive: %v", err)
+			} else {
+				ui.Printf("✓ Committed: \"%s\"\n", commitMsg)
+			}
+
+			// Branches slung with --branch no longer map to the work
+			for _, branch := range git.BranchesWithConfig(repoPath, slungWorkKey, workName) {
+				if err := git.UnsetBranchConfig(repoPath, branch, slungWorkKey); err != nil {
+					ui.Warnf("%v", err)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func workStatusCmd() *cobra.Command {
+	var rigFilter string
+
+	cmd := &cobra.Command{
+		Use:               "status [rig]",
+		Short:             "Show all active work across all rigs",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepoNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if rigFilter != "" && rigFilter != args[0] {
+					return fmt.Errorf("conflicting rigs: %s and --rig=%s", args[0], rigFilter)
+				}
+				rigFilter = args[0]
+			}
+			if rigFilter != "" {
+				if err := config.ValidateRigName(rigFilter); err != nil {
+					return err
+				}
+			}
+
+			fmt.Println("💼 Active Work")
+			fmt.Println()
+
+			type WorkItem struct {
+				WorkName    string
+				Status      string
+				AssignedTo  string
+				Branch      string
+				CurrentTask string
+			}
+
+			rigWork := make(map[string][]WorkItem)
+
+			// addWork records the work a checkout's feature branch is on, if any
+			addWork := func(rigName, assignedTo, path string) {
+				// Get current branch
+				branch, err := git.GetCurrentBranch(path)
+				if err != nil {
+					return
+				}
+
+				// Check if it's a feature branch
+				workName := branchWork(path, branch)
+				if workName == "" {
+					return
+				}
+				for _, item := range rigWork[rigName] {
+					if item.WorkName == workName && item.Branch == branch {
+						return
+					}
+				}
+
+				// Try to read progress.md
+				progressPath := filepath.Join(path, "work", workName, "progress.md")
+				progress, err := work.ParseProgress(progressPath)
+				if err != nil {
+					// If progress.md doesn't exist or can't be parsed, show basic info
+					status := "Malformed progress"
+					if errors.Is(err, os.ErrNotExist) {
+						status = "No progress file"
+					}
+					rigWork[rigName] = append(rigWork[rigName], WorkItem{
+						WorkName:    workName,
+						Status:      status,
+						AssignedTo:  assignedTo,
+						Branch:      branch,
+						CurrentTask: "",
+					})
+					return
+				}
+
+				// Add work item with full details
+				rigWork[rigName] = append(rigWork[rigName], WorkItem{
+					WorkName:    workName,
+					Status:      progress.Status,
+					AssignedTo:  assignedTo,
+					Branch:      branch,
+					CurrentTask: progress.GetCurrentTask(),
+				})
+			}
+
+			// Scan all crew
+			workspaces, err := crew.Discover(cfg)
+			if err != nil {
+				return err
+			}
+			for _, info := range workspaces {
+				if rigFilter == "" || info.Rig == rigFilter {
+					addWork(info.Rig, info.Name, info.Path)
+				}
+			}
+
+			// Work slung with --self happens in the rig's own checkout
+			selfRig := rigFilter
+			if selfRig == "" {
+				selfRig, _ = crew.InferRig(cfg, "")
+			}
+			if selfRig != "" {
+				addWork(selfRig, "self", cfg.GetRepoPath(selfRig))
+			}
+
+			if len(rigWork) == 0 {
+				fmt.Println("No active work found")
+				fmt.Println()
+				fmt.Println("Create work with: rig work create <name>")
+				fmt.Println("Assign work with: rig sling work/<name>")
+				return nil
+			}
+
+			// Display work grouped by rig
+			for _, rigName := range sortedKeys(rigWork) {
+				workItems := rigWork[rigName]
+				sort.Slice(workItems, func(i, j int) bool {
+					if workItems[i].WorkName != workItems[j].WorkName {
+						return workItems[i].WorkName < workItems[j].WorkName
+					}
+					return workItems[i].AssignedTo < workItems[j].AssignedTo
+				})
+				fmt.Printf("🏗️  %s\n", rigName)
+
+				for _, item := range workItems {
+					statusDisplay := item.Status
+					if statusDisplay == "" {
+						statusDisplay = "Unknown"
 					}
+
+					emoji := "👤"
+					if polecat.IsPolecat(item.AssignedTo) {
+						emoji = "🐱"
+					}
+
+					fmt.Printf("  %-20s [%s] %s %-18s %s\n",
+						item.WorkName,
+						colorWorkStatus(fmt.Sprintf("%-14s", statusDisplay)),
+						emoji,
+						item.AssignedTo,
+						item.Branch)
+
+					if item.CurrentTask != "" {
+						fmt.Printf("    → %s\n", item.CurrentTask)
+					}
+				}
+				fmt.Println()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rigFilter, "rig", "", "Only show work on this rig")
+	cmd.RegisterFlagCompletionFunc("rig", completeRigFlag)
+
+	return cmd
+}
+
+// validateFormula checks that a formula exists in the repo, listing the
+// available ones if not
+func validateFormula(repoPath, formulaName string) error {
+	formulas, err := work.ListFormulas(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to list formulas: %w", err)
+	}
+
+	for _, f := range formulas {
+		if f == formulaName {
+			return nil
+		}
+	}
+
+	if len(formulas) == 0 {
+		return fmt.Errorf("formula not found: %s\nNo formulas available", formulaName)
+	}
+	return fmt.Errorf("formula not found: %s\nAvailable formulas: %s", formulaName, strings.Join(formulas, ", "))
+}
+
+// slungWorkKey is the branch config key recording the work slung onto a
+// branch that isn't named feat/<name>
+const slungWorkKey = "rigWork"
+
+// branchWork returns the work a branch is for, from being slung with --branch
+// or from its feat/<name> name, or "" if it isn't for any work
+func branchWork(repoPath, branch string) string {
+	if workName := git.GetBranchConfig(repoPath, branch, slungWorkKey); workName != "" {
+		return workName
+	}
+	return work.InferWorkFromBranch(branch)
+}
+
+// crewActiveWork returns the work a crew workspace has in progress, based on
+// its feature branch and progress.md, or "" if it's idle
+func crewActiveWork(crewPath string) string {
+	branch, err := git.GetCurrentBranch(crewPath)
+	if err != nil {
+		return ""
+	}
+
+	workName := branchWork(crewPath, branch)
+	if workName == "" {
+		return ""
+	}
+
+	progress, err := work.ParseProgress(filepath.Join(crewPath, "work", workName, "progress.md"))
+	if err != nil || !strings.EqualFold(progress.Status, "In Progress") {
+		return ""
+	}
+
+	return workName
+}
+
+// crewCreated describes when a crew workspace was created, e.g. "created 2h
+// ago", or returns "" if it has no metadata
+func crewCreated(crewPath string) string {
+	meta, err := crew.ReadMeta(crewPath)
+	if err != nil || meta == nil || meta.CreatedAt.IsZero() {
+		return ""
+	}
+	return "created " + formatAge(time.Since(meta.CreatedAt))
+}
+
+// crewActivity describes when a crew workspace last had a commit, e.g. "last
+// commit 3d ago", or returns "" if that can't be read
+func crewActivity(crewPath string) string {
+	last, err := git.LastCommitTime(crewPath)
+	if err != nil {
+		return ""
+	}
+	return "last commit " + formatAge(time.Since(last))
+}
+
+// parseAge parses a duration like time.ParseDuration, also accepting whole
+// days such as "7d"
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return d, nil
+}
+
+// formatAge renders a duration in its largest whole unit, e.g. "5m ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// newClipboard returns the system clipboard; replaced in tests
+var newClipboard = clipboard.Detect
+
+func hookCmd() *cobra.Command {
+	var copyHook bool
+	var raw bool
+	var regenerate bool
+	var formulaName string
+	var inlineFormula bool
+
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Display the hook file for current work",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get current directory and find repo root
+			pwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+
+			repo, err := git.OpenRepo(pwd)
+			if err != nil {
+				return fmt.Errorf("not in a git repository: %w", err)
+			}
+			repoPath := repo.Root
+
+			// Get current branch
+			branch, err := repo.CurrentBranch()
+			if err != nil {
+				return fmt.Errorf("failed to get current branch: %w", err)
+			}
+
+			// Infer work name from branch; a reviewer has its own hook
+			hookFile := "hook.md"
+			workName := branchWork(repoPath, branch)
+			if reviewName := work.InferReviewFromBranch(branch); reviewName != "" {
+				workName = reviewName
+				hookFile = work.ReviewHookFileName
+			}
+			if workName == "" {
+				return fmt.Errorf("not on a feature branch (expected feat/<name> or review/<name>), current branch: %s", branch)
+			}
+
+			// Regenerate from the formula if asked
+			if (cmd.Flags().Changed("formula") || inlineFormula) && !regenerate {
+				return fmt.Errorf("--formula and --inline-formula require --regenerate")
+			}
+			if regenerate && hookFile != "hook.md" {
+				return fmt.Errorf("--regenerate isn't supported for review hooks\nRun 'rig sling work/%s --reviewer' for a new reviewer", workName)
+			}
+			if regenerate {
+				if err := validateFormula(repoPath, formulaName); err != nil {
+					return err
+				}
+				if err := work.GenerateHook(repoPath, workName, formulaName, work.HookOptions{InlineFormula: inlineFormula}); err != nil {
+					return fmt.Errorf("failed to generate hook: %w", err)
+				}
+				if !raw {
+					ui.Printf("✓ Regenerated hook: work/%s/hook.md (formula: %s)\n\n", workName, formulaName)
+				}
+			}
+
+			// Find hook file
+			hookPath := filepath.Join(work.GetWorkPath(repoPath, workName), hookFile)
+			if _, err := os.Stat(hookPath); os.IsNotExist(err) {
+				return fmt.Errorf("no hook found for work: %s\nRun 'rig sling work/%s' to create one", workName, workName)
+			}
+
+			// Read and display hook
+			content, err := os.ReadFile(hookPath)
+			if err != nil {
+				return fmt.Errorf("failed to read hook: %w", err)
+			}
+
+			if copyHook {
+				board, err := newClipboard()
+				if err == nil {
+					err = board.Copy(string(content))
 				}
+				if err == nil {
+					ui.Printf("✓ Copied hook for %s to clipboard\n", workName)
+					return nil
+				}
+				ui.Warnf("Could not copy to clipboard: %v", err)
 			}
 
-			fmt.Println()
-			fmt.Println("Next steps:")
-			fmt.Printf("  1. Edit work/%s/spec.md\n", workName)
-			fmt.Printf("  2. When ready: rig sling work/%s\n", workName)
-			fmt.Printf("\nYou are now on branch: %s\n", featureBranch)
+			if !raw {
+				fmt.Printf("🪝 Hook: %s\n\n", workName)
+			}
+			fmt.Print(string(content))
 
 			return nil
 		},
 	}
-}
 
-func workStatusCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "status",
-		Short: "Show all active work across all rigs",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("💼 Active Work")
-			fmt.Println()
+	cmd.Flags().BoolVarP(&copyHook, "copy", "c", false, "Copy the hook to the clipboard instead of printing it")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print only the hook contents, without the header")
+	cmd.Flags().BoolVar(&regenerate, "regenerate", false, "Regenerate hook.md from a formula before showing it")
+	cmd.Flags().StringVar(&formulaName, "formula", "build", "Formula to regenerate from (with --regenerate)")
+	cmd.Flags().BoolVar(&inlineFormula, "inline-formula", false, "Include the formula's phase headings in the regenerated hook")
+	cmd.RegisterFlagCompletionFunc("formula", completeFormulas)
 
-			// Check if crew base exists
-			if _, err := os.Stat(cfg.CrewBase); os.IsNotExist(err) {
-				fmt.Println("No crew workspaces found")
-				return nil
-			}
+	return cmd
+}
 
-			// Scan all rigs
-			rigDirs, err := os.ReadDir(cfg.CrewBase)
-			if err != nil {
-				return fmt.Errorf("failed to read crew directory: %w", err)
-			}
+// createReviewer creates a polecat workspace on a new review branch off a
+// work's feature branch and commits a review hook there. The builder's
+// workspace and branch are left alone. Returns the polecat's name and path.
+func createReviewer(repo *git.Repo, rigName, workName, formulaFile string, hookOpts work.HookOptions) (string, string, error) {
+	featureBranch := "feat/" + workName
+	if !repo.BranchExists(featureBranch) {
+		return "", "", fmt.Errorf("feature branch not found: %s\nRun 'rig work create %s' first", featureBranch, workName)
+	}
 
-			type WorkItem struct {
-				WorkName    string
-				Status      string
-				AssignedTo  string
-				Branch      string
-				CurrentTask string
-			}
+	reviewBranch := work.ReviewBranch(workName)
+	if repo.BranchExists(reviewBranch) {
+		return "", "", fmt.Errorf("review branch already exists: %s\nRemove the previous reviewer or delete the branch first", reviewBranch)
+	}
 
-			rigWork := make(map[string][]WorkItem)
+	name := polecat.GenerateName(listCrewNames(cfg, rigName))
+	crewPath := cfg.GetCrewPath(rigName, name)
 
-			for _, rigDir := range rigDirs {
-				if !rigDir.IsDir() {
-					continue
-				}
+	if err := os.MkdirAll(filepath.Dir(crewPath), 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create crew directory: %w", err)
+	}
+	if err := git.CreateWorktree(repo.Root, crewPath, reviewBranch, featureBranch); err != nil {
+		return "", "", err
+	}
 
-				rigName := rigDir.Name()
-				rigPath := filepath.Join(cfg.CrewBase, rigName)
+	cleanup := func() {
+		git.RemoveWorktree(repo.Root, crewPath)
+		git.PruneWorktrees(repo.Root)
+		git.DeleteBranch(repo.Root, reviewBranch)
+	}
 
-				// Scan crew members in this rig
-				crewDirs, err := os.ReadDir(rigPath)
-				if err != nil {
-					continue
-				}
+	if _, err := os.Stat(work.GetWorkPath(crewPath, workName)); err != nil {
+		cleanup()
+		return "", "", fmt.Errorf("work directory not found on %s: work/%s", featureBranch, workName)
+	}
 
-				for _, crewDir := range crewDirs {
-					if !crewDir.IsDir() {
-						continue
-					}
+	if err := work.GenerateReviewHook(crewPath, workName, formulaFile, hookOpts); err != nil {
+		cleanup()
+		return "", "", fmt.Errorf("failed to generate review hook: %w", err)
+	}
 
-					crewName := crewDir.Name()
-					crewPath := filepath.Join(rigPath, crewName)
+	if err := git.AddPaths(crewPath, "work/"+workName+"/"); err != nil {
+		cleanup()
+		return "", "", err
+	}
+	if err := git.Commit(crewPath, fmt.Sprintf("Add review hook for %s", workName)); err != nil {
+		cleanup()
+		return "", "", fmt.Errorf("failed to commit review hook: %w", err)
+	}
 
-					// Get current branch
-					branch, err := git.GetCurrentBranch(crewPath)
-					if err != nil {
-						continue
-					}
+	if err := crew.WriteMeta(crewPath, crew.Meta{CreatedAt: time.Now()}); err != nil {
+		ui.Warnf("%v", err)
+	}
 
-					// Check if it's a feature branch
-					workName := work.InferWorkFromBranch(branch)
-					if workName == "" {
-						continue
-					}
+	ui.Printf("✓ Created reviewer: 🐱 %s\n", name)
+	ui.Printf("✓ Workspace: %s\n", crewPath)
+	ui.Printf("✓ Branch: %s (from %s)\n", reviewBranch, featureBranch)
+	ui.Printf("✓ Created hook: work/%s/%s\n", workName, work.ReviewHookFileName)
+	return name, crewPath, nil
+}
 
-					// Try to read progress.md
-					progressPath := filepath.Join(crewPath, "work", workName, "progress.md")
-					progress, err := work.ParseProgress(progressPath)
-					if err != nil {
-						// If progress.md doesn't exist or can't be parsed, show basic info
-						rigWork[rigName] = append(rigWork[rigName], WorkItem{
-							WorkName:    workName,
-							Status:      "Unknown",
-							AssignedTo:  crewName,
-							Branch:      branch,
-							CurrentTask: "",
-						})
-						continue
-					}
+// startHookSession creates a crew session for a freshly slung workspace and
+// tells its Claude Code pane to run 'rig hook'. The workspace is kept if the
+// session can't be created, so starting it can be retried.
+func startHookSession(sessionName, crewPath, rigName, name, branch string) error {
+	// Create tmux session
+	useCC := crew.SessionUseCC(cfg, sessionName)
+	if err := tmux.CreateCrewSession(sessionName, crewPath, rigName, name, branch, useCC, crew.SessionOptions(cfg, crewPath)); err != nil {
+		return fmt.Errorf("worktree created but session failed: %w\nRun 'rig crew start %s --rig=%s' to retry, then 'rig hook' in the session", err, name, rigName)
+	}
 
-					// Add work item with full details
-					rigWork[rigName] = append(rigWork[rigName], WorkItem{
-						WorkName:    workName,
-						Status:      progress.Status,
-						AssignedTo:  crewName,
-						Branch:      branch,
-						CurrentTask: progress.GetCurrentTask(),
-					})
-				}
-			}
+	// Send the hook command to the Claude Code pane
+	target, err := tmux.PaneTarget(sessionName, tmux.PaneClaude, useCC)
+	if err != nil {
+		return err
+	}
 
-			if len(rigWork) == 0 {
-				fmt.Println("No active work found")
-				fmt.Println()
-				fmt.Println("Create work with: rig work create <name>")
-				fmt.Println("Assign work with: rig sling work/<name>")
-				return nil
-			}
+	// Wait for Claude Code to start
+	time.Sleep(2 * time.Second)
+
+	// First send a clear instruction message, then the actual rig hook
+	// command, with a small delay between them
+	instructionMsg := "# YOUR WORK ASSIGNMENT: Run the command 'rig hook' to see your instructions"
+	for i, command := range []string{instructionMsg, "rig hook"} {
+		if i > 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err := tmux.SendCommand(target, command); err != nil {
+			return fmt.Errorf("%w\nRun 'rig hook' in the session to start", err)
+		}
+	}
 
-			// Display work grouped by rig
-			for rigName, workItems := range rigWork {
-				fmt.Printf("🏗️  %s\n", rigName)
+	ui.Println()
+	ui.Println("Session started. Sent 'rig hook' command to Claude Code.")
+	return nil
+}
 
-				for _, item := range workItems {
-					statusDisplay := item.Status
-					if statusDisplay == "" {
-						statusDisplay = "Unknown"
-					}
+// commitWorkDir offers to commit uncommitted changes in work/<name>/ before
+// the work is handed to someone else. action names the hand-off in prompts.
+func commitWorkDir(repoPath, workName, action string) error {
+	changes, err := git.StatusPorcelain(repoPath, "work/"+workName+"/")
+	if err != nil {
+		return err
+	}
 
-					emoji := "👤"
-					if polecat.IsPolecat(item.AssignedTo) {
-						emoji = "🐱"
-					}
+	if len(changes) == 0 {
+		return nil
+	}
 
-					fmt.Printf("  %-20s [%-14s] %s %-18s %s\n",
-						item.WorkName,
-						statusDisplay,
-						emoji,
-						item.AssignedTo,
-						item.Branch)
+	ui.Warnf("Uncommitted changes in work directory:")
+	for _, change := range changes {
+		if change.OrigPath != "" {
+			fmt.Fprintf(os.Stderr, "   %s %s -> %s\n", change.Code, change.OrigPath, change.Path)
+		} else {
+			fmt.Fprintf(os.Stderr, "   %s %s\n", change.Code, change.Path)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Commit these changes before %s? (Y/n) ", action)
+	var response string
+	fmt.Scanln(&response)
 
-					if item.CurrentTask != "" {
-						fmt.Printf("    → %s\n", item.CurrentTask)
-					}
-				}
-				fmt.Println()
-			}
+	if strings.ToLower(response) == "n" {
+		return fmt.Errorf("cancelled - please commit your changes before %s", action)
+	}
 
-			return nil
-		},
+	if err := git.AddPaths(repoPath, "work/"+workName+"/"); err != nil {
+		return err
+	}
+
+	commitMsg := fmt.Sprintf("Update work files for %s", workName)
+	if err := git.Commit(repoPath, commitMsg); err != nil {
+		return err
 	}
+
+	ui.Printf("✓ Committed changes: \"%s\"\n", commitMsg)
+	return nil
 }
 
-func hookCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "hook",
-		Short: "Display the hook file for current work",
+func mvWorkCmd() *cobra.Command {
+	var toName string
+
+	cmd := &cobra.Command{
+		Use:   "mv-work <name> --to <crew>",
+		Short: "Hand a work directory to an existing crew member",
+		Long: `Hand a work directory to an existing crew member.
+
+Commits any changes in work/<name>/ on its feature branch, frees the branch
+in the main repo, and checks it out in the crew's worktree. Unlike sling,
+no hook is generated and nothing is sent to the crew's session.
+
+Examples:
+    rig mv-work add-auth --to tracy
+    rig mv-work work/add-auth --to tracy`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeWorkPaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Get current directory and find repo root
+			if toName == "" {
+				return fmt.Errorf("--to is required")
+			}
+			workName := strings.TrimSuffix(strings.TrimPrefix(args[0], "work/"), "/")
+
 			pwd, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("failed to get current directory: %w", err)
 			}
-
-			repoPath, err := git.GetRepoRoot(pwd)
+			repo, err := git.OpenRepo(pwd)
 			if err != nil {
 				return fmt.Errorf("not in a git repository: %w", err)
 			}
+			repoPath := repo.Root
+			rigName := filepath.Base(repoPath)
 
-			// Get current branch
-			branch, err := git.GetCurrentBranch(repoPath)
-			if err != nil {
-				return fmt.Errorf("failed to get current branch: %w", err)
+			crewPath := crew.Locate(cfg, rigName, toName)
+			if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+				return fmt.Errorf("crew workspace not found: %s\nRun 'rig crew add %s --rig=%s' first", crewPath, toName, rigName)
 			}
 
-			// Infer work name from branch
-			workName := work.InferWorkFromBranch(branch)
-			if workName == "" {
-				return fmt.Errorf("not on a feature branch (expected feat/<name>), current branch: %s", branch)
+			featureBranch := "feat/" + workName
+			if !repo.BranchExists(featureBranch) {
+				return fmt.Errorf("feature branch not found: %s\nRun 'rig work create %s' first", featureBranch, workName)
 			}
 
-			// Find hook file
-			hookPath := filepath.Join(work.GetWorkPath(repoPath, workName), "hook.md")
-			if _, err := os.Stat(hookPath); os.IsNotExist(err) {
-				return fmt.Errorf("no hook found for work: %s\nRun 'rig sling work/%s' to create one", workName, workName)
+			// Commit the work on its branch, then free the branch for the crew
+			currentBranch, err := repo.CurrentBranch()
+			if err != nil {
+				return fmt.Errorf("failed to get current branch: %w", err)
+			}
+			if currentBranch == featureBranch {
+				if err := commitWorkDir(repoPath, workName, "moving it"); err != nil {
+					return err
+				}
+
+				baseBranch, err := repo.BaseBranch(cfg.DefaultBranch)
+				if err != nil {
+					return fmt.Errorf("failed to get base branch: %w", err)
+				}
+				ui.Printf("Switching to %s...\n", baseBranch)
+				if err := repo.CheckoutBranch(baseBranch); err != nil {
+					return fmt.Errorf("failed to checkout base branch: %w", err)
+				}
 			}
 
-			// Read and display hook
-			content, err := os.ReadFile(hookPath)
+			crewBranch, err := git.GetCurrentBranch(crewPath)
 			if err != nil {
-				return fmt.Errorf("failed to read hook: %w", err)
+				return fmt.Errorf("failed to get current branch of %s: %w", toName, err)
+			}
+			if crewBranch != featureBranch {
+				if existing, _ := repo.WorktreeForBranch(featureBranch); existing != "" {
+					return fmt.Errorf("%s is checked out in %s", featureBranch, existing)
+				}
+				if err := git.CheckoutBranch(crewPath, featureBranch); err != nil {
+					return fmt.Errorf("failed to checkout branch: %w", err)
+				}
+				ui.Printf("✓ Checked out branch: %s\n", featureBranch)
 			}
 
-			fmt.Printf("🪝 Hook: %s\n\n", workName)
-			fmt.Print(string(content))
+			if _, err := os.Stat(work.GetWorkPath(crewPath, workName)); err != nil {
+				return fmt.Errorf("work/%s is not on %s\nCommit it there, then run mv-work again", workName, featureBranch)
+			}
 
+			ui.Printf("✓ Moved work/%s to %s\n", workName, toName)
+			ui.Printf("✓ Workspace: %s\n", crewPath)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&toName, "to", "", "Crew member to hand the work to")
+	cmd.RegisterFlagCompletionFunc("to", completeCrewFlag)
+
+	return cmd
 }
 
 func slingCmd() *cobra.Command {
 	var toName string
 	var formulaName string
+	var formulaFile string
+	var inlineFormula bool
 	var self bool
+	var reviewer bool
+	var branch string
+	var attach bool
 
 	cmd := &cobra.Command{
 		Use:   "sling <work-path>",
 		Short: "Assign work to a crew member or polecat",
-		Args:  cobra.ExactArgs(1),
+		Long: `Assign work to a crew member or polecat.
+
+By default a new polecat gets a workspace on the work's feature branch and
+a session that starts on the hook. Use --to for an existing crew member, or
+--self to work on it yourself. With --attach, you're attached to the new
+session once the hook has been sent.
+
+With --reviewer, a new polecat reviews the work instead: it gets its own
+review/<name> branch off the feature branch, and a review hook generated
+from the review formula. Whoever is building the work keeps it.
+
+Use --branch to sling onto an existing branch that isn't named
+feat/<name>. The work directory is still work/<name>.
+
+Examples:
+    rig sling work/add-auth
+    rig sling work/add-auth --to tracy
+    rig sling work/add-auth --reviewer
+    rig sling work/add-auth --branch tracy/login
+    rig sling work/add-auth --attach`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeWorkPaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			workPath := args[0]
 
@@ -1109,20 +3597,77 @@ func slingCmd() *cobra.Command {
 			}
 			workName := parts[1]
 
+			if formulaFile != "" {
+				if formulaName != "" {
+					return fmt.Errorf("--formula can't be combined with --formula-file")
+				}
+				if _, err := os.Stat(formulaFile); err != nil {
+					return fmt.Errorf("formula file not found: %s", formulaFile)
+				}
+			}
+			if reviewer && toName != "" {
+				return fmt.Errorf("--reviewer can't be combined with --to")
+			}
+			if reviewer && self {
+				return fmt.Errorf("--reviewer can't be combined with --self")
+			}
+			if reviewer && branch != "" {
+				return fmt.Errorf("--reviewer can't be combined with --branch")
+			}
+			if attach && self {
+				return fmt.Errorf("--attach can't be combined with --self")
+			}
+			if attach && toName != "" {
+				return fmt.Errorf("--attach can't be combined with --to")
+			}
+
 			// Get current directory and find repo root
 			pwd, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("failed to get current directory: %w", err)
 			}
 
-			repoPath, err := git.GetRepoRoot(pwd)
+			repo, err := git.OpenRepo(pwd)
 			if err != nil {
 				return fmt.Errorf("not in a git repository: %w", err)
 			}
+			repoPath := repo.Root
 
 			// Infer rig name
 			rigName := filepath.Base(repoPath)
 
+			// A reviewer works from the feature branch as committed, so the
+			// current checkout doesn't matter
+			if reviewer {
+				if formulaFile == "" {
+					if formulaName == "" {
+						formulaName = "review"
+					}
+					if formulaName == "review" {
+						if err := work.EnsureReviewFormula(repoPath); err != nil {
+							return fmt.Errorf("failed to install review formula: %w", err)
+						}
+					}
+					if err := validateFormula(repoPath, formulaName); err != nil {
+						return err
+					}
+					formulaFile = work.GetFormulaPath(repoPath, formulaName)
+				}
+
+				reviewerName, crewPath, err := createReviewer(repo, rigName, workName, formulaFile, work.HookOptions{InlineFormula: inlineFormula})
+				if err != nil {
+					return err
+				}
+				sessionName := cfg.GetCrewSessionName(rigName, reviewerName)
+				if err := startHookSession(sessionName, crewPath, rigName, reviewerName, work.ReviewBranch(workName)); err != nil {
+					return err
+				}
+				if attach {
+					return attachSession(sessionName, crew.SessionUseCC(cfg, sessionName))
+				}
+				return nil
+			}
+
 			// Verify work directory exists
 			fullWorkPath := work.GetWorkPath(repoPath, workName)
 			if _, err := os.Stat(fullWorkPath); os.IsNotExist(err) {
@@ -1131,132 +3676,161 @@ func slingCmd() *cobra.Command {
 
 			// Feature branch name
 			featureBranch := "feat/" + workName
+			if branch != "" {
+				featureBranch = branch
+			}
 
 			// Verify feature branch exists
-			if !git.BranchExists(repoPath, featureBranch) {
+			if !repo.BranchExists(featureBranch) {
+				if branch != "" {
+					return fmt.Errorf("branch not found: %s", branch)
+				}
 				return fmt.Errorf("feature branch not found: %s\nRun 'rig work create %s' first", featureBranch, workName)
 			}
 
-			// Get current branch
-			currentBranch, err := git.GetCurrentBranch(repoPath)
-			if err != nil {
-				return fmt.Errorf("failed to get current branch: %w", err)
+			// Slinging checks the base branch out in the repo, so the work
+			// can't live on it
+			if branch != "" {
+				if baseBranch, err := repo.BaseBranch(cfg.DefaultBranch); err == nil && branch == baseBranch {
+					return fmt.Errorf("can't sling onto the base branch %s\nCreate a branch for the work first, e.g. 'git branch feat/%s'", branch, workName)
+				}
 			}
 
-			// If we're not on the feature branch, switch to it first
-			if currentBranch != featureBranch {
-				fmt.Printf("Switching to %s...\n", featureBranch)
-				if err := git.CheckoutBranch(repoPath, featureBranch); err != nil {
-					return fmt.Errorf("failed to checkout feature branch: %w", err)
+			// Record the work on a branch whose name doesn't say, so 'rig
+			// hook' can find it. This waits until nothing can cancel the
+			// sling, so a failed one leaves no config behind.
+			recordWork := func() error {
+				if work.InferWorkFromBranch(featureBranch) == workName {
+					return nil
 				}
+				return git.SetBranchConfig(repoPath, featureBranch, slungWorkKey, workName)
 			}
 
-			// Default formula to "build"
-			if formulaName == "" {
-				formulaName = "build"
+			// A new polecat takes the work over from whoever has the branch
+			// checked out. Their workspace is only removed once nothing can
+			// cancel the sling, so until then the hook is written there.
+			var assigned *crew.CrewInfo
+			hookRoot := repoPath
+			if !self && toName == "" {
+				for _, info := range crew.DiscoverRig(cfg, rigName) {
+					if info.Branch != featureBranch {
+						continue
+					}
+					displayName := info.Name
+					if info.IsPolecat {
+						displayName = "🐱 " + info.Name
+					}
+					ui.Warnf("work/%s is already assigned to %s", workName, displayName)
+					fmt.Fprintf(os.Stderr, "   Workspace: %s\n", info.Path)
+					fmt.Println()
+					fmt.Print("Reassign to new polecat? (y/N) ")
+					var response string
+					fmt.Scanln(&response)
+					if strings.ToLower(response) != "y" {
+						return fmt.Errorf("cancelled")
+					}
+					if git.IsDirty(info.Path) {
+						return fmt.Errorf("%s has uncommitted changes in %s\nCommit or discard them before reassigning the work", info.Name, info.Path)
+					}
+					assigned = &info
+					hookRoot = info.Path
+					break
+				}
 			}
 
-			// Validate formula exists
-			formulas, err := work.ListFormulas(repoPath)
+			// Get current branch
+			currentBranch, err := repo.CurrentBranch()
 			if err != nil {
-				return fmt.Errorf("failed to list formulas: %w", err)
-			}
-
-			formulaExists := false
-			for _, f := range formulas {
-				if f == formulaName {
-					formulaExists = true
-					break
-				}
+				return fmt.Errorf("failed to get current branch: %w", err)
 			}
 
-			if !formulaExists {
-				if len(formulas) == 0 {
-					return fmt.Errorf("formula not found: %s\nNo formulas available", formulaName)
+			// If we're not on the feature branch, switch to it first
+			if assigned == nil && currentBranch != featureBranch {
+				ui.Printf("Switching to %s...\n", featureBranch)
+				if err := repo.CheckoutBranch(featureBranch); err != nil {
+					return fmt.Errorf("failed to checkout feature branch: %w", err)
 				}
-				return fmt.Errorf("formula not found: %s\nAvailable formulas: %s", formulaName, strings.Join(formulas, ", "))
 			}
 
 			// Generate hook (while on feature branch)
-			if err := work.GenerateHook(repoPath, workName, formulaName); err != nil {
-				return fmt.Errorf("failed to generate hook: %w", err)
-			}
-
-			fmt.Printf("✓ Created hook: work/%s/hook.md\n", workName)
-
-			// Check for uncommitted changes in work directory (including hook.md)
-			statusCmd := exec.Command("git", "status", "--porcelain", "work/"+workName+"/")
-			statusCmd.Dir = repoPath
-			statusOutput, err := statusCmd.Output()
-			if err != nil {
-				return fmt.Errorf("failed to check git status: %w", err)
-			}
-
-			hasUncommittedChanges := len(strings.TrimSpace(string(statusOutput))) > 0
-
-			if hasUncommittedChanges {
-				fmt.Println("⚠️  Uncommitted changes in work directory:")
-				fmt.Println(string(statusOutput))
-				fmt.Print("Commit these changes before slinging? (Y/n) ")
-				var response string
-				fmt.Scanln(&response)
-
-				if strings.ToLower(response) == "n" {
-					return fmt.Errorf("cancelled - please commit your changes before slinging")
+			hookOpts := work.HookOptions{InlineFormula: inlineFormula}
+			if formulaFile != "" {
+				if err := work.GenerateHookFromFile(hookRoot, workName, formulaFile, hookOpts); err != nil {
+					return fmt.Errorf("failed to generate hook: %w", err)
+				}
+				ui.Printf("✓ Copied formula: work/%s/%s\n", workName, work.FormulaFileName)
+			} else {
+				// Default formula to "build"
+				if formulaName == "" {
+					formulaName = "build"
 				}
 
-				// Commit the changes (including hook.md)
-				addCmd := exec.Command("git", "add", "work/"+workName+"/")
-				addCmd.Dir = repoPath
-				if err := addCmd.Run(); err != nil {
-					return fmt.Errorf("failed to stage changes: %w", err)
+				// Validate formula exists
+				if err := validateFormula(hookRoot, formulaName); err != nil {
+					return err
 				}
 
-				commitMsg := fmt.Sprintf("Update work files for %s", workName)
-				commitCmd := exec.Command("git", "commit", "-m", commitMsg)
-				commitCmd.Dir = repoPath
-				if err := commitCmd.Run(); err != nil {
-					return fmt.Errorf("failed to commit changes: %w", err)
+				if err := work.GenerateHook(hookRoot, workName, formulaName, hookOpts); err != nil {
+					return fmt.Errorf("failed to generate hook: %w", err)
 				}
+			}
 
-				fmt.Printf("✓ Committed changes: \"%s\"\n", commitMsg)
+			ui.Printf("✓ Created hook: work/%s/hook.md\n", workName)
+
+			// Commit uncommitted changes in the work directory (including hook.md)
+			if err := commitWorkDir(hookRoot, workName, "slinging"); err != nil {
+				return err
 			}
 
 			// Now switch to base branch (making feature branch available for worktree)
-			baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+			baseBranch, err := repo.BaseBranch(cfg.DefaultBranch)
 			if err != nil {
 				return fmt.Errorf("failed to get base branch: %w", err)
 			}
 
-			fmt.Printf("Switching to %s...\n", baseBranch)
-			if err := git.CheckoutBranch(repoPath, baseBranch); err != nil {
+			ui.Printf("Switching to %s...\n", baseBranch)
+			if err := repo.CheckoutBranch(baseBranch); err != nil {
 				return fmt.Errorf("failed to checkout base branch: %w", err)
 			}
 
 			// Handle --self flag
 			if self {
-				fmt.Println("✓ Hook ready in current workspace")
-				fmt.Println()
-				fmt.Println("To start working, run this command in your Claude Code session:")
-				fmt.Println("  rig hook")
-				fmt.Println()
-				fmt.Println("This will display your work instructions. Follow them to begin.")
+				if err := recordWork(); err != nil {
+					return err
+				}
+				ui.Println("✓ Hook ready in current workspace")
+				ui.Println()
+				ui.Println("To start working, run this command in your Claude Code session:")
+				ui.Println("  rig hook")
+				ui.Println()
+				ui.Println("This will display your work instructions. Follow them to begin.")
 				return nil
 			}
 
 			// Handle --to flag (existing crew member)
 			if toName != "" {
-				crewPath := cfg.GetCrewPath(rigName, toName)
+				crewPath := crew.Locate(cfg, rigName, toName)
 
 				// Check if crew workspace exists
 				if _, err := os.Stat(crewPath); os.IsNotExist(err) {
 					return fmt.Errorf("crew workspace not found: %s\nRun 'rig crew add %s --rig=%s' first", crewPath, toName, rigName)
 				}
 
+				// Check the crew isn't mid-task on other work
+				if activeWork := crewActiveWork(crewPath); activeWork != "" && activeWork != workName {
+					ui.Warnf("%s is still in progress on work/%s", toName, activeWork)
+					fmt.Print("Reassign anyway? [y/N] ")
+					var response string
+					fmt.Scanln(&response)
+					if strings.ToLower(response) != "y" {
+						return fmt.Errorf("cancelled - %s is busy with work/%s", toName, activeWork)
+					}
+				}
+
 				// Check if on correct branch
 				currentBranch, err := git.GetCurrentBranch(crewPath)
 				if err == nil && currentBranch != featureBranch {
-					fmt.Printf("⚠️  Warning: %s is on branch '%s', expected '%s'\n", toName, currentBranch, featureBranch)
+					ui.Warnf("%s is on branch '%s', expected '%s'", toName, currentBranch, featureBranch)
 					fmt.Print("Checkout feature branch? [Y/n] ")
 					var response string
 					fmt.Scanln(&response)
@@ -1264,17 +3838,20 @@ func slingCmd() *cobra.Command {
 						if err := git.CheckoutBranch(crewPath, featureBranch); err != nil {
 							return fmt.Errorf("failed to checkout branch: %w", err)
 						}
-						fmt.Printf("✓ Checked out branch: %s\n", featureBranch)
+						ui.Printf("✓ Checked out branch: %s\n", featureBranch)
 					}
 				}
 
-				fmt.Printf("✓ Workspace ready: %s\n", crewPath)
-				fmt.Printf("✓ Branch: %s\n", featureBranch)
-				fmt.Println()
-				fmt.Printf("To start working, paste this command into %s's Claude Code session:\n", toName)
-				fmt.Println("  rig hook")
-				fmt.Println()
-				fmt.Println("This will display the work instructions. Ask them to follow the instructions.")
+				if err := recordWork(); err != nil {
+					return err
+				}
+				ui.Printf("✓ Workspace ready: %s\n", crewPath)
+				ui.Printf("✓ Branch: %s\n", featureBranch)
+				ui.Println()
+				ui.Printf("To start working, paste this command into %s's Claude Code session:\n", toName)
+				ui.Println("  rig hook")
+				ui.Println()
+				ui.Println("This will display the work instructions. Ask them to follow the instructions.")
 				return nil
 			}
 
@@ -1290,40 +3867,10 @@ func slingCmd() *cobra.Command {
 				}
 			}
 
-			// Check if work is already assigned
-			worktrees, err := git.ListWorktrees(repoPath)
-			if err == nil {
-				for _, wt := range worktrees {
-					if wt.Branch == featureBranch {
-						// Extract crew name from path
-						relPath, err := filepath.Rel(cfg.CrewBase, wt.Path)
-						if err == nil {
-							pathParts := strings.Split(relPath, string(filepath.Separator))
-							if len(pathParts) >= 2 {
-								assignedName := pathParts[1]
-								displayName := assignedName
-								if polecat.IsPolecat(assignedName) {
-									displayName = "🐱 " + assignedName
-								}
-								fmt.Printf("⚠️  Warning: work/%s is already assigned to %s\n", workName, displayName)
-								fmt.Printf("   Workspace: %s\n", wt.Path)
-								fmt.Println()
-								fmt.Print("Reassign to new polecat? (y/N) ")
-								var response string
-								fmt.Scanln(&response)
-								if strings.ToLower(response) != "y" {
-									return fmt.Errorf("cancelled")
-								}
-							}
-						}
-					}
-				}
-			}
-
 			// Generate polecat name
 			polecatName := polecat.GenerateName(existingNames)
 
-			fmt.Printf("✓ Created polecat: 🐱 %s\n", polecatName)
+			ui.Printf("✓ Created polecat: 🐱 %s\n", polecatName)
 
 			// Create crew workspace for polecat
 			crewPath := cfg.GetCrewPath(rigName, polecatName)
@@ -1335,7 +3882,7 @@ func slingCmd() *cobra.Command {
 			}
 
 			// Check if worktree for this branch already exists
-			existingWorktree, _ := git.GetWorktreeForBranch(repoPath, featureBranch)
+			existingWorktree, _ := repo.WorktreeForBranch(featureBranch)
 			if existingWorktree != "" && existingWorktree != crewPath {
 				// Check if the existing worktree is the main repo
 				existingResolved, _ := filepath.EvalSymlinks(existingWorktree)
@@ -1344,84 +3891,65 @@ func slingCmd() *cobra.Command {
 				if existingResolved == repoResolved {
 					// The feature branch is still checked out in the main repo
 					// This shouldn't happen since we already switched earlier, but handle it just in case
-					baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+					baseBranch, err := repo.BaseBranch(cfg.DefaultBranch)
 					if err != nil {
 						return fmt.Errorf("failed to get base branch: %w", err)
 					}
 
-					fmt.Printf("Switching main repo to %s...\n", baseBranch)
-					if err := git.CheckoutBranch(repoPath, baseBranch); err != nil {
+					ui.Printf("Switching main repo to %s...\n", baseBranch)
+					if err := repo.CheckoutBranch(baseBranch); err != nil {
 						return fmt.Errorf("failed to checkout base branch in main repo: %w", err)
 					}
 				} else {
-					// It's a crew worktree, remove it
-					git.RemoveWorktree(repoPath, existingWorktree)
-					git.PruneWorktrees(repoPath)
+					// It's the previous assignee's worktree, clean apart from
+					// the committed hook, so take it down with its session
+					if err := repo.RemoveWorktree(existingWorktree); err != nil {
+						return fmt.Errorf("failed to free %s: %w", featureBranch, err)
+					}
+					repo.PruneWorktrees()
+					if assigned != nil && tmux.SessionExists(assigned.SessionName) {
+						if err := killSession(assigned.SessionName); err != nil {
+							ui.Warnf("Failed to kill %s's session: %v", assigned.Name, err)
+						}
+					}
 				}
 			}
 
 			// Create worktree from existing feature branch
-			if err := git.CreateWorktreeFromExisting(repoPath, crewPath, featureBranch); err != nil {
+			if err := repo.CreateWorktreeFromExisting(crewPath, featureBranch); err != nil {
 				return fmt.Errorf("failed to create worktree: %w", err)
 			}
-
-			fmt.Printf("✓ Workspace: %s\n", crewPath)
-			fmt.Printf("✓ Session: %s\n", sessionName)
-			fmt.Printf("✓ Branch: %s\n", featureBranch)
-
-			// Create tmux session
-			if err := tmux.CreateCrewSession(sessionName, crewPath, rigName, polecatName, featureBranch, cfg.UseCC, cfg.ClaudeInitPrompt); err != nil {
-				// Cleanup on failure
-				git.RemoveWorktree(repoPath, crewPath)
-				git.PruneWorktrees(repoPath)
-				return fmt.Errorf("failed to create session: %w", err)
+			if err := crew.WriteMeta(crewPath, crew.Meta{CreatedAt: time.Now(), Rig: rigName, Name: polecatName}); err != nil {
+				ui.Warnf("%v", err)
 			}
 
-			// Send initial command to Claude Code
-			time := 2000 // milliseconds - wait for Claude Code to start
-			sleepCmd := exec.Command("sleep", fmt.Sprintf("%.1f", float64(time)/1000.0))
-			sleepCmd.Run()
-
-			// Send the hook command to the first pane (Claude Code)
-			target := sessionName + ":.1"
-
-			// First send a clear instruction message
-			instructionMsg := "# YOUR WORK ASSIGNMENT: Run the command 'rig hook' to see your instructions"
-			sendCmd := exec.Command("tmux", "send-keys", "-t", target, instructionMsg)
-			sendCmd.Run()
-
-			// Send Enter to show the message
-			sleepCmd = exec.Command("sleep", "0.1")
-			sleepCmd.Run()
-			sendCmd = exec.Command("tmux", "send-keys", "-t", target, "C-m")
-			sendCmd.Run()
-
-			// Small delay
-			sleepCmd = exec.Command("sleep", "0.1")
-			sleepCmd.Run()
-
-			// Now send the actual rig hook command
-			sendCmd = exec.Command("tmux", "send-keys", "-t", target, "rig hook")
-			sendCmd.Run()
+			ui.Printf("✓ Workspace: %s\n", crewPath)
+			ui.Printf("✓ Session: %s\n", sessionName)
+			ui.Printf("✓ Branch: %s\n", featureBranch)
 
-			// Small delay
-			sleepCmd = exec.Command("sleep", "0.1")
-			sleepCmd.Run()
-
-			// Then send Enter to execute it
-			sendCmd = exec.Command("tmux", "send-keys", "-t", target, "C-m")
-			sendCmd.Run()
-
-			fmt.Println()
-			fmt.Println("Session started. Sent 'rig hook' command to Claude Code.")
+			if err := startHookSession(sessionName, crewPath, rigName, polecatName, featureBranch); err != nil {
+				return err
+			}
+			if err := recordWork(); err != nil {
+				return err
+			}
 
+			if attach {
+				return attachSession(sessionName, crew.SessionUseCC(cfg, sessionName))
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&toName, "to", "", "Assign to existing crew member")
-	cmd.Flags().StringVar(&formulaName, "formula", "", "Formula to use (default: build)")
+	cmd.Flags().StringVar(&formulaName, "formula", "", "Formula to use (default: build, or review with --reviewer)")
+	cmd.Flags().StringVar(&formulaFile, "formula-file", "", "Use an ad-hoc formula file instead of a named formula")
+	cmd.Flags().BoolVar(&inlineFormula, "inline-formula", false, "Include the formula's phase headings in the hook")
+	cmd.RegisterFlagCompletionFunc("formula", completeFormulas)
 	cmd.Flags().BoolVar(&self, "self", false, "Work on it yourself in current session")
+	cmd.Flags().BoolVar(&reviewer, "reviewer", false, "Have a new polecat review the work on its own review branch")
+	cmd.Flags().StringVar(&branch, "branch", "", "Sling onto this existing branch instead of feat/<name>")
+	cmd.Flags().BoolVar(&attach, "attach", false, "Attach to the new session after sending the hook")
 
 	return cmd
 }
@@ -0,0 +1,2163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mstrand/rig/internal/testutil"
+	"github.com/mstrand/rig/pkg/clipboard"
+	"github.com/mstrand/rig/pkg/config"
+	"github.com/mstrand/rig/pkg/crew"
+	"github.com/mstrand/rig/pkg/git"
+	"github.com/mstrand/rig/pkg/picker"
+	"github.com/mstrand/rig/pkg/ui"
+	"github.com/mstrand/rig/pkg/work"
+	"github.com/spf13/cobra"
+)
+
+func setupTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	testCfg := &config.Config{
+		RigsBase:      filepath.Join(tmpDir, "git"),
+		CrewBase:      filepath.Join(tmpDir, "crew"),
+		DefaultBranch: "main",
+	}
+	os.MkdirAll(testCfg.RigsBase, 0755)
+	os.MkdirAll(testCfg.CrewBase, 0755)
+
+	origCfg := cfg
+	cfg = testCfg
+	t.Cleanup(func() { cfg = origCfg })
+
+	return testCfg
+}
+
+func initTestRepo(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	cmd := exec.Command("git", "init")
+	cmd.Dir = path
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+
+	// Commit without relying on a global git identity
+	for _, args := range [][]string{
+		{"config", "user.name", "Test User"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = path
+		cmd.Run()
+	}
+}
+
+// useTestTmux points tmux at a private socket directory so tests never
+// touch the user's tmux server
+func useTestTmux(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available, skipping")
+	}
+
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() {
+		exec.Command("tmux", "kill-server").Run()
+	})
+}
+
+func TestCompleteRepoNames(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "myapp"))
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "mytool"))
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "notes"))
+	os.MkdirAll(filepath.Join(testCfg.RigsBase, "not-a-repo"), 0755)
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		expected   []string
+	}{
+		{"all repos", nil, "", []string{"myapp", "mytool", "notes"}},
+		{"prefix", nil, "my", []string{"myapp", "mytool"}},
+		{"no match", nil, "zzz", []string{}},
+		{"already has arg", []string{"myapp"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, directive := completeRepoNames(&cobra.Command{}, tt.args, tt.toComplete)
+			sort.Strings(result)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("completeRepoNames() = %v, want %v", result, tt.expected)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("Expected NoFileComp directive, got %v", directive)
+			}
+		})
+	}
+}
+
+func TestCompleteCrewNames(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	os.MkdirAll(testCfg.GetCrewPath("notes", "tracy"), 0755)
+	os.MkdirAll(testCfg.GetCrewPath("notes", "polecat_emma"), 0755)
+	os.MkdirAll(testCfg.GetCrewPath("myapp", "alex"), 0755)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("rig", "", "")
+	cmd.Flags().Set("rig", "notes")
+
+	result, _ := completeCrewNames(cmd, nil, "")
+	sort.Strings(result)
+	expected := []string{"polecat_emma", "tracy"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("completeCrewNames() = %v, want %v", result, expected)
+	}
+
+	result, _ = completeCrewNames(cmd, nil, "tr")
+	if !reflect.DeepEqual(result, []string{"tracy"}) {
+		t.Errorf("completeCrewNames() with prefix = %v, want [tracy]", result)
+	}
+}
+
+func TestCompleteSessionNames(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "my.app"))
+
+	// tmux renames my.app to my_app
+	for _, name := range []string{"notes", "notes@tracy", "myapp", "my_app"} {
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+
+	result, _ := completeSessionNames(&cobra.Command{}, nil, "notes")
+	sort.Strings(result)
+	expected := []string{"notes", "notes@tracy"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("completeSessionNames() = %v, want %v", result, expected)
+	}
+
+	result, _ = completeSessionNames(&cobra.Command{}, nil, "my.")
+	if !reflect.DeepEqual(result, []string{"my.app"}) {
+		t.Errorf("completeSessionNames() = %v, want [my.app]", result)
+	}
+}
+
+// chdirTemp changes into dir for the duration of the test
+func chdirTemp(t *testing.T, dir string) {
+	t.Helper()
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+}
+
+func TestCompleteFormulas(t *testing.T) {
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	initTestRepo(t, repoPath)
+
+	formulaDir := filepath.Join(repoPath, "work", "formula")
+	os.MkdirAll(formulaDir, 0755)
+	for _, name := range []string{"build", "bugfix", "hotfix"} {
+		os.WriteFile(filepath.Join(formulaDir, name+".md"), []byte("# "+name), 0644)
+	}
+
+	chdirTemp(t, repoPath)
+
+	tests := []struct {
+		toComplete string
+		expected   []string
+	}{
+		{"", []string{"bugfix", "build", "hotfix"}},
+		{"b", []string{"bugfix", "build"}},
+		{"h", []string{"hotfix"}},
+		{"x", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toComplete, func(t *testing.T) {
+			result, directive := completeFormulas(&cobra.Command{}, []string{"work/foo"}, tt.toComplete)
+			sort.Strings(result)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("completeFormulas(%q) = %v, want %v", tt.toComplete, result, tt.expected)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("Expected NoFileComp directive, got %v", directive)
+			}
+		})
+	}
+}
+
+func TestCompleteWorkPaths(t *testing.T) {
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	initTestRepo(t, repoPath)
+
+	for _, dir := range []string{"formula", "build-frontend", "add-auth"} {
+		os.MkdirAll(filepath.Join(repoPath, "work", dir), 0755)
+	}
+
+	chdirTemp(t, repoPath)
+
+	result, _ := completeWorkPaths(&cobra.Command{}, nil, "")
+	sort.Strings(result)
+	expected := []string{"work/add-auth", "work/build-frontend"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("completeWorkPaths() = %v, want %v", result, expected)
+	}
+}
+
+func createBranchRepos(tb testing.TB, n int) map[string]string {
+	tb.Helper()
+
+	baseDir := tb.TempDir()
+	paths := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("repo%02d", i)
+		path := filepath.Join(baseDir, name)
+		os.MkdirAll(path, 0755)
+		cmd := exec.Command("git", "init", "-b", "branch-"+name)
+		cmd.Dir = path
+		if err := cmd.Run(); err != nil {
+			tb.Fatalf("Failed to init git repo: %v", err)
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+func TestLookupBranches(t *testing.T) {
+	paths := createBranchRepos(t, 5)
+	paths["missing"] = filepath.Join(t.TempDir(), "does-not-exist")
+
+	branches := lookupBranches(paths)
+
+	if len(branches) != len(paths) {
+		t.Fatalf("Expected %d results, got %d", len(paths), len(branches))
+	}
+	for key := range paths {
+		expected := "branch-" + key
+		if key == "missing" {
+			expected = "unknown"
+		}
+		if branches[key] != expected {
+			t.Errorf("branches[%q] = %q, want %q", key, branches[key], expected)
+		}
+	}
+
+	if result := lookupBranches(map[string]string{}); len(result) != 0 {
+		t.Errorf("Expected empty result for no paths, got %v", result)
+	}
+}
+
+func BenchmarkBranchLookupSerial(b *testing.B) {
+	paths := createBranchRepos(b, 24)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, path := range paths {
+			git.GetCurrentBranch(path)
+		}
+	}
+}
+
+func BenchmarkBranchLookupParallel(b *testing.B) {
+	paths := createBranchRepos(b, 24)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lookupBranches(paths)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		n        int
+		expected string
+	}{
+		{"fewer lines than n", "a\nb\n", 5, "a\nb"},
+		{"trims trailing blank lines", "a\nb\nc\n\n\n", 2, "b\nc"},
+		{"zero means all", "a\nb\nc", 0, "a\nb\nc"},
+		{"empty", "\n\n", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := lastLines(tt.input, tt.n); result != tt.expected {
+				t.Errorf("lastLines(%q, %d) = %q, want %q", tt.input, tt.n, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     string
+		cur      string
+		expected string
+	}{
+		{"unchanged", "a\nb\n\n", "a\nb\n\n", ""},
+		{"appended below", "a\nb\n\n\n", "a\nb\nc\n\n", "c"},
+		{"scrolled", "a\nb\nc\n", "b\nc\nd\ne\n", "d\ne"},
+		{"scrolled past everything", "a\nb\n", "c\nd\n", "c\nd"},
+		{"first capture", "", "a\nb\n", "a\nb"},
+		{"cleared", "a\nb\n", "\n\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := newLines(tt.prev, tt.cur); result != tt.expected {
+				t.Errorf("newLines(%q, %q) = %q, want %q", tt.prev, tt.cur, result, tt.expected)
+			}
+		})
+	}
+}
+
+// captureStdout returns everything fn writes to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+// captureStderr returns everything fn writes to stderr, such as warnings
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+func captureFile(t *testing.T, file **os.File, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	orig := *file
+	*file = w
+	defer func() { *file = orig }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+// attachTestTmux makes the test look like it runs inside the given session
+func attachTestTmux(t *testing.T, session string) {
+	t.Helper()
+
+	output, err := exec.Command("tmux", "display-message", "-p", "-t", session, "#{socket_path},#{pid},#{pane_id}").Output()
+	if err != nil {
+		t.Fatalf("Failed to query session %s: %v", session, err)
+	}
+	parts := strings.Split(strings.TrimSpace(string(output)), ",")
+	t.Setenv("TMUX", parts[0]+","+parts[1]+",0")
+	t.Setenv("TMUX_PANE", parts[2])
+}
+
+func TestRenderStatusDottedRigName(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "my.app"))
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "notes"))
+	for _, name := range []string{"my.app", "notes"} {
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+	attachTestTmux(t, "my_app")
+
+	output := captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "✓ my.app") {
+		t.Errorf("Expected my.app to be marked current, got:\n%s", output)
+	}
+	if strings.Contains(output, "✓ notes") {
+		t.Errorf("Expected notes not to be marked current, got:\n%s", output)
+	}
+}
+
+func TestAttachedLabel(t *testing.T) {
+	attached := map[string]bool{"my_app": true, "notes": false}
+
+	if got := attachedLabel(attached, "my.app"); got != " (attached)" {
+		t.Errorf("attachedLabel(my.app) = %q, want \" (attached)\"", got)
+	}
+	for _, session := range []string{"notes", "missing"} {
+		if got := attachedLabel(attached, session); got != "" {
+			t.Errorf("attachedLabel(%s) = %q, want empty", session, got)
+		}
+	}
+}
+
+func TestRenderStatusSHA(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	apiPath := filepath.Join(testCfg.RigsBase, "api")
+	initTestRepo(t, apiPath)
+	runGitCmd(t, apiPath, "commit", "--allow-empty", "-m", "initial")
+	hash, err := git.CurrentCommitHash(apiPath)
+	if err != nil {
+		t.Fatalf("CurrentCommitHash() error = %v", err)
+	}
+
+	// notes has no commits yet
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "notes"))
+	for _, name := range []string{"api", "notes"} {
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{ShowSHA: true}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, " @ "+hash+"\n") {
+		t.Errorf("Expected api commit %s in status, got:\n%s", hash, output)
+	}
+	if !strings.Contains(output, " @ —\n") {
+		t.Errorf("Expected — for the unborn notes branch, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if strings.Contains(output, " @ ") {
+		t.Errorf("Expected no commits without --sha, got:\n%s", output)
+	}
+}
+
+func TestRenderStatusCrewOnly(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "api"))
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "api").Run(); err != nil {
+		t.Fatalf("Failed to create session api: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{CrewOnly: true}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if strings.Contains(output, "Active Rigs") || strings.Contains(output, "  api") {
+		t.Errorf("Expected --crew-only to omit the rigs section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "No active crew") {
+		t.Errorf("Expected the crew section, got:\n%s", output)
+	}
+	if strings.Contains(output, "Start a rig") {
+		t.Errorf("Expected no rig hint with --crew-only, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Start crew with") {
+		t.Errorf("Expected the crew hint, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{RigsOnly: true}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if strings.Contains(output, "👥 Crew") || strings.Contains(output, "Start crew") {
+		t.Errorf("Expected --rigs-only to omit the crew section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "api") || strings.Contains(output, "Start a rig") {
+		t.Errorf("Expected api listed without the rig hint, got:\n%s", output)
+	}
+}
+
+func TestRenderStatusStale(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	oldPath := filepath.Join(testCfg.RigsBase, "old")
+	initTestRepo(t, oldPath)
+	old := time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339)
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "long ago")
+	cmd.Dir = oldPath
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+old, "GIT_AUTHOR_DATE="+old)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, output)
+	}
+
+	freshPath := filepath.Join(testCfg.RigsBase, "fresh")
+	initTestRepo(t, freshPath)
+	runGitCmd(t, freshPath, "commit", "--allow-empty", "-m", "just now")
+
+	for _, name := range []string{"old", "fresh"} {
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{Stale: 7 * 24 * time.Hour}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "old ⏰ last commit 10d ago\n") {
+		t.Errorf("Expected old to be flagged stale, got:\n%s", output)
+	}
+	if strings.Contains(output, "fresh ⏰") {
+		t.Errorf("Expected fresh not to be flagged, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := renderStatus(testCfg, statusOptions{}); err != nil {
+			t.Fatalf("renderStatus() error = %v", err)
+		}
+	})
+	if strings.Contains(output, "⏰") {
+		t.Errorf("Expected no stale markers without --stale, got:\n%s", output)
+	}
+}
+
+// stubPicker returns a scripted choice and records what it was offered
+type stubPicker struct {
+	choice  string
+	err     error
+	offered []string
+}
+
+func (p *stubPicker) Pick(label string, items []string) (string, error) {
+	p.offered = items
+	return p.choice, p.err
+}
+
+func TestSelectAndAttach(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	for _, name := range []string{"notes", "notes@tracy"} {
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+
+	var attached []string
+	origAttach := attachSession
+	attachSession = func(name string, useCC bool) error {
+		attached = append(attached, name)
+		return nil
+	}
+	t.Cleanup(func() { attachSession = origAttach })
+
+	stub := &stubPicker{choice: "notes@tracy"}
+	origPicker := sessionPicker
+	sessionPicker = stub
+	t.Cleanup(func() { sessionPicker = origPicker })
+
+	if err := selectAndAttach(testCfg); err != nil {
+		t.Fatalf("selectAndAttach() error = %v", err)
+	}
+
+	sort.Strings(stub.offered)
+	if !reflect.DeepEqual(stub.offered, []string{"notes", "notes@tracy"}) {
+		t.Errorf("Picker offered %v, want running sessions", stub.offered)
+	}
+	if !reflect.DeepEqual(attached, []string{"notes@tracy"}) {
+		t.Errorf("Attached to %v, want [notes@tracy]", attached)
+	}
+
+	// A cancelled pick attaches to nothing
+	attached = nil
+	stub.err = picker.ErrCancelled
+	if err := selectAndAttach(testCfg); err != picker.ErrCancelled {
+		t.Errorf("Expected ErrCancelled, got %v", err)
+	}
+	if len(attached) != 0 {
+		t.Errorf("Expected no attach after cancel, got %v", attached)
+	}
+}
+
+// runGitCmd runs a git command in dir, failing the test on error
+func runGitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestCrewListBranchMismatch(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	os.MkdirAll(filepath.Join(testCfg.CrewBase, "notes"), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "tracy/work", testCfg.GetCrewPath("notes", "tracy"))
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "experiment", testCfg.GetCrewPath("notes", "alex"))
+
+	output := captureStdout(t, func() {
+		cmd := crewListCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("crew ls error = %v", err)
+		}
+	})
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "alex") && !strings.Contains(line, "experiment ⚠"):
+			t.Errorf("Expected alex to be flagged on experiment, got %q", line)
+		case strings.Contains(line, "tracy") && strings.Contains(line, "⚠"):
+			t.Errorf("Expected tracy not to be flagged, got %q", line)
+		}
+	}
+	if !strings.Contains(output, "⚠ = not on its crew branch") {
+		t.Errorf("Expected mismatch legend, got:\n%s", output)
+	}
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Expected no color in piped output, got %q", output)
+	}
+}
+
+func TestCrewListMissingCrewBase(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	testCfg.CrewBase = filepath.Join(t.TempDir(), "crew")
+
+	output := captureStdout(t, func() {
+		cmd := crewListCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("crew ls error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "No crew workspaces yet ("+condensePath(testCfg.CrewBase)+" doesn't exist)") || !strings.Contains(output, "rig crew add <name>") {
+		t.Errorf("Expected a first-run hint, got:\n%s", output)
+	}
+}
+
+func TestCrewListHere(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	herePath := filepath.Join(t.TempDir(), "notes-tracy")
+	captureStdout(t, func() {
+		if err := crew.Add(testCfg, "tracy", "notes", crew.AddOptions{NoSession: true, Here: herePath}); err != nil {
+			t.Fatalf("crew add --here error = %v", err)
+		}
+	})
+
+	// Nothing exists under CrewBase, so the crew is only found via worktrees
+	output := captureStdout(t, func() {
+		cmd := crewListCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("crew ls error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "🏗️  notes") || !strings.Contains(output, "tracy") || !strings.Contains(output, "tracy/work") {
+		t.Errorf("Expected tracy listed under notes, got:\n%s", output)
+	}
+
+	// which also knows the crew from inside the custom worktree
+	chdirTemp(t, herePath)
+	info, err := currentContext(testCfg)
+	if err != nil {
+		t.Fatalf("currentContext error = %v", err)
+	}
+	if info.Rig != "notes" || info.Crew != "tracy" {
+		t.Errorf("currentContext = %+v, want notes@tracy", info)
+	}
+}
+
+// stubClipboard records copied text
+type stubClipboard struct {
+	copied []string
+}
+
+func (c *stubClipboard) Copy(text string) error {
+	c.copied = append(c.copied, text)
+	return nil
+}
+
+// setupHookRepo creates a repo on feat/add-auth whose hook holds content and
+// changes into it, returning the hook's path
+func setupHookRepo(t *testing.T, content string) string {
+	t.Helper()
+
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "checkout", "-b", "feat/add-auth")
+	hookPath := filepath.Join(repoPath, "work", "add-auth", "hook.md")
+	os.MkdirAll(filepath.Dir(hookPath), 0755)
+	os.WriteFile(hookPath, []byte(content), 0644)
+	chdirTemp(t, repoPath)
+	return hookPath
+}
+
+func TestHookCopy(t *testing.T) {
+	setupHookRepo(t, "# Hook\nDo the work\n")
+
+	board := &stubClipboard{}
+	origClipboard := newClipboard
+	newClipboard = func() (clipboard.Clipboard, error) { return board, nil }
+	t.Cleanup(func() { newClipboard = origClipboard })
+
+	output := captureStdout(t, func() {
+		cmd := hookCmd()
+		cmd.SetArgs([]string{"--copy"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("hook --copy error = %v", err)
+		}
+	})
+
+	if !reflect.DeepEqual(board.copied, []string{"# Hook\nDo the work\n"}) {
+		t.Errorf("Copied %q, want hook content", board.copied)
+	}
+	if strings.Contains(output, "Do the work") {
+		t.Errorf("Expected hook not to be printed when copied, got:\n%s", output)
+	}
+
+	// Without a clipboard tool the hook is printed instead
+	newClipboard = func() (clipboard.Clipboard, error) { return nil, clipboard.ErrNoTool }
+	warnings := captureStderr(t, func() {
+		output = captureStdout(t, func() {
+			cmd := hookCmd()
+			cmd.SetArgs([]string{"--copy"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("hook --copy error = %v", err)
+			}
+		})
+	})
+	if !strings.Contains(warnings, "no clipboard tool found") {
+		t.Errorf("Expected clipboard warning on stderr, got:\n%s", warnings)
+	}
+	if !strings.Contains(output, "Do the work") {
+		t.Errorf("Expected printed hook, got:\n%s", output)
+	}
+}
+
+func TestHookRaw(t *testing.T) {
+	setupHookRepo(t, "# Hook\nDo the work\n")
+
+	output := captureStdout(t, func() {
+		cmd := hookCmd()
+		cmd.SetArgs([]string{"--raw"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("hook --raw error = %v", err)
+		}
+	})
+
+	if output != "# Hook\nDo the work\n" {
+		t.Errorf("hook --raw = %q, want only the hook content", output)
+	}
+}
+
+func TestHookRegenerate(t *testing.T) {
+	hookPath := setupHookRepo(t, "stale hook\n")
+	if err := work.EnsureDefaultFormula("."); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		cmd := hookCmd()
+		cmd.SetArgs([]string{"--regenerate", "--formula", "build"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("hook --regenerate error = %v", err)
+		}
+	})
+
+	content, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("Failed to read hook: %v", err)
+	}
+	if strings.Contains(string(content), "stale hook") {
+		t.Errorf("Expected hook.md to be overwritten, got:\n%s", content)
+	}
+	if !strings.Contains(output, "Regenerated hook") || !strings.Contains(output, string(content)) {
+		t.Errorf("Expected regenerated hook to be printed, got:\n%s", output)
+	}
+
+	// Unknown formulas are rejected and leave the hook alone
+	cmd := hookCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs([]string{"--regenerate", "--formula", "missing"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "formula not found: missing") {
+		t.Errorf("hook --regenerate --formula missing error = %v, want formula not found", err)
+	}
+	after, _ := os.ReadFile(hookPath)
+	if string(after) != string(content) {
+		t.Errorf("Expected hook.md to be unchanged after a failed regenerate")
+	}
+}
+
+func TestWorkCreateOpen(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	chdirTemp(t, repoPath)
+
+	var opened []string
+	origRunEditor := runEditor
+	runEditor = func(cmd *exec.Cmd) error {
+		opened = cmd.Args
+		return nil
+	}
+	t.Cleanup(func() { runEditor = origRunEditor })
+
+	runCreate := func(name string) string {
+		return captureStdout(t, func() {
+			cmd := workCreateCmd()
+			cmd.SetArgs([]string{name, "--open"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("work create %s --open error = %v", name, err)
+			}
+		})
+	}
+
+	t.Setenv("EDITOR", "myedit -w")
+	t.Setenv("VISUAL", "vi")
+	runCreate("add-auth")
+	specPath := filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "spec.md")
+	if want := []string{"myedit", "-w", specPath}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("Expected editor %v, got %v", want, opened)
+	}
+
+	// $VISUAL is the fallback
+	t.Setenv("EDITOR", "")
+	runGitCmd(t, repoPath, "checkout", "main")
+	runCreate("fix-login")
+	specPath = filepath.Join(work.GetWorkPath(repoPath, "fix-login"), "spec.md")
+	if want := []string{"vi", specPath}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("Expected editor %v, got %v", want, opened)
+	}
+
+	// Without an editor, the path is printed instead
+	opened = nil
+	t.Setenv("VISUAL", "")
+	runGitCmd(t, repoPath, "checkout", "main")
+	output := runCreate("add-docs")
+	if opened != nil {
+		t.Errorf("Expected no editor to run, got %v", opened)
+	}
+	if !strings.Contains(output, filepath.Join(work.GetWorkPath(repoPath, "add-docs"), "spec.md")) {
+		t.Errorf("Expected spec path in output, got:\n%s", output)
+	}
+}
+
+func TestWorkCreateNoCommit(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	chdirTemp(t, repoPath)
+	before, _ := git.CurrentCommitHash(repoPath)
+
+	output := captureStdout(t, func() {
+		cmd := workCreateCmd()
+		cmd.SetArgs([]string{"add-auth", "--no-commit"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("work create --no-commit error = %v", err)
+		}
+	})
+
+	if branch, _ := git.GetCurrentBranch(repoPath); branch != "feat/add-auth" {
+		t.Errorf("Expected to be on feat/add-auth, got %s", branch)
+	}
+	if after, _ := git.CurrentCommitHash(repoPath); after != before {
+		t.Errorf("Expected no new commit, HEAD moved from %s to %s", before, after)
+	}
+	changes, err := git.StatusPorcelain(repoPath, "work/add-auth/")
+	if err != nil {
+		t.Fatalf("StatusPorcelain() error = %v", err)
+	}
+	if len(changes) == 0 || changes[0].Code != "??" {
+		t.Errorf("Expected work/add-auth/ untracked, got %+v", changes)
+	}
+	if !strings.Contains(output, "uncommitted") {
+		t.Errorf("Expected a reminder to commit, got:\n%s", output)
+	}
+}
+
+func TestSlingToBusyCrew(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	if err := work.EnsureDefaultFormula(repoPath); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+	for _, name := range []string{"add-auth", "other"} {
+		os.MkdirAll(work.GetWorkPath(repoPath, name), 0755)
+		os.WriteFile(filepath.Join(work.GetWorkPath(repoPath, name), "progress.md"), []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+	}
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "initial")
+	runGitCmd(t, repoPath, "branch", "feat/other")
+
+	// Commit the hook up front so sling has nothing to commit
+	runGitCmd(t, repoPath, "checkout", "-b", "feat/add-auth")
+	if err := work.GenerateHook(repoPath, "add-auth", "build", work.HookOptions{}); err != nil {
+		t.Fatalf("GenerateHook() error = %v", err)
+	}
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "hook")
+	runGitCmd(t, repoPath, "checkout", "main")
+
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", crewPath, "feat/other")
+	chdirTemp(t, repoPath)
+
+	if got := crewActiveWork(crewPath); got != "other" {
+		t.Errorf("crewActiveWork() = %q, want %q", got, "other")
+	}
+
+	testutil.StubStdin(t, "n\n")
+	var err error
+	output := captureStderr(t, func() {
+		cmd := slingCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"work/add-auth", "--to", "tracy"})
+		err = cmd.Execute()
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "tracy is busy with work/other") {
+		t.Errorf("sling --to busy crew error = %v, want busy error", err)
+	}
+	if !strings.Contains(output, "still in progress on work/other") {
+		t.Errorf("Expected busy warning, got:\n%s", output)
+	}
+	if branch, _ := git.GetCurrentBranch(crewPath); branch != "feat/other" {
+		t.Errorf("Expected crew to stay on feat/other, got %s", branch)
+	}
+}
+
+func TestSlingBranch(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	if err := work.EnsureDefaultFormula(repoPath); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+	os.MkdirAll(work.GetWorkPath(repoPath, "add-auth"), 0755)
+	os.WriteFile(filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "progress.md"), []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "initial")
+	runGitCmd(t, repoPath, "branch", "tracy/login")
+
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "--detach", crewPath)
+	chdirTemp(t, repoPath)
+
+	runSling := func(args ...string) error {
+		cmd := slingCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := runSling("work/add-auth", "--to", "tracy", "--branch", "missing"); err == nil || !strings.Contains(err.Error(), "branch not found: missing") {
+		t.Errorf("sling --branch missing error = %v, want branch not found", err)
+	}
+	if err := runSling("work/add-auth", "--to", "tracy", "--branch", "main"); err == nil || !strings.Contains(err.Error(), "can't sling onto the base branch main") {
+		t.Errorf("sling --branch main error = %v, want base branch refused", err)
+	}
+
+	// A sling that fails doesn't map the branch to the work
+	captureStdout(t, func() {
+		if err := runSling("work/add-auth", "--to", "tracy", "--branch", "tracy/login", "--formula", "missing"); err == nil {
+			t.Errorf("Expected sling with a missing formula to fail")
+		}
+	})
+	if got := git.GetBranchConfig(repoPath, "tracy/login", slungWorkKey); got != "" {
+		t.Errorf("Expected no work recorded after a failed sling, got %q", got)
+	}
+
+	testutil.StubStdin(t, "y\n")
+	var err error
+	captureStdout(t, func() {
+		err = runSling("work/add-auth", "--to", "tracy", "--branch", "tracy/login")
+	})
+	if err != nil {
+		t.Fatalf("sling --branch error = %v", err)
+	}
+
+	// The hook is committed on the given branch, not a feat/ one
+	runGitCmd(t, repoPath, "cat-file", "-e", "tracy/login:work/add-auth/hook.md")
+	if git.BranchExists(repoPath, "feat/add-auth") {
+		t.Error("Expected no feat/add-auth branch")
+	}
+	if branch, _ := git.GetCurrentBranch(crewPath); branch != "tracy/login" {
+		t.Errorf("Expected crew on tracy/login, got %s", branch)
+	}
+
+	// The branch still maps back to the work
+	if got := crewActiveWork(crewPath); got != "add-auth" {
+		t.Errorf("crewActiveWork() = %q, want %q", got, "add-auth")
+	}
+
+	// until the work is archived
+	captureStdout(t, func() {
+		cmd := workArchiveCmd()
+		cmd.SetArgs([]string{"add-auth"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("work archive error = %v", err)
+		}
+	})
+	if got := git.GetBranchConfig(repoPath, "tracy/login", slungWorkKey); got != "" {
+		t.Errorf("Expected archiving to forget the branch's work, got %q", got)
+	}
+}
+
+func TestSlingAlreadyAssigned(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	os.MkdirAll(work.GetWorkPath(repoPath, "add-auth"), 0755)
+	os.WriteFile(filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "progress.md"), []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "initial")
+	runGitCmd(t, repoPath, "branch", "feat/add-auth")
+
+	// Crew added with --here lives outside CrewBase
+	herePath := filepath.Join(t.TempDir(), "tracy-here")
+	runGitCmd(t, repoPath, "worktree", "add", herePath, "feat/add-auth")
+	if err := crew.WriteMeta(herePath, crew.Meta{Rig: "notes", Name: "tracy"}); err != nil {
+		t.Fatalf("WriteMeta() error = %v", err)
+	}
+	chdirTemp(t, repoPath)
+
+	testutil.StubStdin(t, "n\n")
+	var err error
+	output := captureStderr(t, func() {
+		cmd := slingCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"work/add-auth"})
+		err = cmd.Execute()
+	})
+
+	if err == nil || err.Error() != "cancelled" {
+		t.Errorf("sling error = %v, want cancelled", err)
+	}
+	if !strings.Contains(output, "already assigned to tracy") || !strings.Contains(output, "Workspace: "+herePath) {
+		t.Errorf("Expected assignment warning for tracy, got:\n%s", output)
+	}
+	if branch, _ := git.GetCurrentBranch(herePath); branch != "feat/add-auth" {
+		t.Errorf("Expected tracy to stay on feat/add-auth, got %s", branch)
+	}
+}
+
+func TestSlingReassign(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	if err := work.EnsureDefaultFormula(repoPath); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+	os.MkdirAll(work.GetWorkPath(repoPath, "add-auth"), 0755)
+	os.WriteFile(filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "progress.md"), []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "initial")
+	runGitCmd(t, repoPath, "branch", "feat/add-auth")
+
+	// tracy has the work, with a running session
+	tracyPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(tracyPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", tracyPath, "feat/add-auth")
+	tracySession := testCfg.GetCrewSessionName("notes", "tracy")
+	// The hook is sent to pane 1, which needs 1-based indexes
+	setup := exec.Command("tmux", "new-session", "-d", "-s", tracySession, ";", "set", "-g", "base-index", "1", ";", "set", "-g", "pane-base-index", "1")
+	if output, err := setup.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to start tmux: %v\n%s", err, output)
+	}
+	chdirTemp(t, repoPath)
+
+	runSling := func(input string, args ...string) error {
+		testutil.StubStdin(t, input)
+		var err error
+		captureStderr(t, func() {
+			captureStdout(t, func() {
+				cmd := slingCmd()
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				cmd.SetArgs(args)
+				err = cmd.Execute()
+			})
+		})
+		return err
+	}
+	requireTracy := func(t *testing.T) {
+		t.Helper()
+		if branch, _ := git.GetCurrentBranch(tracyPath); branch != "feat/add-auth" {
+			t.Fatalf("Expected tracy's workspace to stay on feat/add-auth, got %q", branch)
+		}
+	}
+
+	t.Run("dirty workspace is refused", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tracyPath, "notes.txt"), []byte("wip"), 0644)
+		defer os.Remove(filepath.Join(tracyPath, "notes.txt"))
+
+		err := runSling("y\n", "work/add-auth")
+		if err == nil || !strings.Contains(err.Error(), "tracy has uncommitted changes") {
+			t.Errorf("sling error = %v, want uncommitted changes", err)
+		}
+		requireTracy(t)
+	})
+
+	t.Run("bad formula keeps the workspace", func(t *testing.T) {
+		if err := runSling("y\n", "work/add-auth", "--formula", "nosuch"); err == nil {
+			t.Error("Expected an error for an unknown formula")
+		}
+		requireTracy(t)
+	})
+
+	t.Run("declined commit keeps the workspace", func(t *testing.T) {
+		err := runSling("y\nn\n", "work/add-auth")
+		if err == nil || !strings.Contains(err.Error(), "cancelled") {
+			t.Errorf("sling error = %v, want cancelled", err)
+		}
+		requireTracy(t)
+		runGitCmd(t, tracyPath, "clean", "-fdq")
+	})
+
+	t.Run("reassigns", func(t *testing.T) {
+		if err := runSling("y\n\n", "work/add-auth"); err != nil {
+			t.Fatalf("sling error = %v", err)
+		}
+		if _, err := os.Stat(tracyPath); !os.IsNotExist(err) {
+			t.Error("Expected tracy's workspace to be removed")
+		}
+		if exec.Command("tmux", "has-session", "-t", tracySession).Run() == nil {
+			t.Error("Expected tracy's session to be killed")
+		}
+		wtPath, err := git.GetWorktreeForBranch(repoPath, "feat/add-auth")
+		if err != nil || !strings.Contains(filepath.Base(wtPath), "polecat_") {
+			t.Errorf("Expected a polecat on feat/add-auth, got %q (%v)", wtPath, err)
+		}
+		if output, err := exec.Command("git", "-C", repoPath, "show", "feat/add-auth:work/add-auth/hook.md").CombinedOutput(); err != nil {
+			t.Errorf("Expected the hook committed on feat/add-auth: %v\n%s", err, output)
+		}
+	})
+}
+
+func TestSlingAttach(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	if err := work.EnsureDefaultFormula(repoPath); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+	os.MkdirAll(work.GetWorkPath(repoPath, "add-auth"), 0755)
+	os.WriteFile(filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "progress.md"), []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "initial")
+	runGitCmd(t, repoPath, "branch", "feat/add-auth")
+	chdirTemp(t, repoPath)
+
+	// The hook is sent to pane 1, which needs 1-based indexes
+	setup := exec.Command("tmux", "new-session", "-d", "-s", "setup", ";", "set", "-g", "base-index", "1", ";", "set", "-g", "pane-base-index", "1")
+	if output, err := setup.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to start tmux: %v\n%s", err, output)
+	}
+
+	var attached []string
+	origAttach := attachSession
+	attachSession = func(name string, useCC bool) error {
+		attached = append(attached, name)
+		return nil
+	}
+	t.Cleanup(func() { attachSession = origAttach })
+
+	runSling := func(args ...string) error {
+		cmd := slingCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := runSling("work/add-auth", "--attach", "--self"); err == nil || !strings.Contains(err.Error(), "--attach can't be combined with --self") {
+		t.Errorf("sling --attach --self error = %v, want conflict", err)
+	}
+
+	var err error
+	captureStdout(t, func() {
+		err = runSling("work/add-auth", "--attach")
+	})
+	if err != nil {
+		t.Fatalf("sling --attach error = %v", err)
+	}
+
+	if len(attached) != 1 || !strings.HasPrefix(attached[0], testCfg.GetCrewSessionName("notes", "")) {
+		t.Fatalf("Expected to attach to the new polecat's session, got %v", attached)
+	}
+	if exec.Command("tmux", "has-session", "-t", attached[0]).Run() != nil {
+		t.Errorf("Expected session %s to be running", attached[0])
+	}
+}
+
+func TestSlingFormulaFileFlags(t *testing.T) {
+	formulaFile := filepath.Join(t.TempDir(), "one-off.md")
+	os.WriteFile(formulaFile, []byte("# One-off\n"), 0644)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "combined with --formula",
+			args:    []string{"work/add-auth", "--formula", "build", "--formula-file", formulaFile},
+			wantErr: "--formula can't be combined with --formula-file",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"work/add-auth", "--formula-file", formulaFile + ".missing"},
+			wantErr: "formula file not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := slingCmd()
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("sling %v error = %v, want %q", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuietCrewRemove(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+	t.Cleanup(func() { ui.Quiet = false })
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	// A detached worktree has no crew branch, so remove doesn't prompt
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "--detach", crewPath)
+
+	output := captureStdout(t, func() {
+		cmd := newRootCmd()
+		cmd.SetArgs([]string{"--quiet", "crew", "remove", "tracy", "--rig", "notes"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("rig --quiet crew remove error = %v", err)
+		}
+	})
+
+	if output != "" {
+		t.Errorf("Expected no output in quiet mode, got:\n%s", output)
+	}
+	if _, err := os.Stat(crewPath); !os.IsNotExist(err) {
+		t.Errorf("Expected crew workspace to be removed")
+	}
+}
+
+func TestConfigWarnings(t *testing.T) {
+	t.Cleanup(func() { ui.Quiet = false })
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("RIGS_BASE", missing)
+
+	origCfg := cfg
+	cfg = config.Load()
+	t.Cleanup(func() { cfg = origCfg })
+
+	run := func(args ...string) string {
+		return captureStderr(t, func() {
+			captureStdout(t, func() {
+				cmd := newRootCmd()
+				cmd.SetArgs(args)
+				if err := cmd.Execute(); err != nil {
+					t.Fatalf("rig %v error = %v", args, err)
+				}
+			})
+		})
+	}
+
+	if warnings := run("version"); !strings.Contains(warnings, "RIGS_BASE "+missing+" does not exist yet") {
+		t.Errorf("Expected a warning about RIGS_BASE, got: %q", warnings)
+	}
+	if warnings := run("--quiet", "version"); warnings != "" {
+		t.Errorf("Expected no warnings with --quiet, got: %q", warnings)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	setupTestConfig(t)
+	origVersion, origCommit := version, commit
+	version, commit = "1.2.3", "abc1234"
+	t.Cleanup(func() { version, commit = origVersion, origCommit })
+
+	output := captureStdout(t, func() {
+		cmd := newRootCmd()
+		cmd.SetArgs([]string{"version"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("rig version error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "rig 1.2.3") || !strings.Contains(output, "commit abc1234") {
+		t.Errorf("Expected version and commit in output, got: %q", output)
+	}
+}
+
+func TestRepoNameFromURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"git@github.com:me/notes.git", "notes"},
+		{"https://github.com/me/api", "api"},
+		{"https://github.com/me/api.git/", "api"},
+		{"/srv/git/shared.git", "shared"},
+		{"host:repo", "repo"},
+	}
+
+	for _, tt := range tests {
+		if got := repoNameFromURL(tt.url); got != tt.expected {
+			t.Errorf("repoNameFromURL(%q) = %q, want %q", tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	// A bare remote with one commit to clone from
+	srcPath := filepath.Join(t.TempDir(), "src")
+	initTestRepo(t, srcPath)
+	runGitCmd(t, srcPath, "commit", "--allow-empty", "-m", "initial")
+	remotePath := filepath.Join(t.TempDir(), "notes.git")
+	runGitCmd(t, srcPath, "clone", "--bare", srcPath, remotePath)
+
+	cmd := newRootCmd()
+	cmd.SetArgs([]string{"clone", remotePath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("rig clone error = %v", err)
+	}
+	if !git.IsGitRepo(testCfg.GetRepoPath("notes")) {
+		t.Fatalf("Expected clone at %s", testCfg.GetRepoPath("notes"))
+	}
+
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"clone", remotePath, "backend"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("rig clone with name error = %v", err)
+	}
+	if !git.IsGitRepo(testCfg.GetRepoPath("backend")) {
+		t.Errorf("Expected clone at %s", testCfg.GetRepoPath("backend"))
+	}
+
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"clone", remotePath})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected error cloning over an existing rig, got %v", err)
+	}
+}
+
+func TestUpSuggestsRepo(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "myapp"))
+
+	err := upRig(testCfg, "myap", "")
+	if err == nil || !strings.Contains(err.Error(), "Did you mean 'myapp'?") {
+		t.Errorf("Expected a suggestion for a typo, got %v", err)
+	}
+
+	err = upRig(testCfg, "backend", "")
+	if err == nil || strings.Contains(err.Error(), "Did you mean") {
+		t.Errorf("Expected no suggestion for an unrelated name, got %v", err)
+	}
+}
+
+func TestUpPrintPath(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+
+	cmd := upCmd()
+	cmd.SetArgs([]string{"notes", "--print-path"})
+	out := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("up --print-path failed: %v", err)
+		}
+	})
+
+	if out != repoPath+"\n" {
+		t.Errorf("Expected %q, got %q", repoPath+"\n", out)
+	}
+	if exec.Command("tmux", "has-session", "-t", "notes").Run() == nil {
+		t.Error("Expected no session to be created")
+	}
+
+	cmd = upCmd()
+	cmd.SetArgs([]string{"missing", "--print-path"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil {
+		t.Error("Expected error for a missing repo")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "--detach", crewPath)
+
+	var opened, edited []string
+	origRunOpener, origRunEditor := runOpener, runEditor
+	runOpener = func(cmd *exec.Cmd) error {
+		opened = cmd.Args
+		return nil
+	}
+	runEditor = func(cmd *exec.Cmd) error {
+		edited = cmd.Args
+		return nil
+	}
+	t.Cleanup(func() { runOpener, runEditor = origRunOpener, origRunEditor })
+	t.Setenv("EDITOR", "myedit")
+
+	runOpen := func(args ...string) error {
+		cmd := openCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := runOpen("notes"); err != nil {
+		t.Fatalf("open notes error = %v", err)
+	}
+	if want := openerCommand(runtime.GOOS, repoPath).Args; !reflect.DeepEqual(opened, want) {
+		t.Errorf("Expected opener %v, got %v", want, opened)
+	}
+
+	if err := runOpen("notes@tracy", "--editor"); err != nil {
+		t.Fatalf("open notes@tracy --editor error = %v", err)
+	}
+	if want := []string{"myedit", crewPath}; !reflect.DeepEqual(edited, want) {
+		t.Errorf("Expected editor %v, got %v", want, edited)
+	}
+
+	if err := runOpen("notes@missing"); err == nil || !strings.Contains(err.Error(), "crew workspace not found") {
+		t.Errorf("open notes@missing error = %v, want crew workspace not found", err)
+	}
+	if err := runOpen("missing"); err == nil || !strings.Contains(err.Error(), "repo not found") {
+		t.Errorf("open missing error = %v, want repo not found", err)
+	}
+}
+
+func TestOpenerCommand(t *testing.T) {
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{goos: "darwin", want: []string{"open", "/tmp/notes"}},
+		{goos: "linux", want: []string{"xdg-open", "/tmp/notes"}},
+	}
+
+	for _, tt := range tests {
+		if got := openerCommand(tt.goos, "/tmp/notes").Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("openerCommand(%q) = %v, want %v", tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestWhich(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "tracy", crewPath)
+	os.MkdirAll(filepath.Join(crewPath, "src"), 0755)
+	chdirTemp(t, filepath.Join(crewPath, "src"))
+
+	output := captureStdout(t, func() {
+		cmd := whichCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("which error = %v", err)
+		}
+	})
+	for _, want := range []string{"notes@tracy\n", "path:   " + crewPath, "branch: tracy"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	output = captureStdout(t, func() {
+		cmd := whichCmd()
+		cmd.SetArgs([]string{"--json"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("which --json error = %v", err)
+		}
+	})
+	var info contextInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Failed to parse JSON %q: %v", output, err)
+	}
+	want := contextInfo{Rig: "notes", Crew: "tracy", Path: crewPath, Branch: "tracy"}
+	if info != want {
+		t.Errorf("which --json = %+v, want %+v", info, want)
+	}
+
+	// From the rig's own checkout there's no crew
+	chdirTemp(t, repoPath)
+	info, err := currentContext(testCfg)
+	if err != nil {
+		t.Fatalf("currentContext error = %v", err)
+	}
+	if info.Rig != "notes" || info.Crew != "" || info.Path != repoPath {
+		t.Errorf("currentContext in rig = %+v", info)
+	}
+
+	// A CrewBase reached through a symlink still finds the crew
+	linkPath := filepath.Join(t.TempDir(), "crew-link")
+	if err := os.Symlink(testCfg.CrewBase, linkPath); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	testCfg.CrewBase = linkPath
+	chdirTemp(t, testCfg.GetCrewPath("notes", "tracy"))
+	info, err = currentContext(testCfg)
+	if err != nil {
+		t.Fatalf("currentContext error = %v", err)
+	}
+	if info.Rig != "notes" || info.Crew != "tracy" {
+		t.Errorf("currentContext through a symlinked CrewBase = %+v, want notes@tracy", info)
+	}
+}
+
+func TestSwitchSuggestsSession(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "notes"))
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "notes").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	cmd := newRootCmd()
+	cmd.SetArgs([]string{"switch", "ntoes"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "Did you mean 'notes'?") {
+		t.Errorf("Expected a suggestion for a typo, got %v", err)
+	}
+}
+
+func TestKillallReportsFailures(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+
+	for _, name := range []string{"api", "notes", "web"} {
+		initTestRepo(t, filepath.Join(testCfg.RigsBase, name))
+		if err := exec.Command("tmux", "new-session", "-d", "-s", name).Run(); err != nil {
+			t.Fatalf("Failed to create session %s: %v", name, err)
+		}
+	}
+
+	origKill := killSession
+	killSession = func(name string) error {
+		if name == "notes" {
+			return fmt.Errorf("server not responding")
+		}
+		return origKill(name)
+	}
+	t.Cleanup(func() { killSession = origKill })
+
+	var err error
+	captureStdout(t, func() {
+		cmd := killallCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{})
+		err = cmd.Execute()
+	})
+
+	want := "killed 2, failed 1 (notes: server not responding)"
+	if err == nil || err.Error() != want {
+		t.Errorf("killall error = %v, want %q", err, want)
+	}
+	sessions, _ := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	if strings.TrimSpace(string(sessions)) != "notes" {
+		t.Errorf("Expected only notes to survive, got %q", sessions)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{59 * time.Minute, "59m ago"},
+		{2*time.Hour + 30*time.Minute, "2h ago"},
+		{23 * time.Hour, "23h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.expected {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.expected)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"-2h", 0, true},
+		{"week", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAge(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCrewPruneDryRun(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	crewPath := testCfg.GetCrewPath("notes", "polecat_emma")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "polecat_emma/work", crewPath)
+
+	output := captureStdout(t, func() {
+		cmd := crewPruneCmd()
+		cmd.SetArgs([]string{"--dry-run"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("crew prune --dry-run error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Would remove 1 workspace(s)") || !strings.Contains(output, "polecat_emma") {
+		t.Errorf("Expected polecat_emma in dry run, got:\n%s", output)
+	}
+	if strings.Contains(output, "(y/N)") {
+		t.Errorf("Expected no prompt in dry run, got:\n%s", output)
+	}
+	if _, err := os.Stat(crewPath); err != nil {
+		t.Errorf("Expected workspace to be kept: %v", err)
+	}
+}
+
+func TestCrewPruneSkips(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	for _, name := range []string{"polecat_emma", "polecat_nux", "polecat_rex"} {
+		crewPath := testCfg.GetCrewPath("notes", name)
+		os.MkdirAll(filepath.Dir(crewPath), 0755)
+		runGitCmd(t, repoPath, "worktree", "add", "-b", name+"/work", crewPath)
+	}
+	dirtyPath := testCfg.GetCrewPath("notes", "polecat_nux")
+	os.WriteFile(filepath.Join(dirtyPath, "notes.txt"), []byte("unsaved\n"), 0644)
+	lockedPath := testCfg.GetCrewPath("notes", "polecat_rex")
+	runGitCmd(t, repoPath, "worktree", "lock", lockedPath)
+
+	testutil.StubStdin(t, "y\n")
+	var output string
+	warnings := captureStderr(t, func() {
+		output = captureStdout(t, func() {
+			cmd := crewPruneCmd()
+			cmd.SetArgs([]string{})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("crew prune error = %v", err)
+			}
+		})
+	})
+
+	if !strings.Contains(warnings, "Skipping polecat_nux: it has uncommitted changes") {
+		t.Errorf("Expected polecat_nux to be skipped, got:\n%s", warnings)
+	}
+	if !strings.Contains(warnings, "Skipping polecat_rex: it is locked") {
+		t.Errorf("Expected polecat_rex to be skipped, got:\n%s", warnings)
+	}
+	if !strings.Contains(output, "Removed 1 workspace(s)") {
+		t.Errorf("Expected only polecat_emma to be removed, got:\n%s", output)
+	}
+	if _, err := os.Stat(testCfg.GetCrewPath("notes", "polecat_emma")); !os.IsNotExist(err) {
+		t.Errorf("Expected polecat_emma to be removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dirtyPath, "notes.txt")); err != nil {
+		t.Errorf("Expected polecat_nux's changes to be kept: %v", err)
+	}
+	if _, err := os.Stat(lockedPath); err != nil {
+		t.Errorf("Expected locked polecat_rex to be kept: %v", err)
+	}
+}
+
+func TestCrewGcDelete(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	// alex/work is merged, blake/work has a commit main doesn't
+	runGitCmd(t, repoPath, "branch", "alex/work")
+	runGitCmd(t, repoPath, "checkout", "-b", "blake/work")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "unmerged")
+	runGitCmd(t, repoPath, "checkout", "main")
+
+	runGc := func(input string) string {
+		testutil.StubStdin(t, input)
+		return captureStdout(t, func() {
+			cmd := crewGcCmd()
+			cmd.SetArgs([]string{"--delete"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("crew gc --delete error = %v", err)
+			}
+		})
+	}
+
+	// Declining keeps the unmerged branch
+	output := runGc("n\n")
+	if !strings.Contains(output, "blake/work (rig: notes, 1 commit(s) not on the base branch)") {
+		t.Errorf("Expected blake/work's unmerged commit to be listed, got:\n%s", output)
+	}
+	if git.BranchExists(repoPath, "alex/work") {
+		t.Error("Expected merged alex/work to be deleted")
+	}
+	if !git.BranchExists(repoPath, "blake/work") {
+		t.Error("Expected unmerged blake/work to be kept")
+	}
+
+	runGc("y\n")
+	if git.BranchExists(repoPath, "blake/work") {
+		t.Error("Expected blake/work to be deleted after confirming")
+	}
+}
+
+func TestCrewPruneOrphanedSession(t *testing.T) {
+	useTestTmux(t)
+	testCfg := setupTestConfig(t)
+	initTestRepo(t, filepath.Join(testCfg.RigsBase, "notes"))
+
+	// The polecat's directory is gone but its session lingers
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "notes@polecat_ghost").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	testutil.StubStdin(t, "y\n")
+	output := captureStdout(t, func() {
+		cmd := crewPruneCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("crew prune error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "polecat_ghost (rig: notes, session only)") {
+		t.Errorf("Expected orphaned session to be listed, got:\n%s", output)
+	}
+	if exec.Command("tmux", "has-session", "-t", "notes@polecat_ghost").Run() == nil {
+		t.Errorf("Expected orphaned session to be killed")
+	}
+}
+
+// addWorkCrew creates a crew worktree on feat/<workName> with an in-progress
+// progress.md, creating the rig repo if needed
+func addWorkCrew(t *testing.T, testCfg *config.Config, rigName, crewName, workName string) {
+	t.Helper()
+
+	repoPath := testCfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		initTestRepo(t, repoPath)
+		runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	}
+
+	crewPath := testCfg.GetCrewPath(rigName, crewName)
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "feat/"+workName, crewPath)
+
+	progressPath := filepath.Join(crewPath, "work", workName, "progress.md")
+	os.MkdirAll(filepath.Dir(progressPath), 0755)
+	os.WriteFile(progressPath, []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+}
+
+func TestMvWork(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	crewPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", "-b", "tracy/work", crewPath)
+
+	// Work created in the main repo, with an uncommitted spec edit
+	runGitCmd(t, repoPath, "checkout", "-b", "feat/add-auth")
+	if err := work.Create(repoPath, "add-auth"); err != nil {
+		t.Fatalf("work.Create() error = %v", err)
+	}
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "Initialize work: add-auth")
+	specPath := filepath.Join(work.GetWorkPath(repoPath, "add-auth"), "spec.md")
+	os.WriteFile(specPath, []byte("# Add auth\n"), 0644)
+	chdirTemp(t, repoPath)
+
+	testutil.StubStdin(t, "\n")
+	captureStdout(t, func() {
+		cmd := mvWorkCmd()
+		cmd.SetArgs([]string{"add-auth", "--to", "tracy"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mv-work error = %v", err)
+		}
+	})
+
+	if branch, _ := git.GetCurrentBranch(crewPath); branch != "feat/add-auth" {
+		t.Errorf("Expected tracy on feat/add-auth, got %s", branch)
+	}
+	content, err := os.ReadFile(filepath.Join(work.GetWorkPath(crewPath, "add-auth"), "spec.md"))
+	if err != nil || string(content) != "# Add auth\n" {
+		t.Errorf("Expected committed spec in tracy's worktree, got %q (%v)", content, err)
+	}
+	if branch, _ := git.GetCurrentBranch(repoPath); branch != "main" {
+		t.Errorf("Expected main repo back on main, got %s", branch)
+	}
+}
+
+func TestCreateReviewer(t *testing.T) {
+	testCfg := setupTestConfig(t)
+
+	repoPath := filepath.Join(testCfg.RigsBase, "notes")
+	initTestRepo(t, repoPath)
+	runGitCmd(t, repoPath, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+	runGitCmd(t, repoPath, "checkout", "-b", "feat/add-auth")
+	if err := work.Create(repoPath, "add-auth"); err != nil {
+		t.Fatalf("work.Create() error = %v", err)
+	}
+	runGitCmd(t, repoPath, "add", ".")
+	runGitCmd(t, repoPath, "commit", "-m", "Initialize work: add-auth")
+	runGitCmd(t, repoPath, "checkout", "main")
+
+	// The builder keeps working on the feature branch
+	builderPath := testCfg.GetCrewPath("notes", "tracy")
+	os.MkdirAll(filepath.Dir(builderPath), 0755)
+	runGitCmd(t, repoPath, "worktree", "add", builderPath, "feat/add-auth")
+	featureHead, err := git.CurrentCommitHash(builderPath)
+	if err != nil {
+		t.Fatalf("CurrentCommitHash() error = %v", err)
+	}
+
+	formulaFile := filepath.Join(t.TempDir(), "review.md")
+	os.WriteFile(formulaFile, []byte("# Review Formula\n\n### Phase 1: Read\n"), 0644)
+
+	repo, err := git.OpenRepo(repoPath)
+	if err != nil {
+		t.Fatalf("OpenRepo() error = %v", err)
+	}
+	var name, crewPath string
+	captureStdout(t, func() {
+		name, crewPath, err = createReviewer(repo, "notes", "add-auth", formulaFile, work.HookOptions{})
+	})
+	if err != nil {
+		t.Fatalf("createReviewer() error = %v", err)
+	}
+
+	if crewPath != testCfg.GetCrewPath("notes", name) {
+		t.Errorf("Expected reviewer at %s, got %s", testCfg.GetCrewPath("notes", name), crewPath)
+	}
+	if branch, _ := git.GetCurrentBranch(crewPath); branch != "review/add-auth" {
+		t.Errorf("Expected reviewer on review/add-auth, got %s", branch)
+	}
+
+	hook, err := os.ReadFile(filepath.Join(work.GetWorkPath(crewPath, "add-auth"), work.ReviewHookFileName))
+	if err != nil {
+		t.Fatalf("Expected a review hook: %v", err)
+	}
+	if !strings.Contains(string(hook), "You are reviewing: **add-auth**") || !strings.Contains(string(hook), "work/add-auth/review-formula.md") {
+		t.Errorf("Unexpected review hook content:\n%s", hook)
+	}
+	if git.IsDirty(crewPath) {
+		t.Error("Expected the review hook to be committed")
+	}
+
+	// The builder's branch and workspace are untouched
+	if branch, _ := git.GetCurrentBranch(builderPath); branch != "feat/add-auth" {
+		t.Errorf("Expected tracy to stay on feat/add-auth, got %s", branch)
+	}
+	if head, _ := git.CurrentCommitHash(builderPath); head != featureHead {
+		t.Errorf("Expected feat/add-auth to stay at %s, got %s", featureHead, head)
+	}
+
+	captureStdout(t, func() {
+		_, _, err = createReviewer(repo, "notes", "add-auth", formulaFile, work.HookOptions{})
+	})
+	if err == nil || !strings.Contains(err.Error(), "review branch already exists") {
+		t.Errorf("Expected an error for a second reviewer, got %v", err)
+	}
+}
+
+func TestWorkStatusRigFilter(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	addWorkCrew(t, testCfg, "api", "tracy", "add-auth")
+	addWorkCrew(t, testCfg, "notes", "alex", "search")
+
+	for _, args := range [][]string{{"notes"}, {"--rig", "notes"}} {
+		output := captureStdout(t, func() {
+			cmd := workStatusCmd()
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("work status %v error = %v", args, err)
+			}
+		})
+
+		if !strings.Contains(output, "search") || !strings.Contains(output, "🏗️  notes") {
+			t.Errorf("work status %v: expected notes work, got:\n%s", args, output)
+		}
+		if strings.Contains(output, "add-auth") || strings.Contains(output, "🏗️  api") {
+			t.Errorf("work status %v: expected api to be excluded, got:\n%s", args, output)
+		}
+	}
+
+	// Without a filter every rig is shown
+	output := captureStdout(t, func() {
+		cmd := workStatusCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("work status error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "add-auth") || !strings.Contains(output, "search") {
+		t.Errorf("Expected work from both rigs, got:\n%s", output)
+	}
+}
+
+func TestWorkStatusSelfWork(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	addWorkCrew(t, testCfg, "notes", "tracy", "search")
+
+	// The rig's own checkout is on add-auth's feature branch, as after --self
+	repoPath := testCfg.GetRepoPath("notes")
+	runGitCmd(t, repoPath, "checkout", "-q", "-b", "feat/add-auth")
+	progressPath := filepath.Join(repoPath, "work", "add-auth", "progress.md")
+	os.MkdirAll(filepath.Dir(progressPath), 0755)
+	os.WriteFile(progressPath, []byte("# Progress\n\n## Status: In Progress\n"), 0644)
+
+	chdirTemp(t, repoPath)
+	output := captureStdout(t, func() {
+		cmd := workStatusCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("work status error = %v", err)
+		}
+	})
+
+	var selfLines, searchLines int
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "add-auth") {
+			selfLines++
+			if !strings.Contains(line, "self") || !strings.Contains(line, "In Progress") {
+				t.Errorf("Expected add-auth assigned to self, got %q", line)
+			}
+		}
+		if strings.Contains(line, "search") {
+			searchLines++
+		}
+	}
+	if selfLines != 1 || searchLines != 1 {
+		t.Errorf("Expected add-auth and search once each, got:\n%s", output)
+	}
+}
+
+func TestWorkStatusProgressErrors(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	addWorkCrew(t, testCfg, "notes", "tracy", "add-auth")
+	addWorkCrew(t, testCfg, "notes", "alex", "search")
+
+	os.Remove(filepath.Join(testCfg.GetCrewPath("notes", "tracy"), "work", "add-auth", "progress.md"))
+	os.WriteFile(filepath.Join(testCfg.GetCrewPath("notes", "alex"), "work", "search", "progress.md"), []byte("no status here\n"), 0644)
+
+	output := captureStdout(t, func() {
+		cmd := workStatusCmd()
+		cmd.SetArgs([]string{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("work status error = %v", err)
+		}
+	})
+
+	found := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "add-auth") {
+			found["add-auth"] = true
+			if !strings.Contains(line, "No progress file") {
+				t.Errorf("Expected add-auth to show a missing progress file, got %q", line)
+			}
+		}
+		if strings.Contains(line, "search") {
+			found["search"] = true
+			if !strings.Contains(line, "Malformed progress") {
+				t.Errorf("Expected search to show malformed progress, got %q", line)
+			}
+		}
+	}
+	if !found["add-auth"] || !found["search"] {
+		t.Errorf("Expected rows for add-auth and search, got:\n%s", output)
+	}
+}
+
+func TestCrewAndWorkOutputSorted(t *testing.T) {
+	testCfg := setupTestConfig(t)
+	addWorkCrew(t, testCfg, "web", "tracy", "redesign")
+	addWorkCrew(t, testCfg, "api", "sam", "rate-limit")
+	addWorkCrew(t, testCfg, "api", "alex", "add-auth")
+	addWorkCrew(t, testCfg, "notes", "polecat_emma", "search")
+	addWorkCrew(t, testCfg, "docs", "max", "tutorial")
+
+	commands := map[string]func() *cobra.Command{
+		"crew ls":     crewListCmd,
+		"work status": workStatusCmd,
+	}
+	for name, newCmd := range commands {
+		run := func() string {
+			return captureStdout(t, func() {
+				cmd := newCmd()
+				cmd.SetArgs([]string{})
+				if err := cmd.Execute(); err != nil {
+					t.Fatalf("%s error = %v", name, err)
+				}
+			})
+		}
+
+		first := run()
+		for i := 0; i < 5; i++ {
+			if output := run(); output != first {
+				t.Fatalf("%s output changed between runs:\n%s\nvs\n%s", name, first, output)
+			}
+		}
+
+		// Rigs in name order, and items within a rig in name order
+		order := []string{"🏗️  api", "🏗️  docs", "🏗️  notes", "🏗️  web"}
+		if name == "crew ls" {
+			order = append(order[:1], append([]string{"alex", "sam"}, order[1:]...)...)
+		} else {
+			order = append(order[:1], append([]string{"add-auth", "rate-limit"}, order[1:]...)...)
+		}
+		last := -1
+		for _, want := range order {
+			i := strings.Index(first, want)
+			if i <= last {
+				t.Errorf("%s: expected %q after previous entries, got:\n%s", name, want, first)
+				break
+			}
+			last = i
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoTool is returned when no supported clipboard command is installed
+var ErrNoTool = errors.New("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+
+// Clipboard copies text to the system clipboard
+type Clipboard interface {
+	Copy(text string) error
+}
+
+// Command is a Clipboard that pipes text into an external tool
+type Command struct {
+	Args []string
+}
+
+// Copy runs the tool with text on stdin
+func (c Command) Copy(text string) error {
+	cmd := exec.Command(c.Args[0], c.Args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy with %s: %w\n%s", c.Args[0], err, string(output))
+	}
+	return nil
+}
+
+// Detect returns a Clipboard backed by the first available tool for this system
+func Detect() (Clipboard, error) {
+	args, err := selectTool(runtime.GOOS, os.Getenv, exec.LookPath)
+	if err != nil {
+		return nil, err
+	}
+	return Command{Args: args}, nil
+}
+
+// selectTool picks the clipboard command for an OS and environment. Wayland
+// sessions prefer wl-copy; X11 falls back to xclip then xsel.
+func selectTool(goos string, getenv func(string) string, lookPath func(string) (string, error)) ([]string, error) {
+	var candidates [][]string
+	switch goos {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		x11 := [][]string{
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+		if getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append([][]string{{"wl-copy"}}, x11...)
+		} else {
+			candidates = append(x11, []string{"wl-copy"})
+		}
+	}
+
+	for _, args := range candidates {
+		if _, err := lookPath(args[0]); err == nil {
+			return args, nil
+		}
+	}
+	return nil, ErrNoTool
+}
@@ -0,0 +1,51 @@
+package clipboard
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSelectTool(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		env       map[string]string
+		installed []string
+		expected  []string
+		wantErr   bool
+	}{
+		{"macOS", "darwin", nil, []string{"pbcopy"}, []string{"pbcopy"}, false},
+		{"macOS without pbcopy", "darwin", nil, nil, nil, true},
+		{"wayland prefers wl-copy", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"wl-copy", "xclip"}, []string{"wl-copy"}, false},
+		{"wayland falls back to xclip", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"xclip"}, []string{"xclip", "-selection", "clipboard"}, false},
+		{"x11 prefers xclip", "linux", map[string]string{"DISPLAY": ":0"}, []string{"wl-copy", "xclip", "xsel"}, []string{"xclip", "-selection", "clipboard"}, false},
+		{"x11 falls back to xsel", "linux", map[string]string{"DISPLAY": ":0"}, []string{"xsel"}, []string{"xsel", "--clipboard", "--input"}, false},
+		{"linux with nothing installed", "linux", nil, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			lookPath := func(file string) (string, error) {
+				for _, name := range tt.installed {
+					if name == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+
+			result, err := selectTool(tt.goos, getenv, lookPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectTool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrNoTool) {
+				t.Errorf("Expected ErrNoTool, got %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("selectTool() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 // Config holds all configuration for rig
@@ -12,6 +15,11 @@ type Config struct {
 	UseCC            bool
 	DefaultBranch    string
 	ClaudeInitPrompt string
+	SetupScript      string
+
+	// Whether the bases came from RIGS_BASE/CREW_BASE rather than defaults
+	rigsBaseSet bool
+	crewBaseSet bool
 }
 
 // Load reads configuration from environment variables
@@ -28,7 +36,7 @@ func Load() *Config {
 		crewBase = filepath.Join(home, "crew")
 	}
 
-	useCC := os.Getenv("RIG_USE_CC") == "true"
+	useCC := resolveUseCC(os.Getenv("RIG_USE_CC"), os.Getenv("TERM_PROGRAM"))
 
 	defaultBranch := os.Getenv("RIG_DEFAULT_BRANCH")
 	if defaultBranch == "" {
@@ -37,15 +45,143 @@ func Load() *Config {
 
 	claudeInitPrompt := os.Getenv("RIG_CLAUDE_INIT_PROMPT")
 
+	// Only run a setup script when asked to; a repo's own script must not
+	// run just because it was cloned
+	setupScript := os.Getenv("RIG_SETUP_SCRIPT")
+
 	return &Config{
 		RigsBase:         rigsBase,
 		CrewBase:         crewBase,
 		UseCC:            useCC,
 		DefaultBranch:    defaultBranch,
 		ClaudeInitPrompt: claudeInitPrompt,
+		SetupScript:      setupScript,
+		rigsBaseSet:      os.Getenv("RIGS_BASE") != "",
+		crewBaseSet:      os.Getenv("CREW_BASE") != "",
+	}
+}
+
+// Validate checks the configuration for mistakes that would otherwise only
+// surface deep inside a command: explicitly set bases that aren't directories
+// and can't be created, and a default branch that isn't a valid branch name
+func (c *Config) Validate() error {
+	for _, base := range c.explicitBases() {
+		if err := checkBaseDir(base.env, base.path); err != nil {
+			return err
+		}
+	}
+
+	if !validBranchName(c.DefaultBranch) {
+		return fmt.Errorf("RIG_DEFAULT_BRANCH %q is not a valid branch name", c.DefaultBranch)
+	}
+
+	return nil
+}
+
+// Warnings returns problems rig can work around, like an explicitly set base
+// that doesn't exist yet and will be created when first needed
+func (c *Config) Warnings() []string {
+	var warnings []string
+	for _, base := range c.explicitBases() {
+		if _, err := os.Stat(base.path); os.IsNotExist(err) {
+			warnings = append(warnings, fmt.Sprintf("%s %s does not exist yet; it will be created when needed", base.env, base.path))
+		}
+	}
+	return warnings
+}
+
+type baseDir struct {
+	env  string
+	path string
+}
+
+// explicitBases returns the bases set through the environment
+func (c *Config) explicitBases() []baseDir {
+	var bases []baseDir
+	if c.rigsBaseSet {
+		bases = append(bases, baseDir{"RIGS_BASE", c.RigsBase})
+	}
+	if c.crewBaseSet {
+		bases = append(bases, baseDir{"CREW_BASE", c.CrewBase})
+	}
+	return bases
+}
+
+// checkBaseDir errors if path exists but isn't a directory, or if it's
+// missing and its nearest existing ancestor isn't a directory either
+func checkBaseDir(env, path string) error {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if info.IsDir() {
+				return nil
+			}
+			if dir == path {
+				return fmt.Errorf("%s %s is not a directory", env, path)
+			}
+			return fmt.Errorf("%s %s can't be created: %s is not a directory", env, path, dir)
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return nil
+		}
 	}
 }
 
+// validBranchName applies the main rules of git check-ref-format to a
+// branch name, without shelling out to git
+func validBranchName(name string) bool {
+	if name == "" || name == "@" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	if strings.ContainsAny(name, " ~^:?*[\\\x7f") || strings.Contains(name, "..") || strings.Contains(name, "@{") {
+		return false
+	}
+	for _, r := range name {
+		if r < 0x20 {
+			return false
+		}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return false
+		}
+	}
+	return !strings.HasSuffix(name, ".")
+}
+
+// resolveUseCC decides whether to use iTerm2 control mode. "true" and "false"
+// are explicit; "auto" enables it only when running under iTerm2.
+func resolveUseCC(setting, termProgram string) bool {
+	switch setting {
+	case "true":
+		return true
+	case "auto":
+		return termProgram == "iTerm.app"
+	default:
+		return false
+	}
+}
+
+// Session layouts accepted by --layout
+const (
+	LayoutNative = "native"
+	LayoutCC     = "cc"
+)
+
+// LayoutUseCC returns whether a session with the given layout uses iTerm2
+// control mode. An empty layout means the configured default.
+func (c *Config) LayoutUseCC(layout string) (bool, error) {
+	switch layout {
+	case "":
+		return c.UseCC, nil
+	case LayoutNative:
+		return false, nil
+	case LayoutCC:
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown layout: %s (expected %s or %s)", layout, LayoutNative, LayoutCC)
+}
+
 // GetRepoPath returns the full path to a repo
 func (c *Config) GetRepoPath(name string) string {
 	return filepath.Join(c.RigsBase, name)
@@ -56,11 +192,159 @@ func (c *Config) GetCrewPath(rig, name string) string {
 	return filepath.Join(c.CrewBase, rig, name)
 }
 
+// EnsureRigsBase creates RigsBase if it doesn't exist yet
+func (c *Config) EnsureRigsBase() error {
+	return ensureDir(c.RigsBase)
+}
+
+func ensureDir(path string) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return nil
+}
+
 // GetCrewSessionName returns the tmux session name for a crew member
 func (c *Config) GetCrewSessionName(rig, name string) string {
 	return rig + "@" + name
 }
 
+// GetSetupScript returns the setup script to run in new sessions for a
+// workspace, or "" if there is none. There is none unless RIG_SETUP_SCRIPT
+// names one; a relative path is resolved against the workspace, and the
+// script must be executable.
+func (c *Config) GetSetupScript(dir string) string {
+	if c.SetupScript == "" {
+		return ""
+	}
+
+	script := c.SetupScript
+	if !filepath.IsAbs(script) {
+		script = filepath.Join(dir, script)
+	}
+
+	info, err := os.Stat(script)
+	if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return ""
+	}
+	return script
+}
+
+// EnvFile is the per-workspace file of KEY=VALUE lines exported into new sessions
+var EnvFile = filepath.Join(".rig", "env")
+
+// envKeyPattern matches valid shell variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadEnvFile reads KEY=VALUE lines from a file. Blank lines and # comments
+// are skipped, a leading "export " is allowed, and values may be wrapped in
+// single or double quotes. A missing file yields no variables.
+func LoadEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE, got %q", path, i+1, line)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// GetEnv returns the variables from a workspace's env file
+func (c *Config) GetEnv(dir string) (map[string]string, error) {
+	return LoadEnvFile(filepath.Join(dir, EnvFile))
+}
+
+// ReposFile is the optional per-rig manifest of extra repos opened alongside
+// the rig's own repo, one path per line
+var ReposFile = filepath.Join(".rig", "repos")
+
+// LoadReposFile reads repo paths from a manifest. Blank lines and # comments
+// are skipped, "~/" expands to the home directory, and relative paths are
+// resolved against baseDir. A missing file yields no repos.
+func LoadReposFile(path, baseDir string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	repos := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "~/") {
+			line = filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(line, "~/"))
+		} else if !filepath.IsAbs(line) {
+			line = filepath.Join(baseDir, line)
+		}
+		repos = append(repos, filepath.Clean(line))
+	}
+	return repos, nil
+}
+
+// GetRepos returns the extra repos listed in a rig's repos manifest
+func (c *Config) GetRepos(dir string) ([]string, error) {
+	return LoadReposFile(filepath.Join(dir, ReposFile), dir)
+}
+
+// ValidateRigName checks that a rig name can be used as a tmux session name
+// and as the rig half of a crew session name
+func ValidateRigName(name string) error {
+	if name == "" {
+		return fmt.Errorf("rig name cannot be empty")
+	}
+
+	// '@' separates rig from crew; ':' and whitespace break tmux targets
+	if strings.ContainsAny(name, "/\\:@ \t\n") {
+		return fmt.Errorf("rig name cannot contain special characters (/, \\, :, @, whitespace): %s", name)
+	}
+
+	// A leading - would be read as a tmux flag
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("rig name cannot start with -: %s", name)
+	}
+
+	return nil
+}
+
+// ParseSessionName splits a session name into its rig and crew parts. Crew
+// sessions have the form <rig>@<crew>; the split is on the first "@" so rig
+// names must not contain one. Anything else is a rig session.
+func ParseSessionName(name string) (rig, crew string, isCrew bool) {
+	parts := strings.SplitN(name, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return name, "", false
+	}
+	return parts[0], parts[1], true
+}
+
 // GetCrewBranchName returns the branch name for a crew member
 func (c *Config) GetCrewBranchName(name string) string {
 	return name + "/work"
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -21,6 +23,7 @@ func TestLoad(t *testing.T) {
 	}()
 
 	t.Run("default values", func(t *testing.T) {
+		t.Setenv("RIG_SETUP_SCRIPT", "")
 		os.Unsetenv("RIGS_BASE")
 		os.Unsetenv("CREW_BASE")
 		os.Unsetenv("RIG_USE_CC")
@@ -44,6 +47,9 @@ func TestLoad(t *testing.T) {
 		if cfg.DefaultBranch != "main" {
 			t.Errorf("Expected DefaultBranch=main, got %s", cfg.DefaultBranch)
 		}
+		if cfg.SetupScript != "" {
+			t.Errorf("Expected no setup script by default, got %s", cfg.SetupScript)
+		}
 	})
 
 	t.Run("custom values", func(t *testing.T) {
@@ -69,6 +75,54 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestResolveUseCC(t *testing.T) {
+	tests := []struct {
+		setting     string
+		termProgram string
+		expected    bool
+	}{
+		{"", "", false},
+		{"", "iTerm.app", false},
+		{"false", "iTerm.app", false},
+		{"true", "", true},
+		{"true", "Apple_Terminal", true},
+		{"auto", "iTerm.app", true},
+		{"auto", "Apple_Terminal", false},
+		{"auto", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := resolveUseCC(tt.setting, tt.termProgram); got != tt.expected {
+			t.Errorf("resolveUseCC(%q, %q) = %v, want %v", tt.setting, tt.termProgram, got, tt.expected)
+		}
+	}
+}
+
+func TestLayoutUseCC(t *testing.T) {
+	tests := []struct {
+		layout   string
+		useCC    bool
+		expected bool
+	}{
+		{"", false, false},
+		{"", true, true},
+		{"native", true, false},
+		{"cc", false, true},
+	}
+
+	for _, tt := range tests {
+		c := &Config{UseCC: tt.useCC}
+		got, err := c.LayoutUseCC(tt.layout)
+		if err != nil || got != tt.expected {
+			t.Errorf("LayoutUseCC(%q) with UseCC %v = %v, %v, want %v", tt.layout, tt.useCC, got, err, tt.expected)
+		}
+	}
+
+	if _, err := (&Config{}).LayoutUseCC("tiled"); err == nil || !strings.Contains(err.Error(), "unknown layout: tiled") {
+		t.Errorf("Expected unknown layout error, got %v", err)
+	}
+}
+
 func TestGetRepoPath(t *testing.T) {
 	cfg := &Config{RigsBase: "/test/git"}
 
@@ -112,3 +166,208 @@ func TestGetCrewBranchName(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, branchName)
 	}
 }
+
+func TestParseSessionName(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantRig    string
+		wantCrew   string
+		wantIsCrew bool
+	}{
+		{"notes", "notes", "", false},
+		{"notes@tracy", "notes", "tracy", true},
+		{"my-app@polecat_emma", "my-app", "polecat_emma", true},
+		{"notes@tracy@extra", "notes", "tracy@extra", true},
+		{"@tracy", "@tracy", "", false},
+		{"notes@", "notes@", "", false},
+		{"@", "@", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rig, crew, isCrew := ParseSessionName(tt.name)
+			if rig != tt.wantRig || crew != tt.wantCrew || isCrew != tt.wantIsCrew {
+				t.Errorf("ParseSessionName(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.name, rig, crew, isCrew, tt.wantRig, tt.wantCrew, tt.wantIsCrew)
+			}
+		})
+	}
+}
+
+func TestValidateRigName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"notes", false},
+		{"my-app", false},
+		{"my.app", false},
+		{"my_app2", false},
+		{"", true},
+		{"foo@bar", true},
+		{"foo:bar", true},
+		{"foo/bar", true},
+		{"foo bar", true},
+		{"-flag", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRigName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRigName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantErr   bool
+		wantWarns int
+	}{
+		{"existing bases", Config{RigsBase: tmpDir, CrewBase: tmpDir, DefaultBranch: "main", rigsBaseSet: true, crewBaseSet: true}, false, 0},
+		{"missing base can be created", Config{RigsBase: tmpDir, CrewBase: filepath.Join(tmpDir, "a", "crew"), DefaultBranch: "main", rigsBaseSet: true, crewBaseSet: true}, false, 1},
+		{"base is a file", Config{RigsBase: file, DefaultBranch: "main", rigsBaseSet: true}, true, 0},
+		{"base under a file", Config{CrewBase: filepath.Join(file, "crew"), DefaultBranch: "main", crewBaseSet: true}, true, 0},
+		{"default bases aren't checked", Config{RigsBase: file, CrewBase: filepath.Join(tmpDir, "missing"), DefaultBranch: "main"}, false, 0},
+		{"nested default branch", Config{DefaultBranch: "release/2.0"}, false, 0},
+		{"empty default branch", Config{DefaultBranch: ""}, true, 0},
+		{"default branch with space", Config{DefaultBranch: "my branch"}, true, 0},
+		{"default branch with ..", Config{DefaultBranch: "main..dev"}, true, 0},
+		{"default branch ending in .lock", Config{DefaultBranch: "main.lock"}, true, 0},
+		{"default branch starting with -", Config{DefaultBranch: "-main"}, true, 0},
+		{"default branch with trailing slash", Config{DefaultBranch: "main/"}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if warnings := tt.cfg.Warnings(); len(warnings) != tt.wantWarns {
+				t.Errorf("Warnings() = %v, want %d warnings", warnings, tt.wantWarns)
+			}
+		})
+	}
+}
+
+func TestGetSetupScript(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{SetupScript: filepath.Join(".rig", "setup.sh")}
+
+	if script := cfg.GetSetupScript(dir); script != "" {
+		t.Errorf("Expected no script when absent, got %s", script)
+	}
+
+	scriptPath := filepath.Join(dir, ".rig", "setup.sh")
+	os.MkdirAll(filepath.Dir(scriptPath), 0755)
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\nnpm install\n"), 0644)
+	if script := cfg.GetSetupScript(dir); script != "" {
+		t.Errorf("Expected non-executable script to be skipped, got %s", script)
+	}
+
+	os.Chmod(scriptPath, 0755)
+	if script := cfg.GetSetupScript(dir); script != scriptPath {
+		t.Errorf("Expected %s, got %s", scriptPath, script)
+	}
+
+	absCfg := &Config{SetupScript: scriptPath}
+	if script := absCfg.GetSetupScript(t.TempDir()); script != scriptPath {
+		t.Errorf("Expected absolute script path %s, got %s", scriptPath, script)
+	}
+
+	disabled := &Config{}
+	if script := disabled.GetSetupScript(dir); script != "" {
+		t.Errorf("Expected no script when unset, got %s", script)
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		env, err := LoadEnvFile(filepath.Join(dir, "missing"))
+		if err != nil || len(env) != 0 {
+			t.Errorf("LoadEnvFile() = %v, %v; want empty, nil", env, err)
+		}
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		path := filepath.Join(dir, "env")
+		content := `# Project settings
+PORT=3000
+export NODE_ENV=development
+
+GREETING="hello world"
+SINGLE='single quoted'
+EQUATION=a=b
+EMPTY=
+`
+		os.WriteFile(path, []byte(content), 0644)
+
+		env, err := LoadEnvFile(path)
+		if err != nil {
+			t.Fatalf("LoadEnvFile() error = %v", err)
+		}
+		expected := map[string]string{
+			"PORT":     "3000",
+			"NODE_ENV": "development",
+			"GREETING": "hello world",
+			"SINGLE":   "single quoted",
+			"EQUATION": "a=b",
+			"EMPTY":    "",
+		}
+		if !reflect.DeepEqual(env, expected) {
+			t.Errorf("LoadEnvFile() = %v, want %v", env, expected)
+		}
+	})
+
+	t.Run("malformed lines", func(t *testing.T) {
+		for _, line := range []string{"NOEQUALS", "1BAD=x", "BAD KEY=x"} {
+			path := filepath.Join(dir, "bad")
+			os.WriteFile(path, []byte(line+"\n"), 0644)
+			if _, err := LoadEnvFile(path); err == nil {
+				t.Errorf("Expected error for %q", line)
+			}
+		}
+	})
+}
+
+func TestLoadReposFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", "/home/me")
+
+	repos, err := LoadReposFile(filepath.Join(dir, "missing"), dir)
+	if err != nil || len(repos) != 0 {
+		t.Errorf("LoadReposFile() = %v, %v; want empty, nil", repos, err)
+	}
+
+	path := filepath.Join(dir, "repos")
+	content := `# Repos opened with this rig
+../api
+
+/srv/shared/
+~/git/docs
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	repos, err = LoadReposFile(path, "/home/me/git/web")
+	if err != nil {
+		t.Fatalf("LoadReposFile() error = %v", err)
+	}
+	expected := []string{"/home/me/git/api", "/srv/shared", "/home/me/git/docs"}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("LoadReposFile() = %v, want %v", repos, expected)
+	}
+}
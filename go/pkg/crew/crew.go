@@ -1,15 +1,24 @@
 package crew
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/mstrand/rig/pkg/config"
 	"github.com/mstrand/rig/pkg/git"
+	"github.com/mstrand/rig/pkg/polecat"
+	"github.com/mstrand/rig/pkg/suggest"
 	"github.com/mstrand/rig/pkg/tmux"
+	"github.com/mstrand/rig/pkg/ui"
 )
 
 // ValidateCrewName validates a crew member name
@@ -40,6 +49,9 @@ func ValidateCrewName(name string) error {
 func InferRig(cfg *config.Config, explicitRig string) (string, error) {
 	// If explicitly provided, use it
 	if explicitRig != "" {
+		if err := config.ValidateRigName(explicitRig); err != nil {
+			return "", err
+		}
 		return explicitRig, nil
 	}
 
@@ -72,13 +84,22 @@ func InferRig(cfg *config.Config, explicitRig string) (string, error) {
 		}
 	}
 
+	// Check if pwd is inside a linked worktree located anywhere else; the rig
+	// is the main repo the worktree belongs to
+	if err == nil {
+		root, rootErr := git.GetRepoRoot(pwd)
+		mainRoot, mainErr := git.GetMainRepoRoot(pwd)
+		if rootErr == nil && mainErr == nil && !samePath(root, mainRoot) {
+			return filepath.Base(mainRoot), nil
+		}
+	}
+
 	// Check active tmux session
 	sessionName := tmux.GetCurrentSession()
 	if sessionName != "" {
 		// If it's a crew session (format: <rig>@<name>), extract rig
-		if strings.Contains(sessionName, "@") {
-			parts := strings.Split(sessionName, "@")
-			return parts[0], nil
+		if rig, _, isCrew := config.ParseSessionName(sessionName); isCrew {
+			return rig, nil
 		}
 
 		// If it's a regular rig session, use it directly
@@ -91,11 +112,317 @@ func InferRig(cfg *config.Config, explicitRig string) (string, error) {
 	return "", fmt.Errorf("could not infer rig. Use --rig=<repo> or run from within a repo in %s or %s", cfg.RigsBase, cfg.CrewBase)
 }
 
+// samePath reports whether two paths refer to the same location, resolving symlinks
+func samePath(a, b string) bool {
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return resolvedA == resolvedB
+}
+
+// SessionOptions returns the tmux session options for a rig or crew workspace.
+// An unreadable env file is reported and skipped rather than failing the session.
+func SessionOptions(cfg *config.Config, dir string) tmux.SessionOptions {
+	env, err := cfg.GetEnv(dir)
+	if err != nil {
+		ui.Warnf("Ignoring %s: %v", config.EnvFile, err)
+	}
+
+	return tmux.SessionOptions{
+		InitPrompt:  cfg.ClaudeInitPrompt,
+		SetupScript: cfg.GetSetupScript(dir),
+		Env:         env,
+	}
+}
+
+// attachSession attaches to a tmux session; replaced in tests
+var attachSession = tmux.AttachSession
+
+// createCrewSession creates a crew's tmux session; replaced in tests
+var createCrewSession = tmux.CreateCrewSession
+
+// MetaFile is the name of the metadata file kept in each crew workspace
+const MetaFile = ".rig-meta"
+
+// Meta is the metadata rig records about a crew workspace
+type Meta struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Rig and Name identify the workspace wherever it lives; older
+	// workspaces don't record them
+	Rig  string `json:"rig,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Layout is the session layout chosen with --layout; without one the
+	// configured layout is used
+	Layout string `json:"layout,omitempty"`
+}
+
+// WriteMeta records metadata in a crew workspace, keeping the file out of
+// git status via the repo's info/exclude
+func WriteMeta(crewPath string, meta Meta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode crew metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(crewPath, MetaFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write crew metadata: %w", err)
+	}
+	return git.AddExclude(crewPath, "/"+MetaFile)
+}
+
+// ReadMeta reads a crew workspace's metadata. Workspaces created before rig
+// kept metadata have none, and return nil without an error.
+func ReadMeta(crewPath string) (*Meta, error) {
+	data, err := os.ReadFile(filepath.Join(crewPath, MetaFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crew metadata: %w", err)
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", MetaFile, err)
+	}
+	return &meta, nil
+}
+
+// saveLayout records a crew workspace's session layout in its metadata, so
+// sessions recreated later get the same one
+func saveLayout(crewPath, layout string) error {
+	meta, err := ReadMeta(crewPath)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		meta = &Meta{}
+	}
+	meta.Layout = layout
+	return WriteMeta(crewPath, *meta)
+}
+
+// crewUseCC returns whether a crew workspace's sessions use iTerm2 control
+// mode: the layout recorded in its metadata, or else the configured one
+func crewUseCC(cfg *config.Config, crewPath string) bool {
+	meta, err := ReadMeta(crewPath)
+	if err != nil || meta == nil {
+		return cfg.UseCC
+	}
+	useCC, err := cfg.LayoutUseCC(meta.Layout)
+	if err != nil {
+		return cfg.UseCC
+	}
+	return useCC
+}
+
+// SessionUseCC returns whether a rig or crew session uses iTerm2 control
+// mode. A crew session uses the layout its workspace was added with.
+func SessionUseCC(cfg *config.Config, sessionName string) bool {
+	rigName, name, isCrew := config.ParseSessionName(sessionName)
+	if !isCrew {
+		return cfg.UseCC
+	}
+	return crewUseCC(cfg, Locate(cfg, rigName, name))
+}
+
+// Locate returns the worktree path of a crew workspace: its directory under
+// CrewBase, or wherever it was created with --here. A crew that doesn't exist
+// gets its CrewBase path.
+func Locate(cfg *config.Config, rigName, name string) string {
+	crewPath := cfg.GetCrewPath(rigName, name)
+	if pathExists(crewPath) {
+		return crewPath
+	}
+	for _, info := range discoverRig(cfg, rigName, nil) {
+		if info.Name == name {
+			return info.Path
+		}
+	}
+	return crewPath
+}
+
+// CrewInfo describes a crew workspace
+type CrewInfo struct {
+	Rig         string
+	Name        string
+	Path        string
+	Branch      string // empty when detached or unknown
+	SessionName string // <rig>@<name>, before tmux normalizes it
+	Running     bool   // the session is running
+	IsPolecat   bool
+}
+
+// NewCrewInfo describes the crew workspace name of a rig at path, checking
+// whether its session is among running, a snapshot of tmux sessions
+func NewCrewInfo(cfg *config.Config, rigName, name, path, branch string, running tmux.SessionSet) CrewInfo {
+	sessionName := cfg.GetCrewSessionName(rigName, name)
+	return CrewInfo{
+		Rig:         rigName,
+		Name:        name,
+		Path:        path,
+		Branch:      branch,
+		SessionName: sessionName,
+		Running:     running.Has(sessionName),
+		IsPolecat:   polecat.IsPolecat(name),
+	}
+}
+
+// Discover returns the crew workspaces of every rig, sorted by rig and name.
+// A rig's worktrees are authoritative: one is crew if it sits at
+// CrewBase/<rig>/<name> or its metadata names it, as for crew added with
+// --here. Directories under CrewBase that git doesn't list, e.g. because the
+// repo is gone, are included as well.
+func Discover(cfg *config.Config) ([]CrewInfo, error) {
+	// Without a tmux server nothing is running
+	running, _ := listSessionSet()
+	return DiscoverWith(cfg, running)
+}
+
+// DiscoverWith is Discover for callers that already have a snapshot of the
+// running tmux sessions
+func DiscoverWith(cfg *config.Config, running tmux.SessionSet) ([]CrewInfo, error) {
+	// Only repos in RigsBase have worktrees worth listing, but any rig with
+	// a directory under CrewBase may have crew
+	rigNames := []string{}
+	for _, base := range []string{cfg.RigsBase, cfg.CrewBase} {
+		entries, err := os.ReadDir(base)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", base, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || slices.Contains(rigNames, entry.Name()) {
+				continue
+			}
+			if base == cfg.RigsBase && !git.IsGitRepo(filepath.Join(base, entry.Name())) {
+				continue
+			}
+			rigNames = append(rigNames, entry.Name())
+		}
+	}
+	sort.Strings(rigNames)
+
+	var found []CrewInfo
+	for _, rigName := range rigNames {
+		found = append(found, discoverRig(cfg, rigName, running)...)
+	}
+	return found, nil
+}
+
+// DiscoverRig returns the crew workspaces of one rig, found the same way as
+// by Discover
+func DiscoverRig(cfg *config.Config, rigName string) []CrewInfo {
+	running, _ := listSessionSet()
+	return discoverRig(cfg, rigName, running)
+}
+
+// listSessionSet snapshots the running tmux sessions; replaced in tests
+var listSessionSet = tmux.LoadSessionSet
+
+// discoverRig returns the crew workspaces of one rig, sorted by name
+func discoverRig(cfg *config.Config, rigName string, running tmux.SessionSet) []CrewInfo {
+	crewDir := filepath.Join(cfg.CrewBase, rigName)
+	byName := make(map[string]CrewInfo)
+
+	if worktrees, err := git.ListWorktrees(cfg.GetRepoPath(rigName)); err == nil {
+		resolvedCrewDir := resolvePath(crewDir)
+		for _, wt := range worktrees {
+			if !pathExists(wt.Path) {
+				continue
+			}
+			if name := childName(resolvePath(wt.Path), resolvedCrewDir); name != "" {
+				byName[name] = NewCrewInfo(cfg, rigName, name, filepath.Join(crewDir, name), wt.Branch, running)
+				continue
+			}
+			meta, err := ReadMeta(wt.Path)
+			if err == nil && meta != nil && meta.Rig == rigName && meta.Name != "" {
+				byName[meta.Name] = NewCrewInfo(cfg, rigName, meta.Name, wt.Path, wt.Branch, running)
+			}
+		}
+	}
+
+	// Fall back to the directory layout for anything git didn't list
+	entries, _ := os.ReadDir(crewDir)
+	for _, entry := range entries {
+		if _, ok := byName[entry.Name()]; ok || !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(crewDir, entry.Name())
+		branch, _ := git.GetCurrentBranch(path)
+		byName[entry.Name()] = NewCrewInfo(cfg, rigName, entry.Name(), path, branch, running)
+	}
+
+	found := make([]CrewInfo, 0, len(byName))
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		found = append(found, byName[name])
+	}
+	return found
+}
+
+// childName returns the name of the entry of dir that path is, or "" if
+// path isn't directly inside dir. Both should have symlinks resolved.
+func childName(path, dir string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || strings.Contains(rel, string(filepath.Separator)) || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return rel
+}
+
+// resolvePath returns path with symlinks resolved, or path itself if that
+// fails
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// AddOptions controls how Add creates a crew workspace
+type AddOptions struct {
+	// Detached creates the session without attaching to it
+	Detached bool
+	// From branches the new crew off another crew's current branch instead
+	// of the base branch
+	From string
+	// NoSession creates only the worktree, without a tmux session
+	NoSession bool
+	// Base branches the new crew off this local branch instead of the
+	// rig's base branch
+	Base string
+	// FromRef branches the new crew off any committish, such as a tag or
+	// a commit SHA
+	FromRef string
+	// Here creates the worktree at this path instead of under CrewBase.
+	// The session name is the usual <rig>@<name>.
+	Here string
+	// ForceNewBranch deletes an existing <name>/work branch, after
+	// confirmation, and starts a new one instead of reusing it
+	ForceNewBranch bool
+	// Layout is the session layout, config.LayoutNative or config.LayoutCC,
+	// instead of the configured default. It's kept for the workspace's later
+	// sessions.
+	Layout string
+}
+
 // Add creates a new crew workspace
-func Add(cfg *config.Config, name, rigName string) error {
+func Add(cfg *config.Config, name, rigName string, opts AddOptions) error {
 	if err := ValidateCrewName(name); err != nil {
 		return err
 	}
+	if err := config.ValidateRigName(rigName); err != nil {
+		return err
+	}
+	useCC, err := cfg.LayoutUseCC(opts.Layout)
+	if err != nil {
+		return err
+	}
 
 	// Get repo path and validate it exists
 	repoPath := cfg.GetRepoPath(rigName)
@@ -103,7 +430,17 @@ func Add(cfg *config.Config, name, rigName string) error {
 		return fmt.Errorf("repo not found: %s", repoPath)
 	}
 
-	crewPath := cfg.GetCrewPath(rigName, name)
+	crewPath := Locate(cfg, rigName, name)
+	if opts.Here != "" {
+		here, err := filepath.Abs(opts.Here)
+		if err != nil {
+			return fmt.Errorf("invalid path %s: %w", opts.Here, err)
+		}
+		if pathExists(crewPath) && !samePath(crewPath, here) {
+			return fmt.Errorf("crew %s already exists at %s", name, crewPath)
+		}
+		crewPath = here
+	}
 	sessionName := cfg.GetCrewSessionName(rigName, name)
 	branchName := cfg.GetCrewBranchName(name)
 
@@ -113,39 +450,108 @@ func Add(cfg *config.Config, name, rigName string) error {
 		return err
 	}
 
+	// A branch that's checked out can't be deleted, including by this crew's
+	// own workspace
+	if opts.ForceNewBranch && git.BranchExists(repoPath, branchName) {
+		if wtPath, err := git.GetWorktreeForBranch(repoPath, branchName); err == nil {
+			return fmt.Errorf("can't recreate %s: it's checked out at %s\nRun 'rig crew remove %s --rig=%s' first", branchName, wtPath, name, rigName)
+		}
+	}
+
 	// Check if worktree already exists (idempotency)
 	if _, err := os.Stat(crewPath); err == nil {
+		// Keep the workspace's layout unless a new one was asked for
+		if opts.Layout == "" {
+			useCC = crewUseCC(cfg, crewPath)
+		} else if err := saveLayout(crewPath, opts.Layout); err != nil {
+			ui.Warnf("%v", err)
+		}
+		if opts.NoSession {
+			ui.Printf("Crew workspace already exists: %s\n", crewPath)
+			return nil
+		}
 		if tmux.SessionExists(sessionName) {
-			fmt.Printf("Crew workspace already exists and session is running\n")
-			fmt.Printf("Attaching to existing session: %s\n", sessionName)
-			return tmux.AttachSession(sessionName, cfg.UseCC)
+			ui.Printf("Crew workspace already exists and session is running\n")
+			if opts.Detached {
+				return nil
+			}
+			ui.Printf("Attaching to existing session: %s\n", sessionName)
+			return attachSession(sessionName, useCC)
 		}
 
-		fmt.Printf("Crew workspace exists but session is not running\n")
-		fmt.Printf("Recreating session...\n")
+		ui.Printf("Crew workspace exists but session is not running\n")
+		ui.Printf("Recreating session...\n")
 
-		if err := tmux.CreateCrewSession(sessionName, crewPath, rigName, name, branchName, cfg.UseCC, cfg.ClaudeInitPrompt); err != nil {
+		if err := createCrewSession(sessionName, crewPath, rigName, name, branchName, useCC, SessionOptions(cfg, crewPath)); err != nil {
 			return fmt.Errorf("failed to recreate session: %w", err)
 		}
 
-		fmt.Printf("✓ Session recreated: %s\n", sessionName)
-		return tmux.AttachSession(sessionName, cfg.UseCC)
+		ui.Printf("✓ Session recreated: %s\n", sessionName)
+		if opts.Detached {
+			return nil
+		}
+		return attachSession(sessionName, useCC)
+	}
+
+	// git can't create <name>/work next to a branch called <name>
+	if err := checkBranchCollision(repoPath, name, baseBranch); err != nil {
+		return err
+	}
+
+	// Branch off another branch or crew's work instead of the base branch
+	startPoint := baseBranch
+	if opts.Base != "" {
+		if !git.BranchExists(repoPath, opts.Base) {
+			return branchNotFound(repoPath, opts.Base)
+		}
+		startPoint = opts.Base
+	}
+	if opts.FromRef != "" {
+		if !git.RefExists(repoPath, opts.FromRef) {
+			return fmt.Errorf("ref not found: %s", opts.FromRef)
+		}
+		startPoint = opts.FromRef
+	}
+	if opts.From != "" {
+		sourceBranch, err := crewBranch(cfg, rigName, opts.From)
+		if err != nil {
+			return err
+		}
+		startPoint = sourceBranch
+	}
+
+	// An existing branch is reused as it is, which would silently drop the
+	// requested start point
+	if startPoint != baseBranch && !opts.ForceNewBranch && git.BranchExists(repoPath, branchName) {
+		return fmt.Errorf("branch %s already exists, so it can't start from %s\nUse --force-new-branch to start it over from %s, or a different crew name", branchName, startPoint, startPoint)
 	}
 
-	// Create crew directory
+	// Create crew directory, along with CrewBase on a first run
 	if err := os.MkdirAll(filepath.Dir(crewPath), 0755); err != nil {
 		return fmt.Errorf("failed to create crew directory: %w", err)
 	}
 
-	fmt.Printf("Creating crew workspace for %s on %s\n", name, rigName)
-	fmt.Printf("  Repo: %s\n", repoPath)
-	fmt.Printf("  Workspace: %s\n", crewPath)
-	fmt.Printf("  Branch: %s (from %s)\n", branchName, baseBranch)
+	ui.Printf("Creating crew workspace for %s on %s\n", name, rigName)
+	ui.Printf("  Repo: %s\n", repoPath)
+	ui.Printf("  Workspace: %s\n", crewPath)
+	ui.Printf("  Branch: %s (from %s)\n", branchName, startPoint)
 
 	// Check if branch already exists
 	useExistingBranch := false
-	if git.BranchExists(repoPath, branchName) {
-		fmt.Printf("Branch %s already exists\n", branchName)
+	if opts.ForceNewBranch && git.BranchExists(repoPath, branchName) {
+		ui.Warnf("Branch %s already exists; commits only on it will be lost", branchName)
+		fmt.Printf("Delete it and start over from %s? [y/N] ", startPoint)
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			return fmt.Errorf("cancelled")
+		}
+		if err := git.DeleteBranch(repoPath, branchName); err != nil {
+			return fmt.Errorf("failed to delete branch %s: %w", branchName, err)
+		}
+		ui.Printf("✓ Deleted branch: %s\n", branchName)
+	} else if git.BranchExists(repoPath, branchName) {
+		ui.Printf("Branch %s already exists\n", branchName)
 		fmt.Print("Use existing branch? [Y/n] ")
 		var response string
 		fmt.Scanln(&response)
@@ -155,32 +561,289 @@ func Add(cfg *config.Config, name, rigName string) error {
 		useExistingBranch = true
 	}
 
+	// A branch only on origin would diverge if we started a new one.
+	// Tracking it isn't offered when another start point was asked for.
+	trackRemote := false
+	if !useExistingBranch && !opts.ForceNewBranch && git.RemoteBranchExists(repoPath, "origin/"+branchName) {
+		ui.Warnf("Branch %s exists on origin but not locally", branchName)
+		if startPoint == baseBranch {
+			fmt.Printf("Track origin/%s? [Y/n] ", branchName)
+			var response string
+			fmt.Scanln(&response)
+			trackRemote = strings.ToLower(response) != "n"
+		}
+		if !trackRemote {
+			ui.Warnf("Starting a new %s from %s; it may diverge from origin/%s", branchName, startPoint, branchName)
+		}
+	}
+
 	// Create worktree
 	if useExistingBranch {
 		if err := git.CreateWorktreeFromExisting(repoPath, crewPath, branchName); err != nil {
 			return err
 		}
+	} else if trackRemote {
+		if err := git.CreateWorktreeTracking(repoPath, crewPath, branchName); err != nil {
+			cleanupWorktree(repoPath, crewPath, branchName)
+			return err
+		}
 	} else {
-		if err := git.CreateWorktree(repoPath, crewPath, branchName, baseBranch); err != nil {
+		if err := git.CreateWorktreeAt(repoPath, crewPath, branchName, startPoint); err != nil {
 			// Cleanup on failure
 			cleanupWorktree(repoPath, crewPath, branchName)
 			return err
 		}
 	}
 
-	fmt.Printf("✓ Crew workspace created: %s\n", crewPath)
+	ui.Printf("✓ Crew workspace created: %s\n", crewPath)
+	if err := WriteMeta(crewPath, Meta{CreatedAt: time.Now(), Rig: rigName, Name: name, Layout: opts.Layout}); err != nil {
+		ui.Warnf("%v", err)
+	}
+
+	if opts.NoSession {
+		ui.Printf("Start a session later with: rig crew start %s --rig=%s\n", name, rigName)
+		return nil
+	}
 
 	// Create tmux session
-	if err := tmux.CreateCrewSession(sessionName, crewPath, rigName, name, branchName, cfg.UseCC, cfg.ClaudeInitPrompt); err != nil {
-		fmt.Printf("Session creation failed, cleaning up worktree...\n")
-		cleanupWorktree(repoPath, crewPath, branchName)
-		return fmt.Errorf("failed to create session: %w", err)
+	// Keep the worktree if only the session failed; it's fine to retry
+	if err := createCrewSession(sessionName, crewPath, rigName, name, branchName, useCC, SessionOptions(cfg, crewPath)); err != nil {
+		return fmt.Errorf("worktree created but session failed: %w\nRun 'rig crew start %s --rig=%s' to retry", err, name, rigName)
 	}
 
-	fmt.Printf("✓ Session created: %s\n", sessionName)
+	ui.Printf("✓ Session created: %s\n", sessionName)
+
+	if opts.Detached {
+		return nil
+	}
 
 	// Attach to session
-	return tmux.AttachSession(sessionName, cfg.UseCC)
+	return attachSession(sessionName, useCC)
+}
+
+// PruneOptions selects which crew workspaces prune removes. By default only
+// polecats are selected.
+type PruneOptions struct {
+	// All includes named crew as well as polecats
+	All bool
+	// Polecats restricts the selection to polecats even when Stale is set
+	Polecats bool
+	// Stale, if non-zero, selects only crew whose last commit is older than
+	// this. Without Polecats it also includes named crew.
+	Stale time.Duration
+}
+
+// PruneCandidate is a crew workspace selected for pruning
+type PruneCandidate struct {
+	Name       string
+	RigName    string
+	Path       string
+	Session    string    // tmux session name
+	Orphaned   bool      // the session is running but the workspace is gone
+	LastActive time.Time // zero unless PruneOptions.Stale was set
+}
+
+// PruneCandidates returns the crew workspaces across all rigs that prune
+// would remove, plus any crew sessions still running for workspaces that no
+// longer exist
+func PruneCandidates(cfg *config.Config, opts PruneOptions) ([]PruneCandidate, error) {
+	// Without a tmux server nothing is running
+	running, _ := listSessionSet()
+	workspaces, err := DiscoverWith(cfg, running)
+	if err != nil {
+		return nil, err
+	}
+
+	includeNamed := opts.All || (opts.Stale > 0 && !opts.Polecats)
+
+	var candidates []PruneCandidate
+	knownSessions := make(map[string]bool)
+	for _, info := range workspaces {
+		sessionName := tmux.NormalizeSessionName(info.SessionName)
+		knownSessions[sessionName] = true
+		if !includeNamed && !info.IsPolecat {
+			continue
+		}
+
+		candidate := PruneCandidate{
+			Name:    info.Name,
+			RigName: info.Rig,
+			Path:    info.Path,
+			Session: sessionName,
+		}
+
+		if opts.Stale > 0 {
+			last, err := lastActive(candidate.Path)
+			if err != nil || time.Since(last) <= opts.Stale {
+				continue
+			}
+			candidate.LastActive = last
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	// Reconcile with tmux: crew sessions whose workspace directory is gone
+	// would otherwise never be found by the scan above. Only sessions of
+	// known rigs count; others may just look like crew sessions. tmux turns
+	// "." into "_", so rigs are matched by their normalized names.
+	rigNames := make(map[string]string)
+	entries, _ := os.ReadDir(cfg.RigsBase)
+	for _, entry := range entries {
+		if entry.IsDir() && git.IsGitRepo(cfg.GetRepoPath(entry.Name())) {
+			rigNames[tmux.NormalizeSessionName(entry.Name())] = entry.Name()
+		}
+	}
+	for _, session := range slices.Sorted(maps.Keys(running)) {
+		sessionRig, crewName, isCrew := config.ParseSessionName(session)
+		if !isCrew || knownSessions[session] {
+			continue
+		}
+		if !includeNamed && !polecat.IsPolecat(crewName) {
+			continue
+		}
+		rigName, ok := rigNames[sessionRig]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, PruneCandidate{
+			Name:     crewName,
+			RigName:  rigName,
+			Path:     cfg.GetCrewPath(rigName, crewName),
+			Session:  session,
+			Orphaned: true,
+		})
+	}
+
+	return candidates, nil
+}
+
+// lastActive returns when a crew workspace was last worked on: the later of
+// its HEAD commit and its creation, so a workspace created recently from an
+// old commit isn't stale
+func lastActive(crewPath string) (time.Time, error) {
+	last, err := git.LastCommitTime(crewPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if meta, err := ReadMeta(crewPath); err == nil && meta != nil && meta.CreatedAt.After(last) {
+		last = meta.CreatedAt
+	}
+	return last, nil
+}
+
+// OrphanBranches returns the crew branches (<name>/work) of a rig that no
+// worktree has checked out, e.g. left behind by removing a worktree by hand
+func OrphanBranches(cfg *config.Config, rigName string) ([]string, error) {
+	repoPath := cfg.GetRepoPath(rigName)
+	branches, err := git.ListBranches(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := git.ListWorktrees(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return orphanBranches(cfg, branches, worktrees), nil
+}
+
+// orphanBranches returns the branches that follow the crew branch naming and
+// aren't checked out in any of worktrees
+func orphanBranches(cfg *config.Config, branches []string, worktrees []git.Worktree) []string {
+	checkedOut := make(map[string]bool)
+	for _, wt := range worktrees {
+		checkedOut[wt.Branch] = true
+	}
+
+	orphans := []string{}
+	for _, branch := range branches {
+		name, _, _ := strings.Cut(branch, "/")
+		if ValidateCrewName(name) != nil || cfg.GetCrewBranchName(name) != branch {
+			continue
+		}
+		if !checkedOut[branch] {
+			orphans = append(orphans, branch)
+		}
+	}
+	return orphans
+}
+
+// Spawn creates count polecat workspaces on a rig, all detached, and returns
+// the names created. If one fails, the ones already created are kept and
+// returned along with the error.
+func Spawn(cfg *config.Config, rigName string, count int) ([]string, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1")
+	}
+
+	// Names already taken on this rig
+	used := []string{}
+	if entries, err := os.ReadDir(filepath.Join(cfg.CrewBase, rigName)); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				used = append(used, entry.Name())
+			}
+		}
+	}
+
+	names := polecat.NewGenerator(used)
+	created := []string{}
+	for i := 0; i < count; i++ {
+		name, err := names.Next()
+		if err != nil {
+			return created, fmt.Errorf("created %d of %d polecats: %w", len(created), count, err)
+		}
+
+		ui.Printf("\n🐱 Polecat %d of %d: %s\n", i+1, count, name)
+		if err := Add(cfg, name, rigName, AddOptions{Detached: true}); err != nil {
+			return created, fmt.Errorf("created %d of %d polecats, %s failed: %w", len(created), count, name, err)
+		}
+		created = append(created, name)
+	}
+
+	return created, nil
+}
+
+// checkBranchCollision rejects crew names that match the base branch or
+// another existing branch, since their <name>/work branch would clash with it
+func checkBranchCollision(repoPath, name, baseBranch string) error {
+	if name == baseBranch {
+		return fmt.Errorf("crew name %s is the rig's base branch\nPick a different name, e.g. %s-crew", name, name)
+	}
+	if git.BranchExists(repoPath, name) {
+		return fmt.Errorf("crew name %s collides with the existing branch %s\nPick a different name, e.g. %s-crew", name, name, name)
+	}
+	return nil
+}
+
+// branchNotFound reports a missing branch, suggesting a similarly named one
+func branchNotFound(repoPath, branch string) error {
+	branches, _ := git.ListBranches(repoPath)
+	if suggestion := suggest.Closest(branch, branches); suggestion != "" {
+		return fmt.Errorf("branch not found: %s\nDid you mean '%s'?", branch, suggestion)
+	}
+	return fmt.Errorf("branch not found: %s", branch)
+}
+
+// crewBranch returns the branch an existing crew workspace is on
+func crewBranch(cfg *config.Config, rigName, name string) (string, error) {
+	if err := ValidateCrewName(name); err != nil {
+		return "", err
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("crew workspace not found: %s", crewPath)
+	}
+
+	branch, err := git.GetCurrentBranch(crewPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch for crew %s: %w", name, err)
+	}
+	if branch == "" {
+		return "", fmt.Errorf("crew %s is in detached HEAD state", name)
+	}
+	return branch, nil
 }
 
 // Start attaches to an existing crew workspace
@@ -189,12 +852,86 @@ func Start(cfg *config.Config, name, rigName string) error {
 		return err
 	}
 
-	crewPath := cfg.GetCrewPath(rigName, name)
+	sessionName := cfg.GetCrewSessionName(rigName, name)
+	if _, err := ensureSession(cfg, name, rigName); err != nil {
+		return err
+	}
+
+	// Attach to session
+	return tmux.AttachSession(sessionName, crewUseCC(cfg, Locate(cfg, rigName, name)))
+}
+
+// Recover rebuilds a crew member's session from its existing worktree, e.g.
+// after the tmux server died, and attaches to it. A session whose panes have
+// all exited is replaced. The worktree is used as-is, on whatever branch it's
+// on.
+func Recover(cfg *config.Config, name, rigName string) error {
+	if err := ValidateCrewName(name); err != nil {
+		return err
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if !git.IsGitRepo(crewPath) {
+		return fmt.Errorf("no worktree to recover at %s\nUse 'rig crew add %s --rig=%s' to create one", crewPath, name, rigName)
+	}
+
+	sessionName := cfg.GetCrewSessionName(rigName, name)
+	if tmux.SessionExists(sessionName) && !tmux.SessionHealthy(sessionName) {
+		ui.Printf("Session %s has no live panes, replacing it...\n", sessionName)
+		if err := tmux.KillSession(sessionName); err != nil {
+			return fmt.Errorf("failed to kill session %s: %w", sessionName, err)
+		}
+	}
+
+	if _, err := ensureSession(cfg, name, rigName); err != nil {
+		return err
+	}
+	return attachSession(sessionName, crewUseCC(cfg, crewPath))
+}
+
+// StartAll recreates the sessions of every stopped crew member of a rig,
+// without attaching, and returns the names it started
+func StartAll(cfg *config.Config, rigName string) ([]string, error) {
+	if err := config.ValidateRigName(rigName); err != nil {
+		return nil, err
+	}
+
+	var started []string
+	var failures []string
+	for _, info := range discoverRig(cfg, rigName, nil) {
+		name := info.Name
+		if ValidateCrewName(name) != nil {
+			continue
+		}
+		created, err := ensureSession(cfg, name, rigName)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if created {
+			started = append(started, name)
+		}
+	}
+
+	if len(failures) > 0 {
+		return started, fmt.Errorf("started %d, failed %d (%s)", len(started), len(failures), strings.Join(failures, "; "))
+	}
+	return started, nil
+}
+
+// ensureSession recreates a crew member's tmux session if it isn't running,
+// reporting whether it created one
+func ensureSession(cfg *config.Config, name, rigName string) (bool, error) {
+	crewPath := Locate(cfg, rigName, name)
 	sessionName := cfg.GetCrewSessionName(rigName, name)
 
 	// Check if worktree exists
 	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
-		return fmt.Errorf("crew workspace not found: %s\nUse 'rig crew add %s --rig=%s' first", crewPath, name, rigName)
+		return false, fmt.Errorf("crew workspace not found: %s\nUse 'rig crew add %s --rig=%s' first", crewPath, name, rigName)
+	}
+
+	if tmux.SessionExists(sessionName) {
+		return false, nil
 	}
 
 	// Get the actual branch the worktree is on
@@ -203,21 +940,26 @@ func Start(cfg *config.Config, name, rigName string) error {
 		branchName = name + "/work"
 	}
 
-	// Check if session exists
-	if !tmux.SessionExists(sessionName) {
-		fmt.Printf("Session doesn't exist, recreating...\n")
-		if err := tmux.CreateCrewSession(sessionName, crewPath, rigName, name, branchName, cfg.UseCC, cfg.ClaudeInitPrompt); err != nil {
-			return fmt.Errorf("failed to create session: %w", err)
-		}
-		fmt.Printf("✓ Session created: %s\n", sessionName)
+	ui.Printf("Session %s doesn't exist, recreating...\n", sessionName)
+	if err := createCrewSession(sessionName, crewPath, rigName, name, branchName, crewUseCC(cfg, crewPath), SessionOptions(cfg, crewPath)); err != nil {
+		return false, fmt.Errorf("failed to create session: %w", err)
 	}
+	ui.Printf("✓ Session created: %s\n", sessionName)
+	return true, nil
+}
 
-	// Attach to session
-	return tmux.AttachSession(sessionName, cfg.UseCC)
+// RemoveOptions controls how Remove deletes a crew workspace
+type RemoveOptions struct {
+	// Force removes the workspace even if its worktree is locked or has
+	// uncommitted changes, without asking
+	Force bool
+	// Push pushes the crew's branch to origin first, aborting the removal if
+	// the push fails
+	Push bool
 }
 
 // Remove removes a crew workspace
-func Remove(cfg *config.Config, name, rigName string) error {
+func Remove(cfg *config.Config, name, rigName string, opts RemoveOptions) error {
 	if err := ValidateCrewName(name); err != nil {
 		return err
 	}
@@ -227,7 +969,7 @@ func Remove(cfg *config.Config, name, rigName string) error {
 		return fmt.Errorf("repo not found: %s", repoPath)
 	}
 
-	crewPath := cfg.GetCrewPath(rigName, name)
+	crewPath := Locate(cfg, rigName, name)
 	sessionName := cfg.GetCrewSessionName(rigName, name)
 	branchName := cfg.GetCrewBranchName(name)
 
@@ -242,8 +984,8 @@ func Remove(cfg *config.Config, name, rigName string) error {
 
 	// Handle detached state
 	if worktreeInGit && !worktreeDirExists {
-		fmt.Printf("Worktree is in detached state (git knows about it but directory is gone)\n")
-		fmt.Printf("Cleaning up git worktree metadata...\n")
+		ui.Printf("Worktree is in detached state (git knows about it but directory is gone)\n")
+		ui.Printf("Cleaning up git worktree metadata...\n")
 		git.RemoveWorktree(repoPath, crewPath)
 		git.PruneWorktrees(repoPath)
 		worktreeInGit = false
@@ -253,17 +995,54 @@ func Remove(cfg *config.Config, name, rigName string) error {
 	if !worktreeDirExists && !worktreeInGit {
 		// Maybe just the session exists?
 		if tmux.SessionExists(sessionName) {
-			fmt.Printf("Only session exists (no worktree), killing it...\n")
-			tmux.KillSession(sessionName)
-			fmt.Printf("✓ Session killed: %s\n", sessionName)
+			ui.Printf("Only session exists (no worktree), killing it...\n")
+			if err := tmux.KillSession(sessionName); err != nil {
+				return fmt.Errorf("failed to kill session %s: %w", sessionName, err)
+			}
+			ui.Printf("✓ Session killed: %s\n", sessionName)
 			return nil
 		}
 		return fmt.Errorf("crew workspace not found: %s", crewPath)
 	}
 
+	// Respect `git worktree lock` unless forced
+	if git.IsWorktreeLocked(repoPath, crewPath) {
+		if !opts.Force {
+			return fmt.Errorf("crew workspace %s is locked (unlock it with 'git worktree unlock' or use --force)", crewPath)
+		}
+		if err := git.UnlockWorktree(repoPath, crewPath); err != nil {
+			return err
+		}
+	}
+
 	// Warn if user is currently in this session
 	if tmux.SessionExists(sessionName) && tmux.GetCurrentSession() == sessionName {
-		fmt.Printf("You are currently in session '%s' - removing it will disconnect you\n", sessionName)
+		ui.Warnf("You are currently in session '%s' - removing it will disconnect you", sessionName)
+	}
+
+	// Removal discards uncommitted work, so confirm it unless forced
+	if worktreeDirExists && !opts.Force && git.IsDirty(crewPath) {
+		ui.Warnf("%s has uncommitted changes that will be lost", crewPath)
+		fmt.Printf("Remove anyway? [y/N] ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			return fmt.Errorf("cancelled - %s has uncommitted changes (use --force to discard them)", name)
+		}
+	}
+
+	// Push before anything is torn down so a failed push loses nothing
+	if opts.Push {
+		if !git.BranchExists(repoPath, branchName) {
+			return fmt.Errorf("can't push: branch %s not found", branchName)
+		}
+		if !git.HasRemote(repoPath) {
+			return fmt.Errorf("can't push: %s has no remote", rigName)
+		}
+		ui.Printf("Pushing %s to origin...\n", branchName)
+		if err := git.Push(repoPath, branchName); err != nil {
+			return fmt.Errorf("not removing %s: %w", name, err)
+		}
 	}
 
 	// Ask about branch deletion BEFORE killing session
@@ -279,13 +1058,15 @@ func Remove(cfg *config.Config, name, rigName string) error {
 
 	// Kill tmux session if running
 	if tmux.SessionExists(sessionName) {
-		fmt.Printf("Killing session: %s\n", sessionName)
-		tmux.KillSession(sessionName)
+		ui.Printf("Killing session: %s\n", sessionName)
+		if err := tmux.KillSession(sessionName); err != nil {
+			return fmt.Errorf("failed to kill session %s: %w", sessionName, err)
+		}
 	}
 
 	// Remove git worktree
 	if worktreeDirExists {
-		fmt.Printf("Removing worktree: %s\n", crewPath)
+		ui.Printf("Removing worktree: %s\n", crewPath)
 		git.RemoveWorktree(repoPath, crewPath)
 	}
 
@@ -295,20 +1076,342 @@ func Remove(cfg *config.Config, name, rigName string) error {
 	// Delete branch if user confirmed
 	if deleteBranch {
 		git.DeleteBranch(repoPath, branchName)
-		fmt.Printf("✓ Branch deleted: %s\n", branchName)
+		ui.Printf("✓ Branch deleted: %s\n", branchName)
 	}
 
-	// Remove empty repo directory
+	// Remove empty repo directory, but never the parent of a --here worktree
 	repoDir := filepath.Dir(crewPath)
-	if entries, err := os.ReadDir(repoDir); err == nil && len(entries) == 0 {
+	if entries, err := os.ReadDir(repoDir); err == nil && len(entries) == 0 && repoDir == filepath.Join(cfg.CrewBase, rigName) {
 		os.Remove(repoDir)
-		fmt.Printf("Removed empty directory: %s\n", repoDir)
+		ui.Printf("Removed empty directory: %s\n", repoDir)
 	}
 
-	fmt.Printf("✓ Crew workspace removed: %s on %s\n", name, rigName)
+	ui.Printf("✓ Crew workspace removed: %s on %s\n", name, rigName)
 	return nil
 }
 
+// Merge merges a crew member's branch into the base branch of the main repo.
+// If removeAfter is set, the crew workspace is removed after a clean merge.
+func Merge(cfg *config.Config, name, rigName string, removeAfter bool) error {
+	if err := ValidateCrewName(name); err != nil {
+		return err
+	}
+
+	repoPath := cfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		return fmt.Errorf("repo not found: %s", repoPath)
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+		return fmt.Errorf("crew workspace not found: %s", crewPath)
+	}
+
+	// Merge whatever branch the worktree is on (crew or feature branch)
+	branchName, err := git.GetCurrentBranch(crewPath)
+	if err != nil || branchName == "" {
+		branchName = cfg.GetCrewBranchName(name)
+	}
+
+	baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+	if err != nil {
+		return err
+	}
+
+	currentBranch, err := git.GetCurrentBranch(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if currentBranch != baseBranch {
+		ui.Printf("Switching %s to %s...\n", rigName, baseBranch)
+		if err := git.CheckoutBranch(repoPath, baseBranch); err != nil {
+			return err
+		}
+	}
+
+	ui.Printf("Merging %s into %s...\n", branchName, baseBranch)
+	result, err := git.Merge(repoPath, branchName)
+	if err != nil {
+		return err
+	}
+
+	switch result.Status {
+	case git.MergeClean:
+		ui.Printf("✓ Merged %s into %s\n", branchName, baseBranch)
+	case git.MergeAlreadyUpToDate:
+		ui.Printf("✓ %s already contains %s\n", baseBranch, branchName)
+	case git.MergeConflicted:
+		printConflicts(result.Files)
+		ui.Printf("\nResolve with 'rig crew pull %s', then merge again\n", name)
+		return fmt.Errorf("merge aborted: %s conflicts with %s", branchName, baseBranch)
+	}
+
+	if removeAfter {
+		return Remove(cfg, name, rigName, RemoveOptions{})
+	}
+
+	return nil
+}
+
+// Pull updates a crew member's branch with the latest base branch. By default
+// the crew branch is rebased onto base; with useMerge, base is merged in
+// instead. If the repo has a remote it is fetched first and the remote base
+// branch is used.
+func Pull(cfg *config.Config, name, rigName string, useMerge bool) error {
+	if err := ValidateCrewName(name); err != nil {
+		return err
+	}
+
+	repoPath := cfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		return fmt.Errorf("repo not found: %s", repoPath)
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+		return fmt.Errorf("crew workspace not found: %s", crewPath)
+	}
+
+	branchName, err := git.GetCurrentBranch(crewPath)
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+	if err != nil {
+		return err
+	}
+
+	upstream := baseBranch
+	if git.HasRemote(repoPath) {
+		ui.Printf("Fetching...\n")
+		if err := git.Fetch(repoPath); err != nil {
+			return err
+		}
+		if git.RemoteBranchExists(repoPath, "origin/"+baseBranch) {
+			upstream = "origin/" + baseBranch
+		}
+	}
+
+	if useMerge {
+		ui.Printf("Merging %s into %s...\n", upstream, branchName)
+		result, err := git.Merge(crewPath, upstream)
+		if err != nil {
+			return err
+		}
+		switch result.Status {
+		case git.MergeAlreadyUpToDate:
+			ui.Printf("✓ %s is already up to date with %s\n", branchName, upstream)
+		case git.MergeConflicted:
+			printConflicts(result.Files)
+			return fmt.Errorf("merge aborted: %s conflicts with %s", upstream, branchName)
+		default:
+			ui.Printf("✓ Merged %s into %s\n", upstream, branchName)
+		}
+		return nil
+	}
+
+	ui.Printf("Rebasing %s onto %s...\n", branchName, upstream)
+	if err := git.RebaseOnto(crewPath, upstream); err != nil {
+		var conflictErr *git.RebaseConflictError
+		if errors.As(err, &conflictErr) {
+			printConflicts(conflictErr.Files)
+			return fmt.Errorf("rebase aborted: %s conflicts with %s (try --merge to resolve in a merge commit)", branchName, upstream)
+		}
+		return err
+	}
+
+	ui.Printf("✓ Rebased %s onto %s\n", branchName, upstream)
+	return nil
+}
+
+// SetUpstream makes a crew's branch track the branch of the same name on
+// origin, pushing it first if origin doesn't have it yet. It returns the
+// upstream ref.
+func SetUpstream(cfg *config.Config, name, rigName string) (string, error) {
+	if err := ValidateCrewName(name); err != nil {
+		return "", err
+	}
+
+	repoPath := cfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		return "", fmt.Errorf("repo not found: %s", repoPath)
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("crew workspace not found: %s", crewPath)
+	}
+
+	branchName, err := git.GetCurrentBranch(crewPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branchName == "" {
+		return "", fmt.Errorf("crew workspace %s is not on a branch (detached HEAD)", name)
+	}
+
+	if !git.HasRemote(repoPath) {
+		return "", fmt.Errorf("%s has no remote to track\nAdd one with 'git remote add origin <url>' first", rigName)
+	}
+
+	ui.Printf("Fetching...\n")
+	if err := git.Fetch(repoPath); err != nil {
+		return "", err
+	}
+
+	upstream := "origin/" + branchName
+	if git.RemoteBranchExists(repoPath, "origin/"+branchName) {
+		if err := git.SetUpstream(crewPath, branchName, upstream); err != nil {
+			return "", err
+		}
+		return upstream, nil
+	}
+
+	ui.Printf("Pushing %s to origin...\n", branchName)
+	if err := git.PushUpstream(crewPath, branchName); err != nil {
+		return "", err
+	}
+	return upstream, nil
+}
+
+// Export writes a crew's commits since the base branch as patches. An
+// output ending in ".patch" gets a single mbox file; anything else is a
+// directory of one patch per commit. It returns the files written.
+func Export(cfg *config.Config, name, rigName, output string) ([]string, error) {
+	if err := ValidateCrewName(name); err != nil {
+		return nil, err
+	}
+
+	repoPath := cfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		return nil, fmt.Errorf("repo not found: %s", repoPath)
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if _, err := os.Stat(crewPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("crew workspace not found: %s", crewPath)
+	}
+
+	branchName, err := git.GetCurrentBranch(crewPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branchName == "" {
+		return nil, fmt.Errorf("crew workspace %s is not on a branch (detached HEAD)", name)
+	}
+
+	baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	if output == "" {
+		output = name + "-patches"
+	}
+	if !strings.HasSuffix(output, ".patch") {
+		patches, err := git.FormatPatch(repoPath, baseBranch, branchName, output)
+		if err != nil {
+			return nil, err
+		}
+		if len(patches) == 0 {
+			return nil, fmt.Errorf("no commits on %s relative to %s", branchName, baseBranch)
+		}
+		return patches, nil
+	}
+
+	// Format into a scratch directory, then concatenate into one mbox
+	tmpDir, err := os.MkdirTemp("", "rig-export-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	patches, err := git.FormatPatch(repoPath, baseBranch, branchName, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no commits on %s relative to %s", branchName, baseBranch)
+	}
+
+	var mbox []byte
+	for _, patch := range patches {
+		content, err := os.ReadFile(patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch: %w", err)
+		}
+		mbox = append(mbox, content...)
+	}
+	if err := os.WriteFile(output, mbox, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return []string{output}, nil
+}
+
+// Import creates a new crew workspace and applies a patch file (as written
+// by Export) to it. With no name a polecat name is generated. If the patch
+// doesn't apply, the new workspace is removed again. It returns the crew
+// name.
+func Import(cfg *config.Config, name, rigName, patchFile string) (string, error) {
+	if _, err := os.Stat(patchFile); err != nil {
+		return "", fmt.Errorf("patch file not found: %s", patchFile)
+	}
+
+	repoPath := cfg.GetRepoPath(rigName)
+	if !git.IsGitRepo(repoPath) {
+		return "", fmt.Errorf("repo not found: %s", repoPath)
+	}
+
+	if name == "" {
+		used := []string{}
+		for _, info := range discoverRig(cfg, rigName, nil) {
+			used = append(used, info.Name)
+		}
+		var err error
+		if name, err = polecat.NewGenerator(used).Next(); err != nil {
+			return "", err
+		}
+	}
+
+	crewPath := Locate(cfg, rigName, name)
+	if pathExists(crewPath) {
+		return "", fmt.Errorf("crew workspace already exists: %s", crewPath)
+	}
+
+	// A failed patch deletes the branch again, so it must be a new one
+	branchName := cfg.GetCrewBranchName(name)
+	if git.BranchExists(repoPath, branchName) {
+		return "", fmt.Errorf("branch %s already exists\nImport under a different name, or remove the branch first", branchName)
+	}
+
+	if err := Add(cfg, name, rigName, AddOptions{NoSession: true}); err != nil {
+		return "", err
+	}
+
+	ui.Printf("Applying %s...\n", patchFile)
+	if err := git.ApplyPatch(crewPath, patchFile); err != nil {
+		ui.Printf("Patch failed, cleaning up worktree...\n")
+		cleanupWorktree(repoPath, crewPath, branchName)
+
+		var conflictErr *git.PatchConflictError
+		if errors.As(err, &conflictErr) && len(conflictErr.Files) > 0 {
+			printConflicts(conflictErr.Files)
+		}
+		return "", err
+	}
+
+	ui.Printf("✓ Applied %s to %s\n", patchFile, name)
+	return name, nil
+}
+
+func printConflicts(files []string) {
+	ui.Warnf("Conflicts found, no changes were made. Conflicting files:")
+	for _, file := range files {
+		fmt.Fprintf(os.Stderr, "  - %s\n", file)
+	}
+}
+
 func cleanupWorktree(repoPath, crewPath, branchName string) {
 	git.RemoveWorktree(repoPath, crewPath)
 	git.PruneWorktrees(repoPath)
@@ -1,13 +1,20 @@
 package crew
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mstrand/rig/internal/testutil"
 	"github.com/mstrand/rig/pkg/config"
 	"github.com/mstrand/rig/pkg/git"
+	"github.com/mstrand/rig/pkg/polecat"
+	"github.com/mstrand/rig/pkg/tmux"
 )
 
 func TestValidateCrewName(t *testing.T) {
@@ -90,6 +97,20 @@ func createTestGitRepo(t *testing.T, basePath, name string) string {
 	return repoPath
 }
 
+// runGit runs git in dir, failing the test on error, and returns its
+// trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
 func setupTestConfig(t *testing.T) *config.Config {
 	t.Helper()
 
@@ -122,6 +143,12 @@ func TestInferRig(t *testing.T) {
 		}
 	})
 
+	t.Run("explicit rig with @", func(t *testing.T) {
+		if _, err := InferRig(cfg, "foo@bar"); err == nil {
+			t.Error("Expected error for rig name containing @")
+		}
+	})
+
 	t.Run("from rigs directory", func(t *testing.T) {
 		// Create a test repo
 		repoPath := createTestGitRepo(t, cfg.RigsBase, "testrepo")
@@ -185,6 +212,27 @@ func TestInferRig(t *testing.T) {
 		}
 	})
 
+	t.Run("from worktree outside the bases", func(t *testing.T) {
+		repoPath := createTestGitRepo(t, cfg.RigsBase, "testrepo3")
+
+		worktreePath := filepath.Join(t.TempDir(), "elsewhere", "review")
+		if err := git.CreateWorktree(repoPath, worktreePath, "review/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(worktreePath)
+
+		rig, err := InferRig(cfg, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rig != "testrepo3" {
+			t.Errorf("Expected testrepo3, got %s", rig)
+		}
+	})
+
 	t.Run("no inference possible", func(t *testing.T) {
 		// Change to temp directory outside rigs/crew
 		tmpDir := t.TempDir()
@@ -200,43 +248,34 @@ func TestInferRig(t *testing.T) {
 }
 
 func TestCrewWorkflow(t *testing.T) {
-	// Skip if tmux is not available
-	if _, err := exec.LookPath("tmux"); err != nil {
-		t.Skip("tmux not available, skipping integration test")
-	}
+	// Keep any real tmux server out of the session checks
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
 
 	cfg := setupTestConfig(t)
 
 	// Create a test repo
 	repoName := "testrig"
-	createTestGitRepo(t, cfg.RigsBase, repoName)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, repoName)
 
 	crewName := "testcrew"
 	crewPath := cfg.GetCrewPath(repoName, crewName)
 	sessionName := cfg.GetCrewSessionName(repoName, crewName)
 	branchName := cfg.GetCrewBranchName(crewName)
 
-	t.Run("add crew workspace", func(t *testing.T) {
-		// Note: We can't fully test Add() because it tries to attach to tmux
-		// Instead, we'll test the individual components
-
-		// Validate name
-		if err := ValidateCrewName(crewName); err != nil {
-			t.Fatalf("Failed to validate crew name: %v", err)
-		}
-
-		// Create worktree
-		repoPath := cfg.GetRepoPath(repoName)
-		baseBranch, err := git.GetBaseBranch(repoPath, cfg.DefaultBranch)
-		if err != nil {
-			t.Fatalf("Failed to get base branch: %v", err)
-		}
-
-		os.MkdirAll(filepath.Dir(crewPath), 0755)
+	// Record the sessions Add creates instead of starting tmux
+	var sessions [][]string
+	origCreate := createCrewSession
+	createCrewSession = func(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, opts tmux.SessionOptions) error {
+		sessions = append(sessions, []string{sessionName, crewPath, rigName, memberName, branchName})
+		return nil
+	}
+	t.Cleanup(func() { createCrewSession = origCreate })
+	attaches := stubAttach(t)
 
-		err = git.CreateWorktree(repoPath, crewPath, branchName, baseBranch)
-		if err != nil {
-			t.Fatalf("Failed to create worktree: %v", err)
+	t.Run("add crew workspace", func(t *testing.T) {
+		if err := Add(cfg, crewName, repoName, AddOptions{}); err != nil {
+			t.Fatalf("Add() error = %v", err)
 		}
 
 		// Verify worktree exists
@@ -251,6 +290,14 @@ func TestCrewWorkflow(t *testing.T) {
 		if !git.BranchExists(repoPath, branchName) {
 			t.Error("Expected branch to exist")
 		}
+
+		expected := [][]string{{sessionName, crewPath, repoName, crewName, branchName}}
+		if !reflect.DeepEqual(sessions, expected) {
+			t.Errorf("Created sessions %v, want %v", sessions, expected)
+		}
+		if !reflect.DeepEqual(*attaches, []string{sessionName}) {
+			t.Errorf("Attached to %v, want [%s]", *attaches, sessionName)
+		}
 	})
 
 	t.Run("verify branch is correct", func(t *testing.T) {
@@ -265,22 +312,10 @@ func TestCrewWorkflow(t *testing.T) {
 	})
 
 	t.Run("remove crew workspace", func(t *testing.T) {
-		// We'll test the removal logic directly
-		repoPath := cfg.GetRepoPath(repoName)
-
-		// Remove worktree
-		err := git.RemoveWorktree(repoPath, crewPath)
-		if err != nil {
-			t.Fatalf("Failed to remove worktree: %v", err)
-		}
-
-		// Prune
-		git.PruneWorktrees(repoPath)
-
-		// Delete branch
-		err = git.DeleteBranch(repoPath, branchName)
-		if err != nil {
-			t.Fatalf("Failed to delete branch: %v", err)
+		// Accept deleting the branch
+		testutil.StubStdin(t, "\n")
+		if err := Remove(cfg, crewName, repoName, RemoveOptions{}); err != nil {
+			t.Fatalf("Remove() error = %v", err)
 		}
 
 		// Verify cleanup
@@ -288,15 +323,14 @@ func TestCrewWorkflow(t *testing.T) {
 			t.Error("Expected crew path to not exist")
 		}
 
+		if git.WorktreeExists(repoPath, crewPath) {
+			t.Error("Expected worktree to be gone from git")
+		}
+
 		if git.BranchExists(repoPath, branchName) {
 			t.Error("Expected branch to be deleted")
 		}
 	})
-
-	// Cleanup session if it exists
-	defer func() {
-		exec.Command("tmux", "kill-session", "-t", sessionName).Run()
-	}()
 }
 
 func TestCrewPathStructure(t *testing.T) {
@@ -323,3 +357,1427 @@ func TestCrewPathStructure(t *testing.T) {
 		t.Errorf("Expected base name tracy, got %s", base)
 	}
 }
+
+func TestMerge(t *testing.T) {
+	cfg := setupTestConfig(t)
+
+	repoName := "mergerig"
+	repoPath := createTestGitRepo(t, cfg.RigsBase, repoName)
+
+	crewPath := cfg.GetCrewPath(repoName, "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, "tracy/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(crewPath, "feature.txt"), []byte("feature"), 0644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Add feature"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = crewPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	t.Run("clean merge lands on base", func(t *testing.T) {
+		if err := Merge(cfg, "tracy", repoName, false); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(repoPath, "feature.txt")); err != nil {
+			t.Error("Expected merged file in main repo")
+		}
+		if _, err := os.Stat(crewPath); err != nil {
+			t.Error("Expected crew workspace to be kept")
+		}
+	})
+
+	t.Run("conflicting merge is refused", func(t *testing.T) {
+		for _, dir := range []string{crewPath, repoPath} {
+			os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("changed in "+filepath.Base(dir)), 0644)
+			cmd := exec.Command("git", "commit", "-am", "Change feature")
+			cmd.Dir = dir
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("Failed to commit in %s: %v", dir, err)
+			}
+		}
+
+		if err := Merge(cfg, "tracy", repoName, false); err == nil {
+			t.Fatal("Expected merge to be refused on conflict")
+		}
+
+		content, _ := os.ReadFile(filepath.Join(repoPath, "feature.txt"))
+		if string(content) != "changed in "+repoName {
+			t.Errorf("Expected main repo to be unchanged, got %q", content)
+		}
+	})
+
+	t.Run("missing crew", func(t *testing.T) {
+		if err := Merge(cfg, "nobody", repoName, false); err == nil {
+			t.Error("Expected error for missing crew workspace")
+		}
+	})
+}
+
+func TestRemoveLockedWorktree(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, cfg.GetCrewBranchName("alex"), "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	cmd := exec.Command("git", "worktree", "lock", crewPath)
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to lock worktree: %v", err)
+	}
+
+	if err := Remove(cfg, "alex", "testrig", RemoveOptions{}); err == nil {
+		t.Fatal("Expected removal of a locked worktree to be refused")
+	}
+	if !git.WorktreeExists(repoPath, crewPath) {
+		t.Fatal("Expected locked worktree to be kept")
+	}
+
+	if err := Remove(cfg, "alex", "testrig", RemoveOptions{Force: true}); err != nil {
+		t.Fatalf("Remove() with Force error = %v", err)
+	}
+	if _, err := os.Stat(crewPath); !os.IsNotExist(err) {
+		t.Error("Expected crew workspace to be removed with Force")
+	}
+}
+
+func TestRemoveDirtyWorktree(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, cfg.GetCrewBranchName("alex"), "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	os.WriteFile(filepath.Join(crewPath, "wip.txt"), []byte("half done"), 0644)
+
+	t.Run("declined", func(t *testing.T) {
+		testutil.StubStdin(t, "\n")
+		if err := Remove(cfg, "alex", "testrig", RemoveOptions{}); err == nil {
+			t.Fatal("Expected removal of a dirty worktree to be cancelled")
+		}
+		if _, err := os.Stat(filepath.Join(crewPath, "wip.txt")); err != nil {
+			t.Error("Expected uncommitted changes to be kept")
+		}
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		// Confirm the discard, then keep the branch
+		testutil.StubStdin(t, "y\nn\n")
+		if err := Remove(cfg, "alex", "testrig", RemoveOptions{}); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(crewPath); !os.IsNotExist(err) {
+			t.Error("Expected crew workspace to be removed")
+		}
+		if !git.BranchExists(repoPath, cfg.GetCrewBranchName("alex")) {
+			t.Error("Expected branch to be kept")
+		}
+	})
+}
+
+func TestRemovePush(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	branchName := cfg.GetCrewBranchName("alex")
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, branchName, "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	runGit(t, crewPath, "commit", "--allow-empty", "-m", "unpushed work")
+	head := runGit(t, crewPath, "rev-parse", "HEAD")
+
+	// An unreachable origin aborts the removal
+	runGit(t, repoPath, "remote", "add", "origin", filepath.Join(t.TempDir(), "missing.git"))
+	if err := Remove(cfg, "alex", "testrig", RemoveOptions{Push: true}); err == nil {
+		t.Fatal("Expected removal to abort when the push fails")
+	}
+	if _, err := os.Stat(crewPath); err != nil {
+		t.Fatal("Expected crew workspace to be kept after a failed push")
+	}
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, repoPath, "init", "--bare", remotePath)
+	runGit(t, repoPath, "remote", "set-url", "origin", remotePath)
+
+	testutil.StubStdin(t, "\n")
+	if err := Remove(cfg, "alex", "testrig", RemoveOptions{Push: true}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(crewPath); !os.IsNotExist(err) {
+		t.Error("Expected crew workspace to be removed")
+	}
+	if git.BranchExists(repoPath, branchName) {
+		t.Error("Expected local branch to be deleted")
+	}
+	if pushed := runGit(t, remotePath, "rev-parse", branchName); pushed != head {
+		t.Errorf("Expected origin's %s at %s, got %s", branchName, head, pushed)
+	}
+}
+
+func TestExport(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	crewPath := cfg.GetCrewPath("testrig", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, "tracy/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	if _, err := Export(cfg, "tracy", "testrig", filepath.Join(t.TempDir(), "none")); err == nil {
+		t.Error("Expected error when there are no commits to export")
+	}
+
+	for _, name := range []string{"one.txt", "two.txt"} {
+		os.WriteFile(filepath.Join(crewPath, name), []byte(name), 0644)
+		for _, args := range [][]string{{"add", name}, {"commit", "-m", "Add " + name}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = crewPath
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("git %v failed: %v", args, err)
+			}
+		}
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		outDir := filepath.Join(t.TempDir(), "patches")
+		files, err := Export(cfg, "tracy", "testrig", outDir)
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if len(files) != 2 {
+			t.Errorf("Expected 2 patch files, got %v", files)
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		outFile := filepath.Join(t.TempDir(), "tracy.patch")
+		files, err := Export(cfg, "tracy", "testrig", outFile)
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if len(files) != 1 || files[0] != outFile {
+			t.Errorf("Expected [%s], got %v", outFile, files)
+		}
+		content, _ := os.ReadFile(outFile)
+		if strings.Count(string(content), "Subject: [PATCH") != 2 {
+			t.Errorf("Expected both commits in %s, got:\n%s", outFile, content)
+		}
+	})
+}
+
+func TestImport(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	crewPath := cfg.GetCrewPath("testrig", "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, "tracy/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	os.WriteFile(filepath.Join(crewPath, "feature.txt"), []byte("feature"), 0644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Add feature"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = crewPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	patchFile := filepath.Join(t.TempDir(), "tracy.patch")
+	if _, err := Export(cfg, "tracy", "testrig", patchFile); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	t.Run("named", func(t *testing.T) {
+		name, err := Import(cfg, "alex", "testrig", patchFile)
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if name != "alex" {
+			t.Errorf("Expected crew alex, got %s", name)
+		}
+		content, err := os.ReadFile(filepath.Join(cfg.GetCrewPath("testrig", "alex"), "feature.txt"))
+		if err != nil || string(content) != "feature" {
+			t.Errorf("Expected imported feature.txt, got %q (%v)", content, err)
+		}
+		branch, _ := git.GetCurrentBranch(cfg.GetCrewPath("testrig", "alex"))
+		if branch != "alex/work" {
+			t.Errorf("Expected branch alex/work, got %s", branch)
+		}
+	})
+
+	t.Run("generated polecat", func(t *testing.T) {
+		name, err := Import(cfg, "", "testrig", patchFile)
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if !polecat.IsPolecat(name) {
+			t.Errorf("Expected a polecat name, got %s", name)
+		}
+	})
+
+	t.Run("existing crew", func(t *testing.T) {
+		if _, err := Import(cfg, "tracy", "testrig", patchFile); err == nil {
+			t.Error("Expected error importing into an existing crew")
+		}
+
+		// A workspace made with --here isn't under CrewBase
+		herePath := filepath.Join(t.TempDir(), "casey")
+		if err := Add(cfg, "casey", "testrig", AddOptions{NoSession: true, Here: herePath}); err != nil {
+			t.Fatalf("Add(casey) error = %v", err)
+		}
+		if _, err := Import(cfg, "casey", "testrig", patchFile); err == nil || !strings.Contains(err.Error(), "already exists: "+herePath) {
+			t.Errorf("Expected error importing into casey at %s, got %v", herePath, err)
+		}
+	})
+
+	t.Run("existing branch is kept", func(t *testing.T) {
+		runGit(t, repoPath, "branch", "drew/work", "main")
+		head := runGit(t, repoPath, "rev-parse", "drew/work")
+
+		_, err := Import(cfg, "drew", "testrig", patchFile)
+		if err == nil || !strings.Contains(err.Error(), "branch drew/work already exists") {
+			t.Errorf("Expected existing branch error, got %v", err)
+		}
+		if got := runGit(t, repoPath, "rev-parse", "drew/work"); got != head {
+			t.Errorf("Expected drew/work to stay at %s, got %s", head, got)
+		}
+		if _, err := os.Stat(cfg.GetCrewPath("testrig", "drew")); !os.IsNotExist(err) {
+			t.Error("Expected no workspace for drew")
+		}
+	})
+
+	t.Run("conflict cleans up", func(t *testing.T) {
+		// Land a conflicting feature.txt on main so the patch no longer applies
+		os.WriteFile(filepath.Join(repoPath, "feature.txt"), []byte("main"), 0644)
+		for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Add feature on main"}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repoPath
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("git %v failed: %v", args, err)
+			}
+		}
+
+		if _, err := Import(cfg, "blake", "testrig", patchFile); err == nil {
+			t.Fatal("Expected error for a conflicting patch")
+		}
+		if _, err := os.Stat(cfg.GetCrewPath("testrig", "blake")); !os.IsNotExist(err) {
+			t.Error("Expected failed import to remove the workspace")
+		}
+		if git.BranchExists(repoPath, "blake/work") {
+			t.Error("Expected failed import to delete the branch")
+		}
+	})
+}
+
+func TestPull(t *testing.T) {
+	cfg := setupTestConfig(t)
+
+	repoName := "pullrig"
+	repoPath := createTestGitRepo(t, cfg.RigsBase, repoName)
+
+	crewPath := cfg.GetCrewPath(repoName, "tracy")
+	os.MkdirAll(filepath.Dir(crewPath), 0755)
+	if err := git.CreateWorktree(repoPath, crewPath, "tracy/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	// Advance main after the crew branched off
+	os.WriteFile(filepath.Join(repoPath, "base.txt"), []byte("new"), 0644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Advance main"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	if err := Pull(cfg, "tracy", repoName, false); err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(crewPath, "base.txt")); err != nil {
+		t.Error("Expected base change in crew worktree after pull")
+	}
+}
+
+func TestSetUpstream(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	for _, name := range []string{"tracy", "alex"} {
+		crewPath := cfg.GetCrewPath("testrig", name)
+		os.MkdirAll(filepath.Dir(crewPath), 0755)
+		if err := git.CreateWorktree(repoPath, crewPath, name+"/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+	}
+
+	if _, err := SetUpstream(cfg, "tracy", "testrig"); err == nil || !strings.Contains(err.Error(), "no remote") {
+		t.Errorf("Expected a no-remote error, got %v", err)
+	}
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, repoPath, "init", "--bare", remotePath)
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+
+	// tracy's branch isn't on origin yet, so it gets pushed
+	upstream, err := SetUpstream(cfg, "tracy", "testrig")
+	if err != nil {
+		t.Fatalf("SetUpstream() error = %v", err)
+	}
+	if upstream != "origin/tracy/work" {
+		t.Errorf("SetUpstream() = %q, want origin/tracy/work", upstream)
+	}
+	tracyPath := cfg.GetCrewPath("testrig", "tracy")
+	if got := runGit(t, tracyPath, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/tracy/work" {
+		t.Errorf("Expected tracy to track origin/tracy/work, got %s", got)
+	}
+	runGit(t, remotePath, "rev-parse", "--verify", "tracy/work")
+
+	// alex's branch was pushed without tracking, so only the upstream is set
+	runGit(t, repoPath, "push", "origin", "alex/work")
+	if _, err := SetUpstream(cfg, "alex", "testrig"); err != nil {
+		t.Fatalf("SetUpstream() error = %v", err)
+	}
+	alexPath := cfg.GetCrewPath("testrig", "alex")
+	if got := runGit(t, alexPath, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/alex/work" {
+		t.Errorf("Expected alex to track origin/alex/work, got %s", got)
+	}
+}
+
+// useTestTmux points tmux at a private socket directory so tests never
+// touch the user's tmux server
+func useTestTmux(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available, skipping")
+	}
+
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() {
+		exec.Command("tmux", "kill-server").Run()
+	})
+}
+
+// stubAttach replaces attachSession and returns a pointer to the recorded calls
+func stubAttach(t *testing.T) *[]string {
+	t.Helper()
+
+	var calls []string
+	orig := attachSession
+	attachSession = func(name string, useCC bool) error {
+		calls = append(calls, name)
+		return nil
+	}
+	t.Cleanup(func() { attachSession = orig })
+	return &calls
+}
+
+func TestAddDetached(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	calls := stubAttach(t)
+
+	sessionName := cfg.GetCrewSessionName("testrig", "alex")
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{Detached: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !tmux.SessionExists(sessionName) {
+		t.Errorf("Expected session %s to exist", sessionName)
+	}
+	if _, err := os.Stat(cfg.GetCrewPath("testrig", "alex")); err != nil {
+		t.Errorf("Expected crew workspace to exist: %v", err)
+	}
+
+	// Existing workspace with a running session
+	if err := Add(cfg, "alex", "testrig", AddOptions{Detached: true}); err != nil {
+		t.Fatalf("Add() on existing workspace error = %v", err)
+	}
+
+	// Existing workspace whose session was killed
+	tmux.KillSession(sessionName)
+	if err := Add(cfg, "alex", "testrig", AddOptions{Detached: true}); err != nil {
+		t.Fatalf("Add() recreating session error = %v", err)
+	}
+	if !tmux.SessionExists(sessionName) {
+		t.Errorf("Expected session %s to be recreated", sessionName)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("Expected no attach calls in detached mode, got %v", *calls)
+	}
+
+	// Without detached the existing session is attached
+	if err := Add(cfg, "alex", "testrig", AddOptions{}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if len(*calls) != 1 || (*calls)[0] != sessionName {
+		t.Errorf("Expected one attach to %s, got %v", sessionName, *calls)
+	}
+}
+
+func TestAddNoSession(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	calls := stubAttach(t)
+
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+	sessionName := cfg.GetCrewSessionName("testrig", "alex")
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !git.WorktreeExists(cfg.GetRepoPath("testrig"), crewPath) {
+		t.Errorf("Expected worktree at %s", crewPath)
+	}
+	if tmux.SessionExists(sessionName) {
+		t.Errorf("Expected no session for %s", sessionName)
+	}
+
+	// Re-running on the existing workspace still leaves it stopped
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() on existing workspace error = %v", err)
+	}
+	if tmux.SessionExists(sessionName) {
+		t.Errorf("Expected no session for %s", sessionName)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("Expected no attach calls, got %v", *calls)
+	}
+}
+
+func TestAddCreatesCrewBase(t *testing.T) {
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	// First run on a fresh machine: no crew directory yet
+	cfg.CrewBase = filepath.Join(cfg.CrewBase, "missing")
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !git.WorktreeExists(cfg.GetRepoPath("testrig"), cfg.GetCrewPath("testrig", "alex")) {
+		t.Errorf("Expected worktree under the new crew base %s", cfg.CrewBase)
+	}
+}
+
+func TestAddBase(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	cmd := exec.Command("git", "branch", "feat/login")
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, Base: "feat/login"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !git.WorktreeExists(repoPath, cfg.GetCrewPath("testrig", "alex")) {
+		t.Error("Expected worktree for alex")
+	}
+
+	err := Add(cfg, "blake", "testrig", AddOptions{NoSession: true, Base: "feat/logn"})
+	if err == nil || !strings.Contains(err.Error(), "branch not found: feat/logn\nDid you mean 'feat/login'?") {
+		t.Errorf("Expected a suggestion for a missing base branch, got %v", err)
+	}
+	if _, err := os.Stat(cfg.GetCrewPath("testrig", "blake")); !os.IsNotExist(err) {
+		t.Error("Expected no workspace for a missing base branch")
+	}
+}
+
+func TestAddForceNewBranch(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	// A leftover alex/work with a commit main doesn't have
+	runGit(t, repoPath, "checkout", "-b", "alex/work")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "stale work")
+	runGit(t, repoPath, "checkout", "main")
+	mainHead := runGit(t, repoPath, "rev-parse", "main")
+
+	// Refused while the branch is checked out elsewhere
+	otherPath := filepath.Join(t.TempDir(), "other")
+	runGit(t, repoPath, "worktree", "add", otherPath, "alex/work")
+	err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, ForceNewBranch: true})
+	if err == nil || !strings.Contains(err.Error(), "checked out at") {
+		t.Errorf("Expected checked out error, got %v", err)
+	}
+	runGit(t, repoPath, "worktree", "remove", otherPath)
+
+	// Declining keeps the branch
+	testutil.StubStdin(t, "n\n")
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, ForceNewBranch: true}); err == nil {
+		t.Error("Expected declining to cancel")
+	}
+	if runGit(t, repoPath, "log", "-1", "--format=%s", "alex/work") != "stale work" {
+		t.Error("Expected alex/work to be kept after declining")
+	}
+
+	testutil.StubStdin(t, "y\n")
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, ForceNewBranch: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+	if head := runGit(t, crewPath, "rev-parse", "HEAD"); head != mainHead {
+		t.Errorf("Expected alex/work reset to main (%s), got %s", mainHead, head)
+	}
+	if branch, _ := git.GetCurrentBranch(crewPath); branch != "alex/work" {
+		t.Errorf("Expected alex on alex/work, got %s", branch)
+	}
+}
+
+func TestAddSessionFailureKeepsWorktree(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	origCreate := createCrewSession
+	createCrewSession = func(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, opts tmux.SessionOptions) error {
+		return errors.New("tmux hiccup")
+	}
+	t.Cleanup(func() { createCrewSession = origCreate })
+
+	err := Add(cfg, "alex", "testrig", AddOptions{Detached: true})
+	if err == nil || !strings.Contains(err.Error(), "worktree created but session failed: tmux hiccup") || !strings.Contains(err.Error(), "rig crew start alex --rig=testrig") {
+		t.Errorf("Expected session failure with a retry hint, got %v", err)
+	}
+	if !git.WorktreeExists(repoPath, cfg.GetCrewPath("testrig", "alex")) {
+		t.Error("Expected the worktree to survive the session failure")
+	}
+	if !git.BranchExists(repoPath, "alex/work") {
+		t.Error("Expected alex/work to survive the session failure")
+	}
+}
+
+func TestAddLayout(t *testing.T) {
+	var gotCC []bool
+	origCreate := createCrewSession
+	createCrewSession = func(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, opts tmux.SessionOptions) error {
+		gotCC = append(gotCC, useCC)
+		return nil
+	}
+	t.Cleanup(func() { createCrewSession = origCreate })
+
+	tests := []struct {
+		name     string
+		useCC    bool
+		layout   string
+		expected bool
+	}{
+		{"default native", false, "", false},
+		{"default cc", true, "", true},
+		{"cc over native default", false, "cc", true},
+		{"native over cc default", true, "native", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupTestConfig(t)
+			cfg.UseCC = tt.useCC
+			createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+			gotCC = nil
+			if err := Add(cfg, "alex", "testrig", AddOptions{Detached: true, Layout: tt.layout}); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotCC, []bool{tt.expected}) {
+				t.Errorf("Session created with useCC %v, want %v", gotCC, tt.expected)
+			}
+		})
+	}
+
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	if err := Add(cfg, "alex", "testrig", AddOptions{Detached: true, Layout: "tiled"}); err == nil || !strings.Contains(err.Error(), "unknown layout") {
+		t.Errorf("Expected unknown layout error, got %v", err)
+	}
+}
+
+func TestLayoutKept(t *testing.T) {
+	// Keep any real tmux server out of the session checks
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	cfg := setupTestConfig(t)
+	cfg.UseCC = false
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	gotCC := make(map[string]bool)
+	origCreate := createCrewSession
+	createCrewSession = func(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, opts tmux.SessionOptions) error {
+		gotCC[memberName] = useCC
+		return nil
+	}
+	t.Cleanup(func() { createCrewSession = origCreate })
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, Layout: config.LayoutCC}); err != nil {
+		t.Fatalf("Add(alex) error = %v", err)
+	}
+	if err := Add(cfg, "sam", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add(sam) error = %v", err)
+	}
+
+	meta, err := ReadMeta(cfg.GetCrewPath("testrig", "alex"))
+	if err != nil || meta == nil || meta.Layout != config.LayoutCC {
+		t.Fatalf("Expected alex's metadata to record the cc layout, got %+v (%v)", meta, err)
+	}
+
+	// Recreated sessions use the recorded layout, not the configured one
+	if _, err := StartAll(cfg, "testrig"); err != nil {
+		t.Fatalf("StartAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotCC, map[string]bool{"alex": true, "sam": false}) {
+		t.Errorf("Sessions created with useCC %v, want alex cc and sam native", gotCC)
+	}
+
+	tests := []struct {
+		session  string
+		useCC    bool
+		expected bool
+	}{
+		{"testrig@alex", false, true},
+		{"testrig@sam", false, false},
+		{"testrig@sam", true, true},
+		{"testrig", true, true},
+		{"testrig@nobody", true, true},
+	}
+	for _, tt := range tests {
+		cfg.UseCC = tt.useCC
+		if got := SessionUseCC(cfg, tt.session); got != tt.expected {
+			t.Errorf("SessionUseCC(%s) with UseCC %v = %v, want %v", tt.session, tt.useCC, got, tt.expected)
+		}
+	}
+
+	// A new --layout on an existing workspace replaces the recorded one
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, Layout: config.LayoutNative}); err != nil {
+		t.Fatalf("Add(alex) again error = %v", err)
+	}
+	cfg.UseCC = true
+	if SessionUseCC(cfg, "testrig@alex") {
+		t.Error("Expected alex to be native after re-adding with --layout native")
+	}
+}
+
+func TestAddFromRef(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	for _, args := range [][]string{{"tag", "v1.0"}, {"commit", "--allow-empty", "-m", "after tag"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true, FromRef: "v1.0"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = cfg.GetCrewPath("testrig", "alex")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if strings.TrimSpace(string(output)) == "after tag" {
+		t.Error("Expected alex to start at the tag, not the tip of main")
+	}
+
+	// An existing branch isn't moved to the ref
+	cmd = exec.Command("git", "branch", "casey/work")
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+	err = Add(cfg, "casey", "testrig", AddOptions{NoSession: true, FromRef: "v1.0"})
+	if err == nil || !strings.Contains(err.Error(), "already exists, so it can't start from v1.0") {
+		t.Errorf("Expected existing branch error, got %v", err)
+	}
+
+	err = Add(cfg, "blake", "testrig", AddOptions{NoSession: true, FromRef: "v9.9"})
+	if err == nil || !strings.Contains(err.Error(), "ref not found: v9.9") {
+		t.Errorf("Expected ref not found error, got %v", err)
+	}
+	if _, err := os.Stat(cfg.GetCrewPath("testrig", "blake")); !os.IsNotExist(err) {
+		t.Error("Expected no workspace for a missing ref")
+	}
+}
+
+func TestAddHere(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	parent := t.TempDir()
+	herePath := filepath.Join(parent, "testrig-tracy")
+	if err := Add(cfg, "tracy", "testrig", AddOptions{NoSession: true, Here: herePath}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if !git.WorktreeExists(repoPath, herePath) {
+		t.Fatalf("Expected a worktree at %s", herePath)
+	}
+	if _, err := os.Stat(cfg.GetCrewPath("testrig", "tracy")); !os.IsNotExist(err) {
+		t.Error("Expected nothing under CrewBase")
+	}
+	if got := Locate(cfg, "testrig", "tracy"); got != herePath {
+		t.Errorf("Locate() = %s, want %s", got, herePath)
+	}
+	want := []CrewInfo{NewCrewInfo(cfg, "testrig", "tracy", herePath, "tracy/work", nil)}
+	if got, err := Discover(cfg); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// Re-adding finds the existing workspace; a second path is refused
+	if err := Add(cfg, "tracy", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Errorf("Expected re-adding tracy to find the existing workspace, got %v", err)
+	}
+	if err := Add(cfg, "tracy", "testrig", AddOptions{NoSession: true, Here: filepath.Join(parent, "other")}); err == nil {
+		t.Error("Expected an error adding tracy at a second path")
+	}
+
+	// Sessions use the usual name and start in the custom path
+	if started, err := StartAll(cfg, "testrig"); err != nil || !reflect.DeepEqual(started, []string{"tracy"}) {
+		t.Fatalf("StartAll() = %v, %v, want [tracy]", started, err)
+	}
+	if !tmux.SessionExists(cfg.GetCrewSessionName("testrig", "tracy")) {
+		t.Error("Expected session testrig@tracy")
+	}
+
+	testutil.StubStdin(t, "\n")
+	if err := Remove(cfg, "tracy", "testrig", RemoveOptions{}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(herePath); !os.IsNotExist(err) {
+		t.Error("Expected the custom worktree to be removed")
+	}
+	if _, err := os.Stat(parent); err != nil {
+		t.Error("Expected the custom worktree's parent to be kept")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	cfg := setupTestConfig(t)
+	stubSessionSet(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	// alex is a worktree in the CrewBase layout, tracy one created elsewhere
+	// with --here
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add(alex) error = %v", err)
+	}
+	herePath := filepath.Join(t.TempDir(), "tracy")
+	if err := Add(cfg, "tracy", "testrig", AddOptions{NoSession: true, Here: herePath}); err != nil {
+		t.Fatalf("Add(tracy) error = %v", err)
+	}
+
+	// A worktree elsewhere without crew metadata isn't crew
+	if err := git.CreateWorktree(repoPath, filepath.Join(t.TempDir(), "scratch"), "scratch", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	// sam only exists as a directory, for a rig whose repo is gone
+	samPath := cfg.GetCrewPath("gone", "sam")
+	os.MkdirAll(samPath, 0755)
+
+	// A plain directory in RigsBase isn't a rig
+	os.MkdirAll(filepath.Join(cfg.RigsBase, "notes-backup", "alex"), 0755)
+
+	got, err := Discover(cfg)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	want := []CrewInfo{
+		NewCrewInfo(cfg, "gone", "sam", samPath, "", nil),
+		NewCrewInfo(cfg, "testrig", "alex", cfg.GetCrewPath("testrig", "alex"), "alex/work", nil),
+		NewCrewInfo(cfg, "testrig", "tracy", herePath, "tracy/work", nil),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// stubSessionSet replaces listSessionSet with a snapshot of the given sessions
+func stubSessionSet(t *testing.T, sessions ...string) {
+	t.Helper()
+
+	orig := listSessionSet
+	listSessionSet = func() (tmux.SessionSet, error) {
+		return tmux.NewSessionSet(sessions), nil
+	}
+	t.Cleanup(func() { listSessionSet = orig })
+}
+
+func TestNewCrewInfo(t *testing.T) {
+	cfg := setupTestConfig(t)
+	running := tmux.NewSessionSet([]string{"my_app@polecat_emma", "notes@tracy"})
+
+	tests := []struct {
+		rig, name string
+		want      CrewInfo
+	}{
+		{"notes", "tracy", CrewInfo{Rig: "notes", Name: "tracy", SessionName: "notes@tracy", Running: true}},
+		{"notes", "alex", CrewInfo{Rig: "notes", Name: "alex", SessionName: "notes@alex"}},
+		{"my.app", "polecat_emma", CrewInfo{Rig: "my.app", Name: "polecat_emma", SessionName: "my.app@polecat_emma", Running: true, IsPolecat: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rig+"@"+tt.name, func(t *testing.T) {
+			tt.want.Path = "/crew/" + tt.name
+			tt.want.Branch = tt.name + "/work"
+			got := NewCrewInfo(cfg, tt.rig, tt.name, tt.want.Path, tt.want.Branch, running)
+			if got != tt.want {
+				t.Errorf("NewCrewInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// A nil snapshot means nothing is running
+	if NewCrewInfo(cfg, "notes", "tracy", "/crew/tracy", "", nil).Running {
+		t.Error("Expected nothing running without a session snapshot")
+	}
+}
+
+func TestAddBranchCollision(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	cmd := exec.Command("git", "branch", "release")
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"main", "crew name main is the rig's base branch"},
+		{"release", "crew name release collides with the existing branch release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Add(cfg, tt.name, "testrig", AddOptions{NoSession: true})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), tt.name+"-crew") {
+				t.Errorf("Add(%s) error = %v, want %q with a suggestion", tt.name, err, tt.wantErr)
+			}
+			if _, err := os.Stat(cfg.GetCrewPath("testrig", tt.name)); !os.IsNotExist(err) {
+				t.Errorf("Expected no workspace for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestAddTracksRemoteBranch(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+
+	// alex/work and blake/work exist only on origin, one commit ahead of main
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, repoPath, "init", "--bare", remotePath)
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+	runGit(t, repoPath, "checkout", "-b", "upstream")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "pushed work")
+	pushed := runGit(t, repoPath, "rev-parse", "HEAD")
+	runGit(t, repoPath, "push", "origin", "upstream:alex/work", "upstream:blake/work")
+	runGit(t, repoPath, "checkout", "main")
+	runGit(t, repoPath, "branch", "-D", "upstream")
+
+	testutil.StubStdin(t, "\n")
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	alexPath := cfg.GetCrewPath("testrig", "alex")
+	if head := runGit(t, alexPath, "rev-parse", "HEAD"); head != pushed {
+		t.Errorf("Expected alex at the pushed commit %s, got %s", pushed, head)
+	}
+	if upstream := runGit(t, alexPath, "rev-parse", "--abbrev-ref", "@{upstream}"); upstream != "origin/alex/work" {
+		t.Errorf("Expected alex to track origin/alex/work, got %s", upstream)
+	}
+
+	// Declining starts a fresh branch from main
+	testutil.StubStdin(t, "n\n")
+	if err := Add(cfg, "blake", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	blakePath := cfg.GetCrewPath("testrig", "blake")
+	if head, main := runGit(t, blakePath, "rev-parse", "HEAD"), runGit(t, repoPath, "rev-parse", "main"); head != main {
+		t.Errorf("Expected blake to start from main %s, got %s", main, head)
+	}
+}
+
+func TestStartAll(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	stubAttach(t)
+
+	for _, name := range []string{"alex", "sam"} {
+		if err := Add(cfg, name, "testrig", AddOptions{NoSession: true}); err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+	}
+	if err := Add(cfg, "tracy", "testrig", AddOptions{Detached: true}); err != nil {
+		t.Fatalf("Add(tracy) error = %v", err)
+	}
+
+	started, err := StartAll(cfg, "testrig")
+	if err != nil {
+		t.Fatalf("StartAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(started, []string{"alex", "sam"}) {
+		t.Errorf("StartAll() = %v, want [alex sam]", started)
+	}
+	for _, name := range []string{"alex", "sam", "tracy"} {
+		if !tmux.SessionExists(cfg.GetCrewSessionName("testrig", name)) {
+			t.Errorf("Expected session for %s", name)
+		}
+	}
+
+	// Everything is running now
+	started, err = StartAll(cfg, "testrig")
+	if err != nil || len(started) != 0 {
+		t.Errorf("StartAll() again = %v, %v, want nothing started", started, err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	attached := stubAttach(t)
+
+	if err := Add(cfg, "tracy", "testrig", AddOptions{Detached: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	sessionName := cfg.GetCrewSessionName("testrig", "tracy")
+
+	// Simulate the session dying while the worktree survives
+	if err := tmux.KillSession(sessionName); err != nil {
+		t.Fatalf("Failed to kill session: %v", err)
+	}
+
+	if err := Recover(cfg, "tracy", "testrig"); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if !tmux.SessionExists(sessionName) {
+		t.Error("Expected session to be recreated")
+	}
+	if !reflect.DeepEqual(*attached, []string{sessionName}) {
+		t.Errorf("Expected attach to %s, got %v", sessionName, *attached)
+	}
+
+	if err := Recover(cfg, "alex", "testrig"); err == nil || !strings.Contains(err.Error(), "no worktree") {
+		t.Errorf("Expected error for a crew without a worktree, got %v", err)
+	}
+}
+
+func TestMetaRoundTrip(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	createTestGitRepo(t, cfg.RigsBase, "testrig")
+	stubAttach(t)
+
+	if err := Add(cfg, "alex", "testrig", AddOptions{NoSession: true}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	crewPath := cfg.GetCrewPath("testrig", "alex")
+
+	// Add records the creation time
+	meta, err := ReadMeta(crewPath)
+	if err != nil || meta == nil {
+		t.Fatalf("ReadMeta() = %v, %v, want metadata", meta, err)
+	}
+	if time.Since(meta.CreatedAt) > time.Minute {
+		t.Errorf("CreatedAt = %v, want about now", meta.CreatedAt)
+	}
+
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	if err := WriteMeta(crewPath, Meta{CreatedAt: created}); err != nil {
+		t.Fatalf("WriteMeta() error = %v", err)
+	}
+	meta, err = ReadMeta(crewPath)
+	if err != nil || meta == nil {
+		t.Fatalf("ReadMeta() = %v, %v, want metadata", meta, err)
+	}
+	if !meta.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", meta.CreatedAt, created)
+	}
+
+	// The metadata file doesn't show up as a change in the worktree
+	status, err := exec.Command("git", "-C", crewPath, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatalf("git status error = %v", err)
+	}
+	if strings.TrimSpace(string(status)) != "" {
+		t.Errorf("Expected clean worktree, got:\n%s", status)
+	}
+
+	// Older workspaces have no metadata
+	os.Remove(filepath.Join(crewPath, MetaFile))
+	if meta, err := ReadMeta(crewPath); meta != nil || err != nil {
+		t.Errorf("ReadMeta() without metadata = %v, %v, want nil, nil", meta, err)
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+	stubSessionSet(t, "testrig@polecat_new", "testrig")
+
+	// Two polecats and two named crew, one of each idle for a month
+	old := time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	for name, idle := range map[string]bool{"polecat_old": true, "polecat_new": false, "tracy": true, "sam": false} {
+		crewPath := cfg.GetCrewPath("testrig", name)
+		if err := git.CreateWorktree(repoPath, crewPath, name+"/work", "main"); err != nil {
+			t.Fatalf("CreateWorktree(%s) error = %v", name, err)
+		}
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", "work")
+		cmd.Dir = crewPath
+		if idle {
+			cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+old)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to commit in %s: %v\n%s", name, err, output)
+		}
+	}
+
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		name     string
+		opts     PruneOptions
+		expected []string
+	}{
+		{"default is polecats", PruneOptions{}, []string{"polecat_new", "polecat_old"}},
+		{"explicit polecats", PruneOptions{Polecats: true}, []string{"polecat_new", "polecat_old"}},
+		{"all", PruneOptions{All: true}, []string{"polecat_new", "polecat_old", "sam", "tracy"}},
+		{"stale", PruneOptions{Stale: week}, []string{"polecat_old", "tracy"}},
+		{"stale polecats", PruneOptions{Stale: week, Polecats: true}, []string{"polecat_old"}},
+		{"stale all", PruneOptions{Stale: week, All: true}, []string{"polecat_old", "tracy"}},
+		{"nothing that stale", PruneOptions{Stale: 365 * 24 * time.Hour}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := PruneCandidates(cfg, tt.opts)
+			if err != nil {
+				t.Fatalf("PruneCandidates() error = %v", err)
+			}
+
+			var names []string
+			for _, c := range candidates {
+				names = append(names, c.Name)
+				if c.RigName != "testrig" || c.Path != cfg.GetCrewPath("testrig", c.Name) {
+					t.Errorf("Unexpected candidate %+v", c)
+				}
+				if (tt.opts.Stale > 0) == c.LastActive.IsZero() {
+					t.Errorf("LastActive = %v for %s with Stale %v", c.LastActive, c.Name, tt.opts.Stale)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("PruneCandidates() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+
+	// A workspace created recently from an old commit isn't stale
+	caseyPath := cfg.GetCrewPath("testrig", "casey")
+	if err := git.CreateWorktree(repoPath, caseyPath, "casey/work", "polecat_old/work"); err != nil {
+		t.Fatalf("CreateWorktree(casey) error = %v", err)
+	}
+	if err := WriteMeta(caseyPath, Meta{CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("WriteMeta() error = %v", err)
+	}
+	candidates, err := PruneCandidates(cfg, PruneOptions{Stale: week})
+	if err != nil {
+		t.Fatalf("PruneCandidates() error = %v", err)
+	}
+	for _, c := range candidates {
+		if c.Name == "casey" {
+			t.Errorf("Expected newly created casey not to be stale, got %+v", c)
+		}
+	}
+
+	// No crew directory and no sessions at all
+	stubSessionSet(t)
+	cfg.CrewBase = filepath.Join(t.TempDir(), "missing")
+	if candidates, err := PruneCandidates(cfg, PruneOptions{All: true}); err != nil || len(candidates) != 0 {
+		t.Errorf("PruneCandidates() without crew = %v, %v, want none", candidates, err)
+	}
+}
+
+func TestOrphanBranches(t *testing.T) {
+	cfg := &config.Config{}
+
+	branches := []string{"alex/work", "feat/add-auth", "main", "polecat_emma/work", "sam/notes", "tracy/work", "x/y/work"}
+	worktrees := []git.Worktree{
+		{Path: "/rigs/notes", Branch: "main"},
+		{Path: "/crew/notes/tracy", Branch: "tracy/work"},
+		{Path: "/crew/notes/detached"},
+	}
+
+	expected := []string{"alex/work", "polecat_emma/work"}
+	if got := orphanBranches(cfg, branches, worktrees); !reflect.DeepEqual(got, expected) {
+		t.Errorf("orphanBranches() = %v, want %v", got, expected)
+	}
+	if got := orphanBranches(cfg, []string{"main"}, worktrees); len(got) != 0 {
+		t.Errorf("orphanBranches() without crew branches = %v, want none", got)
+	}
+
+	// A worktree removed by hand leaves its branch behind
+	cfg = setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+	for _, name := range []string{"tracy", "alex"} {
+		if err := git.CreateWorktree(repoPath, cfg.GetCrewPath("testrig", name), name+"/work", "main"); err != nil {
+			t.Fatalf("CreateWorktree(%s) error = %v", name, err)
+		}
+	}
+	os.RemoveAll(cfg.GetCrewPath("testrig", "alex"))
+	git.PruneWorktrees(repoPath)
+
+	orphans, err := OrphanBranches(cfg, "testrig")
+	if err != nil {
+		t.Fatalf("OrphanBranches() error = %v", err)
+	}
+	if !reflect.DeepEqual(orphans, []string{"alex/work"}) {
+		t.Errorf("OrphanBranches() = %v, want [alex/work]", orphans)
+	}
+}
+
+func TestPruneCandidatesOrphanedSessions(t *testing.T) {
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+	if err := git.CreateWorktree(repoPath, cfg.GetCrewPath("testrig", "polecat_emma"), "polecat_emma/work", "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	// polecat_ghost and tracy have sessions but no directories; other@polecat_x
+	// isn't a session of any rig
+	stubSessionSet(t, "testrig", "testrig@polecat_emma", "testrig@polecat_ghost", "testrig@tracy", "other@polecat_x")
+
+	candidates, err := PruneCandidates(cfg, PruneOptions{})
+	if err != nil {
+		t.Fatalf("PruneCandidates() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("PruneCandidates() = %+v, want polecat_emma and polecat_ghost", candidates)
+	}
+	if c := candidates[0]; c.Name != "polecat_emma" || c.Orphaned {
+		t.Errorf("candidates[0] = %+v, want polecat_emma with a workspace", c)
+	}
+	if c := candidates[1]; c.Name != "polecat_ghost" || !c.Orphaned || c.Session != "testrig@polecat_ghost" || c.RigName != "testrig" {
+		t.Errorf("candidates[1] = %+v, want orphaned polecat_ghost session", c)
+	}
+
+	// Named crew sessions are only included with All
+	candidates, err = PruneCandidates(cfg, PruneOptions{All: true})
+	if err != nil {
+		t.Fatalf("PruneCandidates() error = %v", err)
+	}
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"polecat_emma", "polecat_ghost", "tracy"}) {
+		t.Errorf("PruneCandidates(All) = %v, want [polecat_emma polecat_ghost tracy]", names)
+	}
+
+	// tmux renames my.app's sessions to my_app
+	createTestGitRepo(t, cfg.RigsBase, "my.app")
+	stubSessionSet(t, "my_app@polecat_ghost")
+	candidates, err = PruneCandidates(cfg, PruneOptions{})
+	if err != nil {
+		t.Fatalf("PruneCandidates() error = %v", err)
+	}
+	if len(candidates) != 2 || candidates[1].RigName != "my.app" || candidates[1].Session != "my_app@polecat_ghost" || !candidates[1].Orphaned {
+		t.Errorf("PruneCandidates() = %+v, want polecat_emma and an orphaned polecat_ghost of my.app", candidates)
+	}
+}
+
+func TestSessionNameRoundTrip(t *testing.T) {
+	cfg := setupTestConfig(t)
+
+	// Enumerate short names over an alphabet that includes the separator and
+	// other awkward characters; every valid rig/crew pair must round-trip
+	alphabet := []string{"a", "b", ".", "-", "_", "@", ":", "/"}
+	names := []string{""}
+	for length := 1; length <= 3; length++ {
+		var next []string
+		for _, prefix := range names {
+			if len(prefix) != length-1 {
+				continue
+			}
+			for _, c := range alphabet {
+				next = append(next, prefix+c)
+			}
+		}
+		names = append(names, next...)
+	}
+
+	var rigs, crews []string
+	for _, name := range names {
+		if config.ValidateRigName(name) == nil {
+			rigs = append(rigs, name)
+		}
+		if ValidateCrewName(name) == nil {
+			crews = append(crews, name)
+		}
+	}
+	if len(rigs) == 0 || len(crews) == 0 {
+		t.Fatal("Expected some valid rig and crew names")
+	}
+
+	for _, r := range rigs {
+		for _, c := range crews {
+			rig, crew, isCrew := config.ParseSessionName(cfg.GetCrewSessionName(r, c))
+			if !isCrew || rig != r || crew != c {
+				t.Fatalf("ParseSessionName(GetCrewSessionName(%q, %q)) = (%q, %q, %v)", r, c, rig, crew, isCrew)
+			}
+		}
+	}
+}
+
+func TestAddFrom(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+	stubAttach(t)
+
+	sourcePath := cfg.GetCrewPath("testrig", "polecat_emma")
+	os.MkdirAll(filepath.Dir(sourcePath), 0755)
+	if err := git.CreateWorktree(repoPath, sourcePath, "polecat_emma/work", "main"); err != nil {
+		t.Fatalf("Failed to create source worktree: %v", err)
+	}
+	os.WriteFile(filepath.Join(sourcePath, "feature.txt"), []byte("in progress"), 0644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "WIP feature"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = sourcePath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	t.Run("branches off source crew", func(t *testing.T) {
+		if err := Add(cfg, "reviewer", "testrig", AddOptions{Detached: true, From: "polecat_emma"}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		reviewerPath := cfg.GetCrewPath("testrig", "reviewer")
+		branch, _ := git.GetCurrentBranch(reviewerPath)
+		if branch != "reviewer/work" {
+			t.Errorf("Expected reviewer/work, got %s", branch)
+		}
+
+		cmd := exec.Command("git", "merge-base", "--is-ancestor", "polecat_emma/work", "reviewer/work")
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			t.Error("Expected reviewer branch to contain the source branch history")
+		}
+		if _, err := os.Stat(filepath.Join(reviewerPath, "feature.txt")); err != nil {
+			t.Error("Expected source crew's committed work in new workspace")
+		}
+	})
+
+	t.Run("existing branch", func(t *testing.T) {
+		cmd := exec.Command("git", "branch", "casey/work")
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to create branch: %v", err)
+		}
+		err := Add(cfg, "casey", "testrig", AddOptions{Detached: true, From: "polecat_emma"})
+		if err == nil || !strings.Contains(err.Error(), "branch casey/work already exists, so it can't start from polecat_emma/work") {
+			t.Errorf("Expected existing branch error, got %v", err)
+		}
+		if _, statErr := os.Stat(cfg.GetCrewPath("testrig", "casey")); !os.IsNotExist(statErr) {
+			t.Error("Expected no workspace to be created")
+		}
+	})
+
+	t.Run("missing source crew", func(t *testing.T) {
+		err := Add(cfg, "other", "testrig", AddOptions{Detached: true, From: "nobody"})
+		if err == nil {
+			t.Fatal("Expected error for missing source crew")
+		}
+		if _, statErr := os.Stat(cfg.GetCrewPath("testrig", "other")); !os.IsNotExist(statErr) {
+			t.Error("Expected no workspace to be created")
+		}
+	})
+}
+
+func TestSpawn(t *testing.T) {
+	useTestTmux(t)
+	cfg := setupTestConfig(t)
+	repoPath := createTestGitRepo(t, cfg.RigsBase, "testrig")
+	calls := stubAttach(t)
+
+	created, err := Spawn(cfg, "testrig", 3)
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("Expected 3 polecats, got %v", created)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range created {
+		if !polecat.IsPolecat(name) {
+			t.Errorf("Expected polecat name, got %s", name)
+		}
+		if seen[name] {
+			t.Errorf("Duplicate polecat name %s", name)
+		}
+		seen[name] = true
+
+		crewPath := cfg.GetCrewPath("testrig", name)
+		if !git.WorktreeExists(repoPath, crewPath) {
+			t.Errorf("Expected worktree for %s", name)
+		}
+		if !tmux.SessionExists(cfg.GetCrewSessionName("testrig", name)) {
+			t.Errorf("Expected session for %s", name)
+		}
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("Expected spawned polecats to be detached, got attaches %v", *calls)
+	}
+}
+
+func TestSpawnPartialFailure(t *testing.T) {
+	cfg := setupTestConfig(t)
+
+	// No repo for the rig, so the first Add fails
+	created, err := Spawn(cfg, "missing", 2)
+	if err == nil {
+		t.Fatal("Expected error for missing rig")
+	}
+	if len(created) != 0 {
+		t.Errorf("Expected nothing created, got %v", created)
+	}
+	if !strings.Contains(err.Error(), "created 0 of 2") {
+		t.Errorf("Expected partial progress in error, got %v", err)
+	}
+}
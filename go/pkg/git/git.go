@@ -5,7 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // BranchExists checks if a git branch exists
@@ -15,18 +18,56 @@ func BranchExists(repoPath, branchName string) bool {
 	return cmd.Run() == nil
 }
 
-// GetBaseBranch returns the base branch to use, inferring from origin/HEAD if possible
-func GetBaseBranch(repoPath, defaultBranch string) (string, error) {
-	// First, try to infer from the remote's default branch
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+// RefExists checks if ref resolves to a commit: a branch, tag, or SHA
+func RefExists(repoPath, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = repoPath
+	return cmd.Run() == nil
+}
+
+// ListBranches returns the repository's local branches, sorted by name
+func ListBranches(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads")
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
-	if err == nil {
-		// Output will be something like "refs/remotes/origin/main"
-		ref := strings.TrimSpace(string(output))
-		branch := strings.TrimPrefix(ref, "refs/remotes/origin/")
-		if branch != "" && BranchExists(repoPath, branch) {
-			return branch, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	branches := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+// HasRemote reports whether the repository has any remotes configured.
+// Local-only repos should skip fetches and other remote operations.
+func HasRemote(repoPath string) bool {
+	cmd := exec.Command("git", "remote")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// GetBaseBranch returns the base branch to use, inferring from origin/HEAD if
+// possible, then falling back to the configured default, common names,
+// init.defaultBranch, and finally the repo's only top-level branch
+func GetBaseBranch(repoPath, defaultBranch string) (string, error) {
+	// First, try to infer from the remote's default branch
+	if HasRemote(repoPath) {
+		cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+		cmd.Dir = repoPath
+		output, err := cmd.Output()
+		if err == nil {
+			// Output will be something like "refs/remotes/origin/main"
+			ref := strings.TrimSpace(string(output))
+			branch := strings.TrimPrefix(ref, "refs/remotes/origin/")
+			if branch != "" && BranchExists(repoPath, branch) {
+				return branch, nil
+			}
 		}
 	}
 
@@ -35,14 +76,52 @@ func GetBaseBranch(repoPath, defaultBranch string) (string, error) {
 		return defaultBranch, nil
 	}
 
-	// Last resort: try common default branch names
+	// Then try common default branch names
 	for _, branch := range []string{"main", "master", "develop"} {
 		if BranchExists(repoPath, branch) {
 			return branch, nil
 		}
 	}
 
-	return "", fmt.Errorf("could not find base branch (tried: origin/HEAD, %s, main, master, develop)", defaultBranch)
+	// Then the branch git itself would have created
+	cmd := exec.Command("git", "config", "init.defaultBranch")
+	cmd.Dir = repoPath
+	if output, err := cmd.Output(); err == nil {
+		branch := strings.TrimSpace(string(output))
+		if branch != "" && BranchExists(repoPath, branch) {
+			return branch, nil
+		}
+	}
+
+	// Last resort: a repo with a single top-level branch (e.g. "trunk")
+	if branch := soleTopLevelBranch(repoPath); branch != "" {
+		return branch, nil
+	}
+
+	return "", fmt.Errorf("could not find base branch (tried: origin/HEAD, %s, main, master, develop, init.defaultBranch, local branches)", defaultBranch)
+}
+
+// soleTopLevelBranch returns the only local branch without a "/" in its name,
+// or "" if there are none or several. Crew and feature branches like
+// tracy/work or feat/login are namespaced, so this finds an unusually named
+// base branch next to them.
+func soleTopLevelBranch(repoPath string) string {
+	branches, err := ListBranches(repoPath)
+	if err != nil {
+		return ""
+	}
+
+	found := ""
+	for _, branch := range branches {
+		if strings.Contains(branch, "/") {
+			continue
+		}
+		if found != "" {
+			return ""
+		}
+		found = branch
+	}
+	return found
 }
 
 // WorktreeExists checks if a worktree exists at the given path
@@ -56,6 +135,16 @@ func WorktreeExists(repoPath, worktreePath string) bool {
 	return strings.Contains(string(output), worktreePath)
 }
 
+// Clone clones a repository into dest
+func Clone(url, dest string) error {
+	cmd := exec.Command("git", "clone", url, dest)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to clone %s: %w\n%s", url, err, string(output))
+	}
+	return nil
+}
+
 // CreateWorktree creates a new git worktree
 func CreateWorktree(repoPath, worktreePath, branchName, baseBranch string) error {
 	cmd := exec.Command("git", "worktree", "add", worktreePath, "-b", branchName, baseBranch)
@@ -67,6 +156,27 @@ func CreateWorktree(repoPath, worktreePath, branchName, baseBranch string) error
 	return nil
 }
 
+// CreateWorktreeAt creates a new git worktree on a new branch starting at any
+// committish, such as a tag or a specific commit
+func CreateWorktreeAt(repoPath, worktreePath, branchName, startPoint string) error {
+	if !RefExists(repoPath, startPoint) {
+		return fmt.Errorf("ref not found: %s", startPoint)
+	}
+	return CreateWorktree(repoPath, worktreePath, branchName, startPoint)
+}
+
+// CreateWorktreeTracking creates a worktree on a new local branch that
+// tracks the branch of the same name on origin
+func CreateWorktreeTracking(repoPath, worktreePath, branchName string) error {
+	cmd := exec.Command("git", "worktree", "add", "--track", "-b", branchName, worktreePath, "origin/"+branchName)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create worktree tracking origin/%s: %w\n%s", branchName, err, string(output))
+	}
+	return nil
+}
+
 // CreateWorktreeFromExisting creates a worktree from an existing branch
 func CreateWorktreeFromExisting(repoPath, worktreePath, branchName string) error {
 	cmd := exec.Command("git", "worktree", "add", worktreePath, branchName)
@@ -85,6 +195,40 @@ func RemoveWorktree(repoPath, worktreePath string) error {
 	return cmd.Run()
 }
 
+// IsWorktreeLocked reports whether a worktree is protected with
+// `git worktree lock`
+func IsWorktreeLocked(repoPath, worktreePath string) bool {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	var currentPath string
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "worktree ") {
+			currentPath = strings.TrimPrefix(line, "worktree ")
+		} else if line == "locked" || strings.HasPrefix(line, "locked ") {
+			if filepath.Clean(currentPath) == filepath.Clean(worktreePath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnlockWorktree removes a `git worktree lock` from a worktree
+func UnlockWorktree(repoPath, worktreePath string) error {
+	cmd := exec.Command("git", "worktree", "unlock", worktreePath)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to unlock worktree: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
 // PruneWorktrees prunes stale worktree metadata
 func PruneWorktrees(repoPath string) error {
 	cmd := exec.Command("git", "worktree", "prune")
@@ -99,6 +243,17 @@ func DeleteBranch(repoPath, branchName string) error {
 	return cmd.Run()
 }
 
+// CommitsAhead returns how many commits branch has that base doesn't
+func CommitsAhead(repoPath, base, branch string) (int, error) {
+	cmd := exec.Command("git", "rev-list", "--count", base+".."+branch)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare %s with %s: %w\n%s", branch, base, err, output)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
 // GetCurrentBranch returns the current branch in a git directory
 func GetCurrentBranch(path string) (string, error) {
 	cmd := exec.Command("git", "branch", "--show-current")
@@ -110,6 +265,189 @@ func GetCurrentBranch(path string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetUpstream returns the upstream a branch tracks, like "origin/main", or ""
+// if it has none
+func GetUpstream(repoPath, branch string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", branch+"@{upstream}")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	// rev-parse fails the same way for a missing upstream as for anything
+	// else, so check the branch config directly
+	if BranchExists(repoPath, branch) {
+		config := exec.Command("git", "config", "--get", "branch."+branch+".merge")
+		config.Dir = repoPath
+		if config.Run() != nil {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("failed to get upstream of %s: %w\n%s", branch, err, output)
+}
+
+// SetUpstream sets the upstream of branch to remoteRef, e.g. "origin/main"
+func SetUpstream(repoPath, branch, remoteRef string) error {
+	cmd := exec.Command("git", "branch", "--set-upstream-to="+remoteRef, branch)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to set upstream of %s to %s: %w\n%s", branch, remoteRef, err, output)
+	}
+	return nil
+}
+
+// SetBranchConfig sets branch.<branch>.<key> in the repo's config
+func SetBranchConfig(repoPath, branch, key, value string) error {
+	cmd := exec.Command("git", "config", "branch."+branch+"."+key, value)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to set %s config for %s: %w\n%s", key, branch, err, output)
+	}
+	return nil
+}
+
+// GetBranchConfig returns branch.<branch>.<key> from the repo's config, or ""
+// if it isn't set
+func GetBranchConfig(repoPath, branch, key string) string {
+	cmd := exec.Command("git", "config", "--get", "branch."+branch+"."+key)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// UnsetBranchConfig removes branch.<branch>.<key> from the repo's config, if
+// it's set
+func UnsetBranchConfig(repoPath, branch, key string) error {
+	if GetBranchConfig(repoPath, branch, key) == "" {
+		return nil
+	}
+	cmd := exec.Command("git", "config", "--unset", "branch."+branch+"."+key)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to unset %s config for %s: %w\n%s", key, branch, err, output)
+	}
+	return nil
+}
+
+// BranchesWithConfig returns the branches whose branch.<branch>.<key> is
+// value
+func BranchesWithConfig(repoPath, key, value string) []string {
+	// git lowercases the key in the names it prints
+	cmd := exec.Command("git", "config", "--get-regexp", `^branch\..*\.`+strings.ToLower(key)+`$`)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, v, _ := strings.Cut(line, " ")
+		if v != value {
+			continue
+		}
+		// Branch names may contain dots; the key follows the last one
+		name = strings.TrimPrefix(name, "branch.")
+		if i := strings.LastIndex(name, "."); i > 0 {
+			branches = append(branches, name[:i])
+		}
+	}
+	return branches
+}
+
+// Push pushes branch to origin
+func Push(repoPath, branch string) error {
+	return push(repoPath, branch)
+}
+
+// PushUpstream pushes branch to origin and sets it as the branch's upstream
+func PushUpstream(repoPath, branch string) error {
+	return push(repoPath, branch, "-u")
+}
+
+func push(repoPath, branch string, extraArgs ...string) error {
+	args := append([]string{"push", "--quiet"}, extraArgs...)
+	args = append(args, "origin", branch)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push %s: %w\n%s", branch, err, output)
+	}
+	return nil
+}
+
+// CurrentCommitHash returns the short hash of HEAD. It fails on an unborn
+// branch with no commits yet.
+func CurrentCommitHash(path string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--short", "--verify", "--quiet", "HEAD")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD commit in %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// LastCommitTime returns the committer time of HEAD
+func LastCommitTime(path string) (time.Time, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	cmd.Dir = path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last commit in %s: %w\n%s", path, err, output)
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// AddPaths stages paths, relative to repoPath
+func AddPaths(repoPath string, paths ...string) error {
+	args := append([]string{"add", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w\n%s", strings.Join(paths, " "), err, string(output))
+	}
+	return nil
+}
+
+// RemoveCached unstages paths, relative to repoPath, leaving the files on
+// disk. Paths that aren't tracked are ignored.
+func RemoveCached(repoPath string, paths ...string) error {
+	args := append([]string{"rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to unstage %s: %w\n%s", strings.Join(paths, " "), err, string(output))
+	}
+	return nil
+}
+
+// Commit commits what's staged with message
+func Commit(repoPath, message string) error {
+	cmd := exec.Command("git", "commit", "-m", message)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
 // CheckoutBranch checks out a branch
 func CheckoutBranch(path, branchName string) error {
 	cmd := exec.Command("git", "checkout", branchName)
@@ -132,11 +470,93 @@ func GetRepoRoot(path string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// IsGitRepo checks if a directory is a git repository
+// GetMainRepoRoot returns the root of the main working tree for path. For a
+// linked worktree this is the repository the worktree was created from; for
+// the main working tree it is the same as GetRepoRoot.
+func GetMainRepoRoot(path string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	commonDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(path, commonDir)
+	}
+	if filepath.Base(commonDir) != ".git" {
+		return "", fmt.Errorf("no main working tree for %s (git dir: %s)", path, commonDir)
+	}
+	return filepath.Dir(commonDir), nil
+}
+
+// AddExclude adds a pattern to the repository's info/exclude file (shared by
+// all of its worktrees) unless it is already listed
+func AddExclude(path, pattern string) error {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "info/exclude")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to find exclude file: %w", err)
+	}
+
+	excludePath := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(path, excludePath)
+	}
+
+	existing, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read exclude file: %w", err)
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return fmt.Errorf("failed to create exclude directory: %w", err)
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open exclude file: %w", err)
+	}
+	defer f.Close()
+
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		pattern = "\n" + pattern
+	}
+	if _, err := f.WriteString(pattern + "\n"); err != nil {
+		return fmt.Errorf("failed to write exclude file: %w", err)
+	}
+	return nil
+}
+
+// IsGitRepo checks if a directory is the root of a git working tree
+// (including linked worktrees and submodules) or a bare repository
 func IsGitRepo(path string) bool {
-	gitPath := filepath.Join(path, ".git")
-	_, err := os.Stat(gitPath)
-	return err == nil
+	// Fast path: a .git directory means a regular checkout
+	if info, err := os.Stat(filepath.Join(path, ".git")); err == nil && info.IsDir() {
+		return true
+	}
+
+	// A .git file (worktree/submodule) or no .git at all (bare repo) needs git
+	// to decide. Output is "<is-bare>\n<cdup>\n"; cdup is empty at the top of a
+	// working tree and omitted entirely when there is no working tree.
+	cmd := exec.Command("git", "rev-parse", "--is-bare-repository", "--show-cdup")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	lines := strings.Split(string(output), "\n")
+	if lines[0] == "true" {
+		return true
+	}
+	return len(lines) == 3 && lines[1] == ""
 }
 
 // CreateFeatureBranch creates a new feature branch from a base branch
@@ -188,6 +608,114 @@ func ListWorktrees(repoPath string) ([]Worktree, error) {
 	return worktrees, nil
 }
 
+// WorktreeInfo describes the state of a single worktree
+type WorktreeInfo struct {
+	Path     string
+	Branch   string // empty when detached
+	Detached bool
+	Dirty    bool // uncommitted changes or untracked files
+}
+
+// WorktreeStatus returns every worktree of a repository with its branch and
+// dirty state. Dirtiness is checked for all worktrees concurrently.
+func WorktreeStatus(repoPath string) ([]WorktreeInfo, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	// Entries are blank-line separated blocks of "key value" lines
+	var worktrees []WorktreeInfo
+	for _, block := range strings.Split(strings.TrimSpace(string(output)), "\n\n") {
+		var info WorktreeInfo
+		bare := false
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "worktree "):
+				info.Path = strings.TrimPrefix(line, "worktree ")
+			case strings.HasPrefix(line, "branch "):
+				info.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+			case line == "detached":
+				info.Detached = true
+			case line == "bare":
+				bare = true
+			}
+		}
+		if info.Path != "" && !bare {
+			worktrees = append(worktrees, info)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range worktrees {
+		wg.Add(1)
+		go func(info *WorktreeInfo) {
+			defer wg.Done()
+			info.Dirty = IsDirty(info.Path)
+		}(&worktrees[i])
+	}
+	wg.Wait()
+
+	return worktrees, nil
+}
+
+// IsDirty reports whether a worktree has uncommitted changes or untracked
+// files. Missing or unreadable worktrees are reported clean.
+func IsDirty(worktreePath string) bool {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return len(strings.TrimSpace(string(output))) > 0
+}
+
+// StatusEntry is one changed path from git status
+type StatusEntry struct {
+	Code     string // two-letter XY code, e.g. " M", "A " or "??"
+	Path     string
+	OrigPath string // the path before a rename or copy
+}
+
+// StatusPorcelain returns the uncommitted changes and untracked files in a
+// worktree, limited to pathspec unless it's empty
+func StatusPorcelain(path, pathspec string) ([]StatusEntry, error) {
+	args := []string{"status", "--porcelain", "-z"}
+	if pathspec != "" {
+		args = append(args, "--", pathspec)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git status: %w", err)
+	}
+	return parsePorcelain(string(output)), nil
+}
+
+// parsePorcelain parses `git status --porcelain -z` output, where a rename or
+// copy is followed by its original path as a separate entry
+func parsePorcelain(output string) []StatusEntry {
+	var entries []StatusEntry
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) < 4 {
+			continue
+		}
+		entry := StatusEntry{Code: field[:2], Path: field[3:]}
+		if (entry.Code[0] == 'R' || entry.Code[0] == 'C') && i+1 < len(fields) {
+			i++
+			entry.OrigPath = fields[i]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
 // GetWorktreeForBranch returns the worktree path for a given branch
 func GetWorktreeForBranch(repoPath, branchName string) (string, error) {
 	worktrees, err := ListWorktrees(repoPath)
@@ -203,3 +731,308 @@ func GetWorktreeForBranch(repoPath, branchName string) (string, error) {
 
 	return "", fmt.Errorf("no worktree found for branch: %s", branchName)
 }
+
+// Repo is a git repository whose root has been resolved once. Commands that
+// run several git operations against the same repo should open it once and
+// use its methods instead of re-deriving the root for each call.
+type Repo struct {
+	Root string
+}
+
+// OpenRepo resolves the root of the repository containing path
+func OpenRepo(path string) (*Repo, error) {
+	root, err := GetRepoRoot(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Repo{Root: root}, nil
+}
+
+// BranchExists checks if a branch exists in the repo
+func (r *Repo) BranchExists(branchName string) bool {
+	return BranchExists(r.Root, branchName)
+}
+
+// BaseBranch returns the base branch for the repo
+func (r *Repo) BaseBranch(defaultBranch string) (string, error) {
+	return GetBaseBranch(r.Root, defaultBranch)
+}
+
+// CurrentBranch returns the branch checked out in the repo root
+func (r *Repo) CurrentBranch() (string, error) {
+	return GetCurrentBranch(r.Root)
+}
+
+// CheckoutBranch checks out a branch in the repo root
+func (r *Repo) CheckoutBranch(branchName string) error {
+	return CheckoutBranch(r.Root, branchName)
+}
+
+// CreateFeatureBranch creates and checks out a feature branch from a base branch
+func (r *Repo) CreateFeatureBranch(branchName, baseBranch string) error {
+	return CreateFeatureBranch(r.Root, branchName, baseBranch)
+}
+
+// ListWorktrees returns all worktrees of the repo
+func (r *Repo) ListWorktrees() ([]Worktree, error) {
+	return ListWorktrees(r.Root)
+}
+
+// WorktreeForBranch returns the worktree path for a given branch
+func (r *Repo) WorktreeForBranch(branchName string) (string, error) {
+	return GetWorktreeForBranch(r.Root, branchName)
+}
+
+// CreateWorktreeFromExisting creates a worktree from an existing branch
+func (r *Repo) CreateWorktreeFromExisting(worktreePath, branchName string) error {
+	return CreateWorktreeFromExisting(r.Root, worktreePath, branchName)
+}
+
+// RemoveWorktree removes a worktree of the repo
+func (r *Repo) RemoveWorktree(worktreePath string) error {
+	return RemoveWorktree(r.Root, worktreePath)
+}
+
+// PruneWorktrees prunes stale worktree metadata
+func (r *Repo) PruneWorktrees() error {
+	return PruneWorktrees(r.Root)
+}
+
+// RecentCommits returns up to n recent commits in path as one-line summaries
+func RecentCommits(path string, n int) ([]string, error) {
+	return RecentCommitsSince(path, n, "")
+}
+
+// RecentCommitsSince returns up to n recent commits in path as one-line
+// summaries, limited to commits newer than since (any date git log accepts,
+// e.g. "2 days ago"). An empty since applies no date limit.
+func RecentCommitsSince(path string, n int, since string) ([]string, error) {
+	args := []string{"log", "--oneline", "-n", strconv.Itoa(n)}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w\n%s", err, string(output))
+	}
+
+	commits := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// DiffStat returns the diffstat of branch against its merge base with base
+func DiffStat(repoPath, base, branch string) (string, error) {
+	return diff(repoPath, base, branch, "--stat")
+}
+
+// Diff returns the full patch of branch against its merge base with base
+func Diff(repoPath, base, branch string) (string, error) {
+	return diff(repoPath, base, branch)
+}
+
+func diff(repoPath, base, branch string, extraArgs ...string) (string, error) {
+	args := append([]string{"diff", base + "..." + branch}, extraArgs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s...%s: %w\n%s", base, branch, err, string(output))
+	}
+	return string(output), nil
+}
+
+// FormatPatch writes one patch file per commit in base..branch into outDir
+// and returns their paths in commit order
+func FormatPatch(repoPath, base, branch, outDir string) ([]string, error) {
+	absDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", outDir, err)
+	}
+
+	cmd := exec.Command("git", "format-patch", "-o", absDir, base+".."+branch)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format patches for %s..%s: %w\n%s", base, branch, err, string(output))
+	}
+
+	patches := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			patches = append(patches, line)
+		}
+	}
+	return patches, nil
+}
+
+// MergeStatus describes the outcome of a merge
+type MergeStatus int
+
+const (
+	// MergeClean means the branch was merged without conflicts
+	MergeClean MergeStatus = iota
+	// MergeConflicted means the merge stopped on conflicts and was aborted
+	MergeConflicted
+	// MergeAlreadyUpToDate means the branch had nothing new to merge
+	MergeAlreadyUpToDate
+)
+
+// MergeResult is the outcome of Merge
+type MergeResult struct {
+	Status MergeStatus
+	Files  []string // Conflicting files when Status is MergeConflicted
+}
+
+// Merge merges branch into the branch checked out at repoPath. A conflicted
+// merge is aborted so the repo is left as it was; the conflicting files are
+// reported in the result. An error is returned only for failures other than
+// conflicts (unknown branch, dirty worktree, etc.).
+func Merge(repoPath, branch string) (MergeResult, error) {
+	// Nothing to do if branch is already contained in HEAD
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", branch, "HEAD")
+	cmd.Dir = repoPath
+	if cmd.Run() == nil {
+		return MergeResult{Status: MergeAlreadyUpToDate}, nil
+	}
+
+	cmd = exec.Command("git", "merge", "--no-edit", branch)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return MergeResult{Status: MergeClean}, nil
+	}
+
+	files, _ := conflictedFiles(repoPath)
+	if len(files) == 0 {
+		return MergeResult{}, fmt.Errorf("failed to merge %s: %w\n%s", branch, err, string(output))
+	}
+
+	abortCmd := exec.Command("git", "merge", "--abort")
+	abortCmd.Dir = repoPath
+	if abortOutput, err := abortCmd.CombinedOutput(); err != nil {
+		return MergeResult{}, fmt.Errorf("failed to abort conflicted merge of %s: %w\n%s", branch, err, string(abortOutput))
+	}
+
+	return MergeResult{Status: MergeConflicted, Files: files}, nil
+}
+
+// conflictedFiles returns the paths with unresolved merge conflicts
+func conflictedFiles(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// RebaseConflictError is returned by RebaseOnto when the rebase stopped on
+// conflicts. The rebase has been aborted by the time it is returned.
+type RebaseConflictError struct {
+	Base  string
+	Files []string
+}
+
+func (e *RebaseConflictError) Error() string {
+	return fmt.Sprintf("rebase onto %s has conflicts in: %s", e.Base, strings.Join(e.Files, ", "))
+}
+
+// RebaseOnto rebases the branch checked out at worktreePath onto base. A
+// conflicted rebase is aborted, leaving the branch as it was, and a
+// *RebaseConflictError listing the conflicting files is returned.
+func RebaseOnto(worktreePath, base string) error {
+	cmd := exec.Command("git", "rebase", base)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	files, _ := conflictedFiles(worktreePath)
+	if len(files) == 0 {
+		return fmt.Errorf("failed to rebase onto %s: %w\n%s", base, err, string(output))
+	}
+
+	abortCmd := exec.Command("git", "rebase", "--abort")
+	abortCmd.Dir = worktreePath
+	if abortOutput, err := abortCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort conflicted rebase onto %s: %w\n%s", base, err, string(abortOutput))
+	}
+
+	return &RebaseConflictError{Base: base, Files: files}
+}
+
+// PatchConflictError is returned by ApplyPatch when a patch does not apply
+// cleanly. The apply has been aborted by the time it is returned.
+type PatchConflictError struct {
+	Patch string
+	Files []string
+}
+
+func (e *PatchConflictError) Error() string {
+	if len(e.Files) == 0 {
+		return fmt.Sprintf("patch %s does not apply", e.Patch)
+	}
+	return fmt.Sprintf("patch %s has conflicts in: %s", e.Patch, strings.Join(e.Files, ", "))
+}
+
+// ApplyPatch applies a format-patch mbox to the worktree at worktreePath
+// with `git am`, committing each patch. A patch that doesn't apply is
+// aborted, leaving the worktree as it was, and a *PatchConflictError is
+// returned.
+func ApplyPatch(worktreePath, patchFile string) error {
+	absPatch, err := filepath.Abs(patchFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", patchFile, err)
+	}
+
+	cmd := exec.Command("git", "am", "--3way", absPatch)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	// If no am session was started the file couldn't be read or parsed
+	files, _ := conflictedFiles(worktreePath)
+	abortCmd := exec.Command("git", "am", "--abort")
+	abortCmd.Dir = worktreePath
+	if abortCmd.Run() != nil {
+		return fmt.Errorf("failed to apply patch %s: %w\n%s", patchFile, err, string(output))
+	}
+
+	return &PatchConflictError{Patch: patchFile, Files: files}
+}
+
+// Fetch fetches from all remotes
+func Fetch(repoPath string) error {
+	cmd := exec.Command("git", "fetch", "--all", "--quiet")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// RemoteBranchExists checks if a remote-tracking branch exists, e.g. origin/main
+func RemoteBranchExists(repoPath, remoteBranch string) bool {
+	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/"+remoteBranch)
+	cmd.Dir = repoPath
+	return cmd.Run() == nil
+}
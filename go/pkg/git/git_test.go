@@ -1,10 +1,14 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func createTestRepo(t *testing.T) string {
@@ -25,8 +29,11 @@ func createTestRepo(t *testing.T) string {
 	}
 
 	// Configure git
-	exec.Command("git", "config", "user.name", "Test User").Run()
-	exec.Command("git", "config", "user.email", "test@example.com").Run()
+	for _, args := range [][]string{{"config", "user.name", "Test User"}, {"config", "user.email", "test@example.com"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Run()
+	}
 
 	// Create initial commit on main branch
 	cmd = exec.Command("git", "checkout", "-b", "main")
@@ -63,6 +70,26 @@ func TestBranchExists(t *testing.T) {
 	}
 }
 
+func TestListBranches(t *testing.T) {
+	repoPath := createTestRepo(t)
+	for _, branch := range []string{"tracy/work", "feat/login", "alex/work"} {
+		runGit(t, repoPath, "branch", branch)
+	}
+
+	branches, err := ListBranches(repoPath)
+	if err != nil {
+		t.Fatalf("ListBranches() error = %v", err)
+	}
+	expected := []string{"alex/work", "feat/login", "main", "tracy/work"}
+	if !reflect.DeepEqual(branches, expected) {
+		t.Errorf("ListBranches() = %v, want %v", branches, expected)
+	}
+
+	if _, err := ListBranches(t.TempDir()); err == nil {
+		t.Error("Expected error outside a git repo")
+	}
+}
+
 func TestGetBaseBranch(t *testing.T) {
 	repoPath := createTestRepo(t)
 
@@ -82,6 +109,11 @@ func TestGetBaseBranch(t *testing.T) {
 		cmd.Dir = repoPath
 		cmd.Run()
 
+		// main is tried before master, so drop it to leave only master
+		cmd = exec.Command("git", "branch", "-D", "main")
+		cmd.Dir = repoPath
+		cmd.Run()
+
 		branch, err := GetBaseBranch(repoPath, "nonexistent")
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -109,6 +141,41 @@ func TestGetBaseBranch(t *testing.T) {
 			t.Error("Expected error when no base branch exists")
 		}
 	})
+
+	t.Run("finds a trunk-only repo", func(t *testing.T) {
+		trunkRepo := createTestRepo(t)
+		runGit(t, trunkRepo, "branch", "-m", "main", "trunk")
+		runGit(t, trunkRepo, "branch", "tracy/work")
+		runGit(t, trunkRepo, "branch", "feat/login")
+
+		branch, err := GetBaseBranch(trunkRepo, "main")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if branch != "trunk" {
+			t.Errorf("Expected trunk, got %s", branch)
+		}
+	})
+
+	t.Run("uses init.defaultBranch", func(t *testing.T) {
+		repo := createTestRepo(t)
+		runGit(t, repo, "branch", "-m", "main", "trunk")
+		runGit(t, repo, "branch", "stable")
+
+		// Two top-level branches is ambiguous
+		if branch, err := GetBaseBranch(repo, "main"); err == nil {
+			t.Errorf("Expected error with trunk and stable, got %s", branch)
+		}
+
+		runGit(t, repo, "config", "init.defaultBranch", "stable")
+		branch, err := GetBaseBranch(repo, "main")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if branch != "stable" {
+			t.Errorf("Expected stable, got %s", branch)
+		}
+	})
 }
 
 func TestWorktreeOperations(t *testing.T) {
@@ -148,6 +215,81 @@ func TestWorktreeOperations(t *testing.T) {
 	})
 }
 
+func TestCreateWorktreeAt(t *testing.T) {
+	repoPath := createTestRepo(t)
+	runGit(t, repoPath, "tag", "v1.0")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "after tag")
+
+	tagCommit := revParse(t, repoPath, "v1.0^{commit}")
+
+	if !RefExists(repoPath, "v1.0") || !RefExists(repoPath, tagCommit[:7]) {
+		t.Error("Expected the tag and its short SHA to resolve")
+	}
+	if RefExists(repoPath, "v9.9") {
+		t.Error("Expected a missing tag not to resolve")
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "worktree")
+	if err := CreateWorktreeAt(repoPath, worktreePath, "test/tagged", "v1.0"); err != nil {
+		t.Fatalf("CreateWorktreeAt() error = %v", err)
+	}
+	if head := revParse(t, worktreePath, "HEAD"); head != tagCommit {
+		t.Errorf("Expected worktree at tag commit %s, got %s", tagCommit, head)
+	}
+	if branch, _ := GetCurrentBranch(worktreePath); branch != "test/tagged" {
+		t.Errorf("Expected branch test/tagged, got %s", branch)
+	}
+
+	missingPath := filepath.Join(t.TempDir(), "missing")
+	err := CreateWorktreeAt(repoPath, missingPath, "test/missing", "v9.9")
+	if err == nil || !strings.Contains(err.Error(), "ref not found: v9.9") {
+		t.Errorf("Expected ref not found error, got %v", err)
+	}
+	if BranchExists(repoPath, "test/missing") {
+		t.Error("Expected no branch for a missing ref")
+	}
+}
+
+// revParse returns the full SHA that rev resolves to
+func revParse(t *testing.T, repoPath, rev string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", "rev-parse", rev)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse %s failed: %v", rev, err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestIsWorktreeLocked(t *testing.T) {
+	repoPath := createTestRepo(t)
+	worktreePath := filepath.Join(t.TempDir(), "worktree")
+	if err := CreateWorktree(repoPath, worktreePath, "test/locked", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	if IsWorktreeLocked(repoPath, worktreePath) {
+		t.Error("Expected new worktree to be unlocked")
+	}
+
+	runGit(t, repoPath, "worktree", "lock", "--reason", "keep me", worktreePath)
+	if !IsWorktreeLocked(repoPath, worktreePath) {
+		t.Error("Expected worktree to be locked")
+	}
+	if IsWorktreeLocked(repoPath, repoPath) {
+		t.Error("Expected main worktree to be unlocked")
+	}
+
+	if err := UnlockWorktree(repoPath, worktreePath); err != nil {
+		t.Fatalf("UnlockWorktree() error = %v", err)
+	}
+	if IsWorktreeLocked(repoPath, worktreePath) {
+		t.Error("Expected worktree to be unlocked")
+	}
+}
+
 func TestGetCurrentBranch(t *testing.T) {
 	repoPath := createTestRepo(t)
 
@@ -161,6 +303,93 @@ func TestGetCurrentBranch(t *testing.T) {
 	}
 }
 
+func TestCurrentCommitHash(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	hash, err := CurrentCommitHash(repoPath)
+	if err != nil {
+		t.Fatalf("CurrentCommitHash() error = %v", err)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = repoPath
+	expected, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to run git rev-parse: %v", err)
+	}
+	if hash != strings.TrimSpace(string(expected)) {
+		t.Errorf("CurrentCommitHash() = %q, want %q", hash, strings.TrimSpace(string(expected)))
+	}
+
+	// An unborn branch has no commit yet
+	emptyPath := t.TempDir()
+	cmd = exec.Command("git", "init")
+	cmd.Dir = emptyPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	if hash, err := CurrentCommitHash(emptyPath); err == nil {
+		t.Errorf("Expected error for unborn branch, got %q", hash)
+	}
+}
+
+func TestLastCommitTime(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "dated")
+	cmd.Dir = repoPath
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2024-03-01T09:30:00Z")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to commit: %v\n%s", err, output)
+	}
+
+	got, err := LastCommitTime(repoPath)
+	if err != nil {
+		t.Fatalf("LastCommitTime() error = %v", err)
+	}
+	expected := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	if !got.Equal(expected) {
+		t.Errorf("LastCommitTime() = %v, want %v", got, expected)
+	}
+
+	// An unborn branch has no commits
+	emptyPath := t.TempDir()
+	cmd = exec.Command("git", "init")
+	cmd.Dir = emptyPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	if _, err := LastCommitTime(emptyPath); err == nil {
+		t.Error("Expected error for unborn branch, got nil")
+	}
+}
+
+func TestAddExclude(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	for i := 0; i < 2; i++ {
+		if err := AddExclude(repoPath, "/.rig-meta"); err != nil {
+			t.Fatalf("AddExclude() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoPath, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatalf("Failed to read exclude file: %v", err)
+	}
+	if n := strings.Count(string(content), "/.rig-meta\n"); n != 1 {
+		t.Errorf("Expected pattern once in exclude file, found %d times:\n%s", n, content)
+	}
+
+	os.WriteFile(filepath.Join(repoPath, ".rig-meta"), []byte("{}"), 0644)
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = repoPath
+	output, _ := cmd.Output()
+	if strings.Contains(string(output), ".rig-meta") {
+		t.Errorf("Expected .rig-meta to be ignored, got:\n%s", output)
+	}
+}
+
 func TestCheckoutBranch(t *testing.T) {
 	repoPath := createTestRepo(t)
 
@@ -220,6 +449,47 @@ func TestIsGitRepo(t *testing.T) {
 	if IsGitRepo(nonRepo) {
 		t.Error("Expected directory to not be a git repo")
 	}
+
+	subDir := filepath.Join(repoPath, "subdir")
+	os.Mkdir(subDir, 0755)
+	if IsGitRepo(subDir) {
+		t.Error("Expected subdirectory of a repo to not be a git repo")
+	}
+
+	t.Run("worktree", func(t *testing.T) {
+		worktreePath := filepath.Join(t.TempDir(), "wt")
+		if err := CreateWorktree(repoPath, worktreePath, "test/wt", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+		if !IsGitRepo(worktreePath) {
+			t.Error("Expected worktree to be a git repo")
+		}
+	})
+
+	t.Run("submodule", func(t *testing.T) {
+		superPath := createTestRepo(t)
+		runGit(t, superPath, "-c", "protocol.file.allow=always", "submodule", "add", repoPath, "sub")
+		if !IsGitRepo(filepath.Join(superPath, "sub")) {
+			t.Error("Expected submodule to be a git repo")
+		}
+	})
+
+	t.Run("bare repo", func(t *testing.T) {
+		barePath := filepath.Join(t.TempDir(), "bare.git")
+		cmd := exec.Command("git", "init", "--bare", barePath)
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to init bare repo: %v", err)
+		}
+		if !IsGitRepo(barePath) {
+			t.Error("Expected bare repo to be a git repo")
+		}
+	})
+
+	t.Run("inside .git dir", func(t *testing.T) {
+		if IsGitRepo(filepath.Join(repoPath, ".git")) {
+			t.Error("Expected .git dir to not be a git repo")
+		}
+	})
 }
 
 func TestDeleteBranch(t *testing.T) {
@@ -341,3 +611,817 @@ func TestGetWorktreeForBranch(t *testing.T) {
 		t.Error("Expected error for non-existent branch")
 	}
 }
+
+func TestOpenRepo(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	subDir := filepath.Join(repoPath, "subdir")
+	os.Mkdir(subDir, 0755)
+
+	repo, err := OpenRepo(subDir)
+	if err != nil {
+		t.Fatalf("Failed to open repo: %v", err)
+	}
+
+	expectedRoot, _ := filepath.EvalSymlinks(repoPath)
+	actualRoot, _ := filepath.EvalSymlinks(repo.Root)
+	if actualRoot != expectedRoot {
+		t.Errorf("Expected repo root %s, got %s", expectedRoot, actualRoot)
+	}
+
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatalf("Failed to get current branch: %v", err)
+	}
+	if branch != "main" {
+		t.Errorf("Expected main, got %s", branch)
+	}
+
+	if !repo.BranchExists("main") {
+		t.Error("Expected main branch to exist")
+	}
+
+	if _, err := OpenRepo(t.TempDir()); err == nil {
+		t.Error("Expected error opening a non-repo directory")
+	}
+}
+
+// The two benchmarks below perform the same three lookups from a
+// subdirectory. The first re-derives the root for each operation (one extra
+// git process per lookup); the second resolves it once via OpenRepo.
+
+func BenchmarkLookupsResolvingRootEachTime(b *testing.B) {
+	repoPath := createBenchRepo(b)
+	subDir := filepath.Join(repoPath, "subdir")
+
+	for i := 0; i < b.N; i++ {
+		root, _ := GetRepoRoot(subDir)
+		GetCurrentBranch(root)
+		root, _ = GetRepoRoot(subDir)
+		BranchExists(root, "main")
+		root, _ = GetRepoRoot(subDir)
+		ListWorktrees(root)
+	}
+}
+
+func BenchmarkLookupsWithRepo(b *testing.B) {
+	repoPath := createBenchRepo(b)
+	subDir := filepath.Join(repoPath, "subdir")
+
+	for i := 0; i < b.N; i++ {
+		repo, _ := OpenRepo(subDir)
+		repo.CurrentBranch()
+		repo.BranchExists("main")
+		repo.ListWorktrees()
+	}
+}
+
+func createBenchRepo(b *testing.B) string {
+	b.Helper()
+
+	repoPath := filepath.Join(b.TempDir(), "benchrepo")
+	os.MkdirAll(filepath.Join(repoPath, "subdir"), 0755)
+
+	for _, args := range [][]string{
+		{"init"},
+		{"checkout", "-b", "main"},
+		{"commit", "--allow-empty", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			b.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	return repoPath
+}
+
+func TestRecentCommits(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	for _, msg := range []string{"Second commit", "Third commit", "Fourth commit"} {
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", msg)
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	t.Run("returns newest first", func(t *testing.T) {
+		commits, err := RecentCommits(repoPath, 20)
+		if err != nil {
+			t.Fatalf("RecentCommits() error = %v", err)
+		}
+		if len(commits) != 4 {
+			t.Fatalf("Expected 4 commits, got %d: %v", len(commits), commits)
+		}
+		if !strings.HasSuffix(commits[0], "Fourth commit") {
+			t.Errorf("Expected newest commit first, got %q", commits[0])
+		}
+		if !strings.HasSuffix(commits[3], "Initial commit") {
+			t.Errorf("Expected initial commit last, got %q", commits[3])
+		}
+	})
+
+	t.Run("limits count", func(t *testing.T) {
+		commits, err := RecentCommits(repoPath, 2)
+		if err != nil {
+			t.Fatalf("RecentCommits() error = %v", err)
+		}
+		if len(commits) != 2 {
+			t.Errorf("Expected 2 commits, got %d", len(commits))
+		}
+	})
+
+	t.Run("since excludes older commits", func(t *testing.T) {
+		commits, err := RecentCommitsSince(repoPath, 20, "2099-01-01")
+		if err != nil {
+			t.Fatalf("RecentCommitsSince() error = %v", err)
+		}
+		if len(commits) != 0 {
+			t.Errorf("Expected no commits, got %v", commits)
+		}
+	})
+
+	t.Run("errors outside a repo", func(t *testing.T) {
+		if _, err := RecentCommits(t.TempDir(), 20); err == nil {
+			t.Error("Expected error outside a git repo")
+		}
+	})
+}
+
+func TestDiffStat(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	cmd := exec.Command("git", "checkout", "-b", "tracy/work")
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to create branch: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(repoPath, "feature.txt"), []byte("one\ntwo\nthree\n"), 0644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Add feature"}, {"checkout", "main"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	stat, err := DiffStat(repoPath, "main", "tracy/work")
+	if err != nil {
+		t.Fatalf("DiffStat() error = %v", err)
+	}
+	if !strings.Contains(stat, "feature.txt") {
+		t.Errorf("Expected stat to mention feature.txt, got:\n%s", stat)
+	}
+	if !strings.Contains(stat, "1 file changed, 3 insertions(+)") {
+		t.Errorf("Expected stat summary for 3 insertions, got:\n%s", stat)
+	}
+
+	patch, err := Diff(repoPath, "main", "tracy/work")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !strings.Contains(patch, "+two") {
+		t.Errorf("Expected full patch content, got:\n%s", patch)
+	}
+
+	stat, err = DiffStat(repoPath, "main", "main")
+	if err != nil {
+		t.Fatalf("DiffStat() error = %v", err)
+	}
+	if stat != "" {
+		t.Errorf("Expected empty stat for identical branches, got:\n%s", stat)
+	}
+
+	if _, err := DiffStat(repoPath, "main", "nonexistent"); err == nil {
+		t.Error("Expected error for nonexistent branch")
+	}
+}
+
+func TestFormatPatch(t *testing.T) {
+	repoPath := createTestRepo(t)
+	runGit(t, repoPath, "checkout", "-b", "tracy/work")
+	commitFile(t, repoPath, "one.txt", "one\n", "Add one")
+	commitFile(t, repoPath, "two.txt", "two\n", "Add two")
+
+	outDir := filepath.Join(t.TempDir(), "patches")
+	patches, err := FormatPatch(repoPath, "main", "tracy/work", outDir)
+	if err != nil {
+		t.Fatalf("FormatPatch() error = %v", err)
+	}
+	if len(patches) != 2 {
+		t.Fatalf("Expected 2 patches, got %v", patches)
+	}
+	for i, subject := range []string{"Add one", "Add two"} {
+		if filepath.Dir(patches[i]) != outDir {
+			t.Errorf("Expected patch %d in %s, got %s", i, outDir, patches[i])
+		}
+		content, err := os.ReadFile(patches[i])
+		if err != nil {
+			t.Fatalf("Failed to read patch: %v", err)
+		}
+		if !strings.Contains(string(content), "/2] "+subject) {
+			t.Errorf("Expected patch %d to be %q, got:\n%s", i, subject, content)
+		}
+	}
+
+	patches, err = FormatPatch(repoPath, "main", "main", outDir)
+	if err != nil {
+		t.Fatalf("FormatPatch() for empty range error = %v", err)
+	}
+	if len(patches) != 0 {
+		t.Errorf("Expected no patches for an empty range, got %v", patches)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	repoPath := createTestRepo(t)
+	runGit(t, repoPath, "checkout", "-b", "tracy/work")
+	commitFile(t, repoPath, "feature.txt", "tracy\n", "Add feature")
+	patches, err := FormatPatch(repoPath, "main", "tracy/work", t.TempDir())
+	if err != nil || len(patches) != 1 {
+		t.Fatalf("FormatPatch() = %v, %v", patches, err)
+	}
+	runGit(t, repoPath, "checkout", "main")
+
+	t.Run("clean", func(t *testing.T) {
+		worktreePath := filepath.Join(t.TempDir(), "clean")
+		if err := CreateWorktree(repoPath, worktreePath, "alex/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+		if err := ApplyPatch(worktreePath, patches[0]); err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		commits, err := RecentCommits(worktreePath, 1)
+		if err != nil || len(commits) != 1 || !strings.Contains(commits[0], "Add feature") {
+			t.Errorf("Expected the patch to be committed, got %v (%v)", commits, err)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		worktreePath := filepath.Join(t.TempDir(), "conflict")
+		if err := CreateWorktree(repoPath, worktreePath, "blake/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+		commitFile(t, worktreePath, "feature.txt", "blake\n", "Add other feature")
+
+		err := ApplyPatch(worktreePath, patches[0])
+		var conflictErr *PatchConflictError
+		if !errors.As(err, &conflictErr) {
+			t.Fatalf("Expected *PatchConflictError, got %v", err)
+		}
+		if !reflect.DeepEqual(conflictErr.Files, []string{"feature.txt"}) {
+			t.Errorf("Expected conflict in feature.txt, got %v", conflictErr.Files)
+		}
+		content, _ := os.ReadFile(filepath.Join(worktreePath, "feature.txt"))
+		if string(content) != "blake\n" {
+			t.Errorf("Expected worktree to be restored, got %q", content)
+		}
+	})
+
+	t.Run("not a patch", func(t *testing.T) {
+		junk := filepath.Join(t.TempDir(), "junk.patch")
+		os.WriteFile(junk, []byte("junk\n"), 0644)
+
+		err := ApplyPatch(repoPath, junk)
+		var conflictErr *PatchConflictError
+		if err == nil || errors.As(err, &conflictErr) {
+			t.Errorf("Expected a plain error for an unparseable patch, got %v", err)
+		}
+	})
+}
+
+// commitFile writes content to name on the current branch and commits it
+func commitFile(t *testing.T, repoPath, name, content, message string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	for _, args := range [][]string{{"add", name}, {"commit", "-m", message}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+func runGit(t *testing.T, repoPath string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("clean merge", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+
+		runGit(t, repoPath, "checkout", "-b", "tracy/work")
+		commitFile(t, repoPath, "feature.txt", "feature", "Add feature")
+		runGit(t, repoPath, "checkout", "main")
+
+		result, err := Merge(repoPath, "tracy/work")
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if result.Status != MergeClean {
+			t.Errorf("Expected MergeClean, got %v", result.Status)
+		}
+
+		if _, err := os.Stat(filepath.Join(repoPath, "feature.txt")); err != nil {
+			t.Error("Expected merged file to exist on main")
+		}
+	})
+
+	t.Run("already up to date", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+
+		runGit(t, repoPath, "branch", "tracy/work")
+
+		result, err := Merge(repoPath, "tracy/work")
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if result.Status != MergeAlreadyUpToDate {
+			t.Errorf("Expected MergeAlreadyUpToDate, got %v", result.Status)
+		}
+	})
+
+	t.Run("conflicting merge is aborted", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+
+		runGit(t, repoPath, "checkout", "-b", "tracy/work")
+		commitFile(t, repoPath, "test.txt", "tracy's version", "Change on branch")
+		commitFile(t, repoPath, "other.txt", "tracy's other", "Add other on branch")
+		runGit(t, repoPath, "checkout", "main")
+		commitFile(t, repoPath, "test.txt", "main's version", "Change on main")
+		commitFile(t, repoPath, "other.txt", "main's other", "Add other on main")
+
+		result, err := Merge(repoPath, "tracy/work")
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if result.Status != MergeConflicted {
+			t.Fatalf("Expected MergeConflicted, got %v", result.Status)
+		}
+
+		expected := []string{"other.txt", "test.txt"}
+		if !reflect.DeepEqual(result.Files, expected) {
+			t.Errorf("Expected conflicts in %v, got %v", expected, result.Files)
+		}
+
+		// Repo should be back to main's version with no merge in progress
+		content, _ := os.ReadFile(filepath.Join(repoPath, "test.txt"))
+		if string(content) != "main's version" {
+			t.Errorf("Expected main's version after abort, got %q", content)
+		}
+		if _, err := os.Stat(filepath.Join(repoPath, ".git", "MERGE_HEAD")); err == nil {
+			t.Error("Expected no merge in progress after abort")
+		}
+	})
+
+	t.Run("nonexistent branch", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+
+		if _, err := Merge(repoPath, "nonexistent"); err == nil {
+			t.Error("Expected error for nonexistent branch")
+		}
+	})
+}
+
+func TestRebaseOnto(t *testing.T) {
+	t.Run("fast-forward", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+		worktreePath := filepath.Join(t.TempDir(), "wt")
+		if err := CreateWorktree(repoPath, worktreePath, "tracy/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+
+		commitFile(t, repoPath, "base.txt", "new on main", "Advance main")
+
+		if err := RebaseOnto(worktreePath, "main"); err != nil {
+			t.Fatalf("RebaseOnto() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath, "base.txt")); err != nil {
+			t.Error("Expected base change to be present after rebase")
+		}
+	})
+
+	t.Run("replays branch commits on top of base", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+		worktreePath := filepath.Join(t.TempDir(), "wt")
+		if err := CreateWorktree(repoPath, worktreePath, "tracy/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+
+		commitFile(t, worktreePath, "feature.txt", "feature", "Add feature")
+		commitFile(t, repoPath, "base.txt", "new on main", "Advance main")
+
+		if err := RebaseOnto(worktreePath, "main"); err != nil {
+			t.Fatalf("RebaseOnto() error = %v", err)
+		}
+		for _, name := range []string{"base.txt", "feature.txt"} {
+			if _, err := os.Stat(filepath.Join(worktreePath, name)); err != nil {
+				t.Errorf("Expected %s after rebase", name)
+			}
+		}
+	})
+
+	t.Run("conflict is aborted", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+		worktreePath := filepath.Join(t.TempDir(), "wt")
+		if err := CreateWorktree(repoPath, worktreePath, "tracy/work", "main"); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+
+		commitFile(t, worktreePath, "test.txt", "tracy's version", "Change on branch")
+		commitFile(t, repoPath, "test.txt", "main's version", "Change on main")
+
+		err := RebaseOnto(worktreePath, "main")
+		var conflictErr *RebaseConflictError
+		if !errors.As(err, &conflictErr) {
+			t.Fatalf("Expected *RebaseConflictError, got %T: %v", err, err)
+		}
+		if !reflect.DeepEqual(conflictErr.Files, []string{"test.txt"}) {
+			t.Errorf("Expected conflict in test.txt, got %v", conflictErr.Files)
+		}
+
+		content, _ := os.ReadFile(filepath.Join(worktreePath, "test.txt"))
+		if string(content) != "tracy's version" {
+			t.Errorf("Expected branch to be unchanged after abort, got %q", content)
+		}
+		branch, _ := GetCurrentBranch(worktreePath)
+		if branch != "tracy/work" {
+			t.Errorf("Expected to be back on tracy/work, got %q", branch)
+		}
+	})
+}
+
+// createBareRemote creates a bare repo and adds it as origin of repoPath
+func createBareRemote(t *testing.T, repoPath string) string {
+	t.Helper()
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	cmd := exec.Command("git", "init", "--bare", remotePath)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to init bare repo: %v", err)
+	}
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+	return remotePath
+}
+
+func TestHasRemote(t *testing.T) {
+	t.Run("remoteless repo", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+		if HasRemote(repoPath) {
+			t.Error("Expected no remote")
+		}
+
+		// Remote-dependent lookups still work locally
+		branch, err := GetBaseBranch(repoPath, "main")
+		if err != nil || branch != "main" {
+			t.Errorf("GetBaseBranch() = %q, %v; want main", branch, err)
+		}
+	})
+
+	t.Run("repo with remote", func(t *testing.T) {
+		repoPath := createTestRepo(t)
+		createBareRemote(t, repoPath)
+		if !HasRemote(repoPath) {
+			t.Error("Expected remote to be detected")
+		}
+	})
+
+	t.Run("not a repo", func(t *testing.T) {
+		if HasRemote(t.TempDir()) {
+			t.Error("Expected no remote outside a repo")
+		}
+	})
+}
+
+func TestRemoteBranchExists(t *testing.T) {
+	repoPath := createTestRepo(t)
+	createBareRemote(t, repoPath)
+
+	// Push a branch, then drop the local copy
+	runGit(t, repoPath, "branch", "tracy/work")
+	runGit(t, repoPath, "push", "origin", "tracy/work")
+	runGit(t, repoPath, "branch", "-D", "tracy/work")
+
+	if !RemoteBranchExists(repoPath, "origin/tracy/work") {
+		t.Error("Expected tracy/work to exist on origin")
+	}
+	if RemoteBranchExists(repoPath, "origin/alex/work") {
+		t.Error("Expected alex/work not to exist on origin")
+	}
+	if RemoteBranchExists(repoPath, "origin/main") {
+		t.Error("Expected unpushed main not to exist on origin")
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "tracy")
+	if err := CreateWorktreeTracking(repoPath, worktreePath, "tracy/work"); err != nil {
+		t.Fatalf("CreateWorktreeTracking() error = %v", err)
+	}
+	if upstream := revParseAbbrev(t, worktreePath, "@{upstream}"); upstream != "origin/tracy/work" {
+		t.Errorf("Expected upstream origin/tracy/work, got %s", upstream)
+	}
+}
+
+func TestGetUpstream(t *testing.T) {
+	repoPath := createTestRepo(t)
+	createBareRemote(t, repoPath)
+	runGit(t, repoPath, "push", "-u", "origin", "main")
+	runGit(t, repoPath, "branch", "local-only")
+
+	tests := []struct {
+		name    string
+		branch  string
+		want    string
+		wantErr bool
+	}{
+		{name: "tracking branch", branch: "main", want: "origin/main"},
+		{name: "no upstream", branch: "local-only", want: ""},
+		{name: "missing branch", branch: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetUpstream(repoPath, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetUpstream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetUpstream() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetUpstream(t *testing.T) {
+	repoPath := createTestRepo(t)
+	createBareRemote(t, repoPath)
+	runGit(t, repoPath, "push", "origin", "main")
+
+	if err := SetUpstream(repoPath, "main", "origin/main"); err != nil {
+		t.Fatalf("SetUpstream() error = %v", err)
+	}
+	if upstream, _ := GetUpstream(repoPath, "main"); upstream != "origin/main" {
+		t.Errorf("Expected upstream origin/main, got %q", upstream)
+	}
+
+	if err := SetUpstream(repoPath, "main", "origin/missing"); err == nil {
+		t.Error("Expected error for a missing remote branch")
+	}
+
+	runGit(t, repoPath, "branch", "feature")
+	if err := PushUpstream(repoPath, "feature"); err != nil {
+		t.Fatalf("PushUpstream() error = %v", err)
+	}
+	if upstream, _ := GetUpstream(repoPath, "feature"); upstream != "origin/feature" {
+		t.Errorf("Expected upstream origin/feature, got %q", upstream)
+	}
+}
+
+func TestAddPathsCommit(t *testing.T) {
+	repoPath := createTestRepo(t)
+	before := revParse(t, repoPath, "HEAD")
+
+	os.MkdirAll(filepath.Join(repoPath, "work", "add-auth"), 0755)
+	os.WriteFile(filepath.Join(repoPath, "work", "add-auth", "spec.md"), []byte("# Spec\n"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "other.txt"), []byte("other\n"), 0644)
+
+	if err := AddPaths(repoPath, "work/add-auth/"); err != nil {
+		t.Fatalf("AddPaths() error = %v", err)
+	}
+	if err := Commit(repoPath, "Add spec"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if revParse(t, repoPath, "HEAD^") != before {
+		t.Error("Expected a new commit on top of HEAD")
+	}
+	cmd := exec.Command("git", "show", "--name-only", "--format=%s", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git show failed: %v", err)
+	}
+	if got := strings.Fields(string(output)); !reflect.DeepEqual(got, []string{"Add", "spec", "work/add-auth/spec.md"}) {
+		t.Errorf("Expected only the staged path committed as \"Add spec\", got %q", output)
+	}
+
+	// Nothing staged is an error
+	if err := Commit(repoPath, "Empty"); err == nil {
+		t.Error("Expected error committing with nothing staged")
+	}
+	if err := AddPaths(repoPath, "missing"); err == nil {
+		t.Error("Expected error staging a missing path")
+	}
+}
+
+func TestParsePorcelain(t *testing.T) {
+	output := " M work/add-auth/spec.md\x00R  work/add-auth/notes.md\x00work/add-auth/old notes.md\x00A  work/add-auth/hook.md\x00?? work/add-auth/scratch.txt\x00"
+
+	want := []StatusEntry{
+		{Code: " M", Path: "work/add-auth/spec.md"},
+		{Code: "R ", Path: "work/add-auth/notes.md", OrigPath: "work/add-auth/old notes.md"},
+		{Code: "A ", Path: "work/add-auth/hook.md"},
+		{Code: "??", Path: "work/add-auth/scratch.txt"},
+	}
+	if got := parsePorcelain(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePorcelain() = %+v, want %+v", got, want)
+	}
+	if got := parsePorcelain(""); got != nil {
+		t.Errorf("parsePorcelain(\"\") = %+v, want nil", got)
+	}
+}
+
+func TestStatusPorcelain(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a\n"), 0644)
+	runGit(t, repoPath, "add", "a.txt")
+	runGit(t, repoPath, "commit", "-m", "add a")
+
+	runGit(t, repoPath, "mv", "a.txt", "b.txt")
+	os.MkdirAll(filepath.Join(repoPath, "work"), 0755)
+	os.WriteFile(filepath.Join(repoPath, "work", "new.txt"), []byte("new\n"), 0644)
+
+	entries, err := StatusPorcelain(repoPath, "")
+	if err != nil {
+		t.Fatalf("StatusPorcelain() error = %v", err)
+	}
+	want := []StatusEntry{
+		{Code: "R ", Path: "b.txt", OrigPath: "a.txt"},
+		{Code: "??", Path: "work/"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("StatusPorcelain() = %+v, want %+v", entries, want)
+	}
+
+	// A pathspec limits the entries
+	entries, err = StatusPorcelain(repoPath, "work/")
+	if err != nil {
+		t.Fatalf("StatusPorcelain(work/) error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "work/" {
+		t.Errorf("StatusPorcelain(work/) = %+v, want only work/", entries)
+	}
+}
+
+func TestCommitsAhead(t *testing.T) {
+	repoPath := createTestRepo(t)
+	runGit(t, repoPath, "checkout", "-b", "alex/work")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "one")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "two")
+
+	if ahead, err := CommitsAhead(repoPath, "main", "alex/work"); err != nil || ahead != 2 {
+		t.Errorf("CommitsAhead(main, alex/work) = %d, %v, want 2", ahead, err)
+	}
+	if ahead, err := CommitsAhead(repoPath, "alex/work", "main"); err != nil || ahead != 0 {
+		t.Errorf("CommitsAhead(alex/work, main) = %d, %v, want 0", ahead, err)
+	}
+	if _, err := CommitsAhead(repoPath, "main", "missing"); err == nil {
+		t.Error("Expected error for a missing branch")
+	}
+}
+
+func TestBranchConfig(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	if value := GetBranchConfig(repoPath, "main", "rigWork"); value != "" {
+		t.Errorf("Expected no config, got %q", value)
+	}
+	if err := SetBranchConfig(repoPath, "main", "rigWork", "add-auth"); err != nil {
+		t.Fatalf("SetBranchConfig() error = %v", err)
+	}
+	if value := GetBranchConfig(repoPath, "main", "rigWork"); value != "add-auth" {
+		t.Errorf("Expected add-auth, got %q", value)
+	}
+
+	runGit(t, repoPath, "branch", "release/v1.2")
+	if err := SetBranchConfig(repoPath, "release/v1.2", "rigWork", "add-auth"); err != nil {
+		t.Fatalf("SetBranchConfig() error = %v", err)
+	}
+	if err := SetBranchConfig(repoPath, "release/v1.2", "rigOther", "add-auth"); err != nil {
+		t.Fatalf("SetBranchConfig() error = %v", err)
+	}
+	if got := BranchesWithConfig(repoPath, "rigWork", "add-auth"); !reflect.DeepEqual(got, []string{"main", "release/v1.2"}) {
+		t.Errorf("BranchesWithConfig() = %v, want [main release/v1.2]", got)
+	}
+	if got := BranchesWithConfig(repoPath, "rigWork", "search"); got != nil {
+		t.Errorf("BranchesWithConfig(search) = %v, want none", got)
+	}
+
+	if err := UnsetBranchConfig(repoPath, "main", "rigWork"); err != nil {
+		t.Fatalf("UnsetBranchConfig() error = %v", err)
+	}
+	if value := GetBranchConfig(repoPath, "main", "rigWork"); value != "" {
+		t.Errorf("Expected config to be unset, got %q", value)
+	}
+	if err := UnsetBranchConfig(repoPath, "main", "rigWork"); err != nil {
+		t.Errorf("UnsetBranchConfig() when unset error = %v", err)
+	}
+}
+
+// revParseAbbrev returns the short ref name that rev resolves to
+func revParseAbbrev(t *testing.T, repoPath, rev string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", rev)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse --abbrev-ref %s failed: %v", rev, err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestGetMainRepoRoot(t *testing.T) {
+	repoPath := createTestRepo(t)
+	expectedRoot, _ := filepath.EvalSymlinks(repoPath)
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	if err := CreateWorktree(repoPath, worktreePath, "test/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	subDir := filepath.Join(worktreePath, "subdir")
+	os.Mkdir(subDir, 0755)
+
+	for _, path := range []string{repoPath, worktreePath, subDir} {
+		root, err := GetMainRepoRoot(path)
+		if err != nil {
+			t.Fatalf("GetMainRepoRoot(%s) error = %v", path, err)
+		}
+		actualRoot, _ := filepath.EvalSymlinks(root)
+		if actualRoot != expectedRoot {
+			t.Errorf("GetMainRepoRoot(%s) = %s, want %s", path, actualRoot, expectedRoot)
+		}
+	}
+
+	if _, err := GetMainRepoRoot(t.TempDir()); err == nil {
+		t.Error("Expected error outside a repo")
+	}
+}
+
+func TestWorktreeStatus(t *testing.T) {
+	repoPath := createTestRepo(t)
+
+	cleanPath := filepath.Join(t.TempDir(), "clean")
+	if err := CreateWorktree(repoPath, cleanPath, "clean/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	dirtyPath := filepath.Join(t.TempDir(), "dirty")
+	if err := CreateWorktree(repoPath, dirtyPath, "dirty/work", "main"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+	os.WriteFile(filepath.Join(dirtyPath, "scratch.txt"), []byte("wip"), 0644)
+
+	detachedPath := filepath.Join(t.TempDir(), "detached")
+	runGit(t, repoPath, "worktree", "add", "--detach", detachedPath, "main")
+
+	infos, err := WorktreeStatus(repoPath)
+	if err != nil {
+		t.Fatalf("WorktreeStatus() error = %v", err)
+	}
+
+	byPath := make(map[string]WorktreeInfo)
+	for _, info := range infos {
+		resolved, _ := filepath.EvalSymlinks(info.Path)
+		byPath[resolved] = info
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected WorktreeInfo
+	}{
+		{"main", repoPath, WorktreeInfo{Branch: "main"}},
+		{"clean", cleanPath, WorktreeInfo{Branch: "clean/work"}},
+		{"dirty", dirtyPath, WorktreeInfo{Branch: "dirty/work", Dirty: true}},
+		{"detached", detachedPath, WorktreeInfo{Detached: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, _ := filepath.EvalSymlinks(tt.path)
+			info, ok := byPath[resolved]
+			if !ok {
+				t.Fatalf("Worktree %s not reported", tt.path)
+			}
+			info.Path = ""
+			if info != tt.expected {
+				t.Errorf("WorktreeStatus() for %s = %+v, want %+v", tt.name, info, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package picker
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// ErrCancelled is returned when the user dismisses the picker without choosing
+var ErrCancelled = errors.New("selection cancelled")
+
+// Picker chooses one item from a list
+type Picker interface {
+	Pick(label string, items []string) (string, error)
+}
+
+// Prompt is an interactive terminal picker: arrow keys move, / filters
+type Prompt struct{}
+
+// Pick shows the items and returns the one selected
+func (Prompt) Pick(label string, items []string) (string, error) {
+	if len(items) == 0 {
+		return "", errors.New("nothing to select")
+	}
+
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+		Size:  10,
+		Searcher: func(input string, index int) bool {
+			return Matches(items[index], input)
+		},
+	}
+
+	_, choice, err := prompt.Run()
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) || errors.Is(err, promptui.ErrAbort) {
+		return "", ErrCancelled
+	}
+	return choice, err
+}
+
+// Matches reports whether the characters of filter appear in item in order,
+// ignoring case and spaces, so "ntr" matches "notes@tracy"
+func Matches(item, filter string) bool {
+	item = strings.ToLower(item)
+	filter = strings.ToLower(strings.ReplaceAll(filter, " ", ""))
+
+	for _, c := range filter {
+		i := strings.IndexRune(item, c)
+		if i < 0 {
+			return false
+		}
+		item = item[i+len(string(c)):]
+	}
+	return true
+}
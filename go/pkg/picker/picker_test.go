@@ -0,0 +1,34 @@
+package picker
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		item     string
+		filter   string
+		expected bool
+	}{
+		{"notes@tracy", "", true},
+		{"notes@tracy", "notes", true},
+		{"notes@tracy", "ntr", true},
+		{"notes@tracy", "NOTES", true},
+		{"notes@tracy", "tracy notes", false},
+		{"notes@tracy", "n t", true},
+		{"myapp", "xyz", false},
+		{"myapp", "ppa", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item+"/"+tt.filter, func(t *testing.T) {
+			if result := Matches(tt.item, tt.filter); result != tt.expected {
+				t.Errorf("Matches(%q, %q) = %v, want %v", tt.item, tt.filter, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPromptEmpty(t *testing.T) {
+	if _, err := (Prompt{}).Pick("Session", nil); err == nil {
+		t.Error("Expected error when there is nothing to select")
+	}
+}
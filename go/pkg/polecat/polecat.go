@@ -3,12 +3,8 @@ package polecat
 import (
 	"fmt"
 	"math/rand"
-	"os"
-	"path/filepath"
 	"strings"
-	"time"
-
-	"github.com/mstrand/rig/pkg/config"
+	"sync"
 )
 
 var names = []string{
@@ -18,11 +14,10 @@ var names = []string{
 	"isabella", "aria", "aurora", "violet", "nova", "hazel",
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
-// GenerateName generates a random polecat name not in the used list
+// GenerateName generates a random polecat name not in the used list. It
+// depends only on its arguments and math/rand's goroutine-safe global source,
+// so concurrent calls are safe; callers sharing a used list across goroutines
+// should use a Generator instead so two calls can't pick the same name.
 func GenerateName(used []string) string {
 	usedMap := make(map[string]bool)
 	for _, name := range used {
@@ -51,48 +46,34 @@ func GenerateName(used []string) string {
 	return fmt.Sprintf("polecat_%s", available[rand.Intn(len(available))])
 }
 
-// IsPolecat checks if a name follows polecat naming convention
-func IsPolecat(name string) bool {
-	return strings.HasPrefix(name, "polecat_")
+// Generator hands out unique polecat names and is safe for concurrent use
+type Generator struct {
+	mu   sync.Mutex
+	used []string
 }
 
-// List returns all polecat names from all rigs
-func List(cfg *config.Config) ([]string, error) {
-	polecats := []string{}
-
-	// Check if crew base exists
-	if _, err := os.Stat(cfg.CrewBase); os.IsNotExist(err) {
-		return polecats, nil
-	}
-
-	// Scan all rigs
-	rigDirs, err := os.ReadDir(cfg.CrewBase)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read crew directory: %w", err)
-	}
-
-	for _, rigDir := range rigDirs {
-		if !rigDir.IsDir() {
-			continue
-		}
-
-		rigPath := filepath.Join(cfg.CrewBase, rigDir.Name())
-		crewDirs, err := os.ReadDir(rigPath)
-		if err != nil {
-			continue
-		}
+// NewGenerator returns a Generator that avoids the given names
+func NewGenerator(used []string) *Generator {
+	return &Generator{used: append([]string{}, used...)}
+}
 
-		for _, crewDir := range crewDirs {
-			if !crewDir.IsDir() {
-				continue
-			}
+// Next reserves and returns an unused polecat name, or an error once every
+// name is taken
+func (g *Generator) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-			name := crewDir.Name()
-			if IsPolecat(name) {
-				polecats = append(polecats, name)
-			}
+	name := GenerateName(g.used)
+	for _, existing := range g.used {
+		if existing == name {
+			return "", fmt.Errorf("no unused polecat names left")
 		}
 	}
+	g.used = append(g.used, name)
+	return name, nil
+}
 
-	return polecats, nil
+// IsPolecat checks if a name follows polecat naming convention
+func IsPolecat(name string) bool {
+	return strings.HasPrefix(name, "polecat_")
 }
@@ -2,6 +2,7 @@ package polecat
 
 import (
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -102,3 +103,44 @@ func TestGenerateNameExhaustion(t *testing.T) {
 		t.Errorf("GenerateName() with all names used should still return valid polecat name, got %q", generated)
 	}
 }
+
+func TestGeneratorConcurrent(t *testing.T) {
+	gen := NewGenerator([]string{"polecat_emma", "tracy"})
+	n := len(names) - 1
+
+	results := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name, err := gen.Next()
+			if err != nil {
+				t.Errorf("Next() error = %v", err)
+				return
+			}
+			results <- name
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]bool)
+	for name := range results {
+		if name == "polecat_emma" {
+			t.Errorf("Next() returned a name that was already used: %s", name)
+		}
+		if seen[name] {
+			t.Errorf("Next() returned duplicate name %s", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != n {
+		t.Errorf("Expected %d unique names, got %d", n, len(seen))
+	}
+
+	// Every name is now taken
+	if name, err := gen.Next(); err == nil {
+		t.Errorf("Expected error once names are exhausted, got %s", name)
+	}
+}
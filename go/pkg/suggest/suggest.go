@@ -0,0 +1,50 @@
+package suggest
+
+// Closest returns the candidate nearest to name by edit distance, or "" if
+// none is close enough to be a likely typo
+func Closest(name string, candidates []string) string {
+	maxDistance := len([]rune(name)) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		if d := Distance(name, candidate); d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// Distance returns the Levenshtein distance between a and b: the number of
+// single-character insertions, deletions or substitutions between them
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev[j] is the distance between the first i-1 runes of a and the
+	// first j runes of b
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,50 @@
+package suggest
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"myapp", "myapp", 0},
+		{"myap", "myapp", 1},
+		{"mypap", "myapp", 2},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"notes@trcy", "notes@tracy", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"myapp", "notes", "notes@tracy", "api"}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"myap", "myapp"},
+		{"ntoes", "notes"},
+		{"notes@trcy", "notes@tracy"},
+		{"apo", "api"},
+		{"backend", ""},
+		{"myapp", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Closest(tt.name, candidates); got != tt.expected {
+			t.Errorf("Closest(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+
+	if got := Closest("myap", nil); got != "" {
+		t.Errorf("Closest() with no candidates = %q, want empty", got)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,14 +21,12 @@ func NormalizeSessionName(name string) string {
 // SessionExists checks if a tmux session exists
 func SessionExists(name string) bool {
 	name = NormalizeSessionName(name)
-	cmd := exec.Command("tmux", "has-session", "-t", name)
-	return cmd.Run() == nil
+	return command("has-session", "-t", name).Run() == nil
 }
 
 // ListSessions returns all active tmux sessions
 func ListSessions() ([]string, error) {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-	output, err := cmd.Output()
+	output, err := command("list-sessions", "-F", "#{session_name}").Output()
 	if err != nil {
 		// No sessions exist
 		return []string{}, nil
@@ -38,37 +39,217 @@ func ListSessions() ([]string, error) {
 	return sessions, nil
 }
 
+// Session describes an active tmux session
+type Session struct {
+	Name     string
+	Created  time.Time
+	Windows  int
+	Attached int // number of clients attached
+}
+
+const sessionFormat = "#{session_name}|#{session_created}|#{session_windows}|#{session_attached}"
+
+// ListSessionsDetailed returns all active tmux sessions with their creation
+// time, window count and attached clients
+func ListSessionsDetailed() ([]Session, error) {
+	output, err := command("list-sessions", "-F", sessionFormat).Output()
+	if err != nil {
+		// No sessions exist
+		return []Session{}, nil
+	}
+
+	sessions := []Session{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		session, err := parseSession(line)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// parseSession parses one line of list-sessions output in sessionFormat
+func parseSession(line string) (Session, error) {
+	// Session names may contain "|", so take the fields from the right
+	parts := strings.Split(line, "|")
+	if len(parts) < 4 {
+		return Session{}, fmt.Errorf("unexpected list-sessions output: %q", line)
+	}
+	n := len(parts)
+	name := strings.Join(parts[:n-3], "|")
+
+	created, err := strconv.ParseInt(parts[n-3], 10, 64)
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid session_created in %q: %w", line, err)
+	}
+	windows, err := strconv.Atoi(parts[n-2])
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid session_windows in %q: %w", line, err)
+	}
+	attached, err := strconv.Atoi(parts[n-1])
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid session_attached in %q: %w", line, err)
+	}
+
+	return Session{
+		Name:     name,
+		Created:  time.Unix(created, 0),
+		Windows:  windows,
+		Attached: attached,
+	}, nil
+}
+
+// SessionSet is a snapshot of active tmux sessions. It allows repeated
+// existence checks without spawning a tmux process for each one.
+type SessionSet map[string]bool
+
+// NewSessionSet builds a SessionSet from a list of session names
+func NewSessionSet(sessions []string) SessionSet {
+	set := make(SessionSet, len(sessions))
+	for _, session := range sessions {
+		set[NormalizeSessionName(session)] = true
+	}
+	return set
+}
+
+// LoadSessionSet lists the active tmux sessions once and returns them as a set
+func LoadSessionSet() (SessionSet, error) {
+	sessions, err := ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	return NewSessionSet(sessions), nil
+}
+
+// Has reports whether a session exists, normalizing the name like SessionExists
+func (s SessionSet) Has(name string) bool {
+	return s[NormalizeSessionName(name)]
+}
+
+// SessionHealthy reports whether a session has at least one live pane. A
+// session whose panes have all exited (e.g. with remain-on-exit) still exists
+// but can't be used.
+func SessionHealthy(name string) bool {
+	output, err := command(sessionHealthArgs(NormalizeSessionName(name))...).Output()
+	if err != nil {
+		return false
+	}
+	return livePanes(string(output)) > 0
+}
+
+func sessionHealthArgs(name string) []string {
+	return []string{"list-panes", "-s", "-t", name, "-F", "#{pane_dead}"}
+}
+
+// livePanes counts the panes in list-panes #{pane_dead} output that are alive
+func livePanes(output string) int {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "0" {
+			count++
+		}
+	}
+	return count
+}
+
 // KillSession kills a tmux session
 func KillSession(name string) error {
 	name = NormalizeSessionName(name)
-	cmd := exec.Command("tmux", "kill-session", "-t", name)
-	return cmd.Run()
+	return command("kill-session", "-t", name).Run()
+}
+
+// RenameSession renames a tmux session. Both names are normalized.
+func RenameSession(oldName, newName string) error {
+	oldName = NormalizeSessionName(oldName)
+	if !SessionExists(oldName) {
+		return fmt.Errorf("session not found: %s", oldName)
+	}
+
+	output, err := run(renameSessionArgs(oldName, newName)...)
+	if err != nil {
+		return fmt.Errorf("failed to rename session %s: %w\n%s", oldName, err, string(output))
+	}
+	return nil
 }
 
-// AttachSession attaches to a tmux session
+func renameSessionArgs(oldName, newName string) []string {
+	return []string{"rename-session", "-t", NormalizeSessionName(oldName), NormalizeSessionName(newName)}
+}
+
+// AttachSession attaches to a tmux session. Inside tmux it switches the
+// current client, falling back to attaching a new client if that fails (e.g.
+// the session is on another server or the client is detaching).
 func AttachSession(name string, useCC bool) error {
 	name = NormalizeSessionName(name)
 	inTmux := os.Getenv("TMUX") != ""
 
-	if inTmux {
-		// Already in tmux, switch client
-		cmd := exec.Command("tmux", "switch-client", "-t", name)
+	var failures []string
+	for _, args := range attachPlan(name, inTmux, useCC) {
+		cmd := command(args...)
+
+		if args[0] == "switch-client" {
+			output, err := cmd.CombinedOutput()
+			if err == nil {
+				return nil
+			}
+			failures = append(failures, fmt.Sprintf("switch-client: %s", commandError(err, output)))
+			continue
+		}
+
+		// Attaching from inside tmux needs $TMUX unset to allow nesting
+		if inTmux {
+			cmd.Env = envWithout(os.Environ(), "TMUX")
+		}
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
-		return cmd.Run()
+		if err := cmd.Run(); err != nil {
+			failures = append(failures, fmt.Sprintf("attach-session: %v", err))
+			return fmt.Errorf("failed to attach to session %s (%s)", name, strings.Join(failures, "; "))
+		}
+		return nil
 	}
 
-	// Not in tmux, attach
-	args := []string{"attach-session", "-t", name}
+	return fmt.Errorf("failed to attach to session %s (%s)", name, strings.Join(failures, "; "))
+}
+
+// attachPlan returns the tmux invocations AttachSession tries, in order.
+// -CC only applies to attaching: switch-client moves the current client, so
+// it keeps whatever mode that client is in.
+func attachPlan(name string, inTmux, useCC bool) [][]string {
+	attach := []string{"attach-session", "-t", name}
 	if useCC {
-		args = append([]string{"-CC"}, args...)
+		attach = append([]string{"-CC"}, attach...)
 	}
-	cmd := exec.Command("tmux", args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+
+	if inTmux {
+		return [][]string{{"switch-client", "-t", name}, attach}
+	}
+	return [][]string{attach}
+}
+
+// commandError describes a failed command by its output, or by the error if
+// it printed nothing
+func commandError(err error, output []byte) string {
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
+
+// envWithout returns env without the given variable
+func envWithout(env []string, key string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, entry := range env {
+		if !strings.HasPrefix(entry, key+"=") {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
 }
 
 // AttachDefault attaches to the default tmux session (most recent or first)
@@ -84,148 +265,215 @@ func AttachDefault(useCC bool) error {
 	if useCC {
 		args = append([]string{"-CC"}, args...)
 	}
-	cmd := exec.Command("tmux", args...)
+	cmd := command(args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// SessionOptions holds optional settings for new rig and crew sessions
+type SessionOptions struct {
+	InitPrompt  string            // sent to Claude Code once it starts
+	SetupScript string            // run in the terminal pane
+	Env         map[string]string // exported in both panes
+	Repos       []string          // extra repos, each opened in its own window (pane in CC mode)
+}
+
 // CreateRigSession creates a tmux session for a rig
-func CreateRigSession(name, repoPath string, useCC bool, initPrompt string) error {
+func CreateRigSession(name, repoPath string, useCC bool, opts SessionOptions) error {
 	name = NormalizeSessionName(name)
 	if useCC {
-		return createRigSessionCC(name, repoPath, initPrompt)
+		return createRigSessionCC(name, repoPath, opts)
 	}
-	return createRigSessionNative(name, repoPath, initPrompt)
+	return createRigSessionNative(name, repoPath, opts)
 }
 
-func createRigSessionNative(name, repoPath string, initPrompt string) error {
+func createRigSessionNative(name, repoPath string, opts SessionOptions) error {
 	// Create session with first window (Claude Code)
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", name, "-n", "Claude Code", "-c", repoPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-session", "-d", "-s", name, "-n", "Claude Code", "-c", repoPath); err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// Start Claude Code in first window
 	sendKeys(name+":1", "cd "+repoPath)
+	for _, keys := range ExportCommands(opts.Env) {
+		sendKeys(name+":1", keys)
+	}
 	time.Sleep(100 * time.Millisecond)
 	sendKeys(name+":1", "claude")
 
 	// Send initial prompt if configured
-	if initPrompt != "" {
+	if opts.InitPrompt != "" {
 		time.Sleep(2 * time.Second) // Wait for Claude Code to start
-		sendKeys(name+":1", initPrompt)
+		sendKeys(name+":1", opts.InitPrompt)
 	}
 
 	// Create second window (Terminal)
-	cmd = exec.Command("tmux", "new-window", "-t", name, "-n", "Terminal", "-c", repoPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-window", "-t", name, "-n", "Terminal", "-c", repoPath); err != nil {
 		return fmt.Errorf("failed to create terminal window: %w", err)
 	}
 
 	// Add helpful header in terminal window
-	sendKeys(name+":2", "cd "+repoPath)
-	sendKeys(name+":2", fmt.Sprintf("echo '# %s terminal'", name))
-	sendKeys(name+":2", "git status")
+	for _, keys := range terminalCommands(repoPath, fmt.Sprintf("# %s terminal", name), opts) {
+		sendKeys(name+":2", keys)
+	}
+
+	if err := openRepoWindows(name, opts, false); err != nil {
+		return err
+	}
 
 	// Select first window
-	cmd = exec.Command("tmux", "select-window", "-t", name+":1")
-	return cmd.Run()
+	_, err := run("select-window", "-t", name+":1")
+	return err
 }
 
-func createRigSessionCC(name, repoPath string, initPrompt string) error {
+func createRigSessionCC(name, repoPath string, opts SessionOptions) error {
 	// Create session with single window (add emoji to window name for iTerm2)
 	windowName := "🏗️  " + name
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", name, "-n", windowName, "-c", repoPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-session", "-d", "-s", name, "-n", windowName, "-c", repoPath); err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// Disable automatic renaming
-	cmd = exec.Command("tmux", "set-window-option", "-t", name, "automatic-rename", "off")
-	if err := cmd.Run(); err != nil {
+	if _, err := run("set-window-option", "-t", name, "automatic-rename", "off"); err != nil {
 		return err
 	}
 
 	// Split window vertically
-	cmd = exec.Command("tmux", "split-window", "-h", "-t", name, "-c", repoPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("split-window", "-h", "-t", name, "-c", repoPath); err != nil {
 		return err
 	}
 
 	// Set pane titles
-	exec.Command("tmux", "select-pane", "-t", name+":.1", "-T", "Claude Code").Run()
-	exec.Command("tmux", "select-pane", "-t", name+":.2", "-T", "Terminal").Run()
+	run("select-pane", "-t", name+":.1", "-T", "Claude Code")
+	run("select-pane", "-t", name+":.2", "-T", "Terminal")
 
 	// Resize panes (70/30 split)
-	exec.Command("tmux", "resize-pane", "-t", name+":.1", "-x", "70%").Run()
+	run("resize-pane", "-t", name+":.1", "-x", "70%")
 
 	// Select Claude Code pane
-	exec.Command("tmux", "select-pane", "-t", name+":.1").Run()
+	run("select-pane", "-t", name+":.1")
 
 	// Start Claude Code
 	sendKeys(name+":.1", "cd "+repoPath)
+	for _, keys := range ExportCommands(opts.Env) {
+		sendKeys(name+":.1", keys)
+	}
 	time.Sleep(100 * time.Millisecond)
 	sendKeys(name+":.1", "claude")
 
 	// Send initial prompt if configured
-	if initPrompt != "" {
+	if opts.InitPrompt != "" {
 		time.Sleep(2 * time.Second) // Wait for Claude Code to start
-		sendKeys(name+":.1", initPrompt)
+		sendKeys(name+":.1", opts.InitPrompt)
 	}
 
 	// Terminal pane
-	sendKeys(name+":.2", "cd "+repoPath)
-	sendKeys(name+":.2", fmt.Sprintf("echo '# %s terminal'", name))
-	sendKeys(name+":.2", "git status")
+	for _, keys := range terminalCommands(repoPath, fmt.Sprintf("# %s terminal", name), opts) {
+		sendKeys(name+":.2", keys)
+	}
+
+	if len(opts.Repos) > 0 {
+		if err := openRepoWindows(name, opts, true); err != nil {
+			return err
+		}
+		run("select-pane", "-t", name+":.1")
+	}
+
+	return nil
+}
 
+// repoWindow is a window (or pane in CC mode) opened for an extra repo
+type repoWindow struct {
+	Repo   string
+	Args   []string // tmux invocation that creates it
+	Target string   // where to send keys once it exists
+}
+
+// repoWindows returns the windows for a rig's extra repos, numbered after
+// the Claude Code and terminal windows. In CC mode each repo gets a pane
+// split off the previous one instead.
+func repoWindows(name string, repos []string, useCC bool) []repoWindow {
+	windows := []repoWindow{}
+	for i, repo := range repos {
+		index := i + 3
+		if useCC {
+			windows = append(windows, repoWindow{
+				Repo:   repo,
+				Args:   []string{"split-window", "-v", "-t", fmt.Sprintf("%s:.%d", name, index-1), "-c", repo},
+				Target: fmt.Sprintf("%s:.%d", name, index),
+			})
+			continue
+		}
+		windows = append(windows, repoWindow{
+			Repo:   repo,
+			Args:   []string{"new-window", "-t", name, "-n", filepath.Base(repo), "-c", repo},
+			Target: fmt.Sprintf("%s:%d", name, index),
+		})
+	}
+	return windows
+}
+
+// openRepoWindows opens a terminal in each of a rig's extra repos
+func openRepoWindows(name string, opts SessionOptions, useCC bool) error {
+	for _, w := range repoWindows(name, opts.Repos, useCC) {
+		if output, err := run(w.Args...); err != nil {
+			return fmt.Errorf("failed to open window for %s: %w\n%s", w.Repo, err, string(output))
+		}
+		header := fmt.Sprintf("# %s (%s rig)", filepath.Base(w.Repo), name)
+		for _, keys := range terminalCommands(w.Repo, header, SessionOptions{Env: opts.Env}) {
+			sendKeys(w.Target, keys)
+		}
+	}
 	return nil
 }
 
 // CreateCrewSession creates a tmux session for a crew member
-func CreateCrewSession(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, initPrompt string) error {
+func CreateCrewSession(sessionName, crewPath, rigName, memberName, branchName string, useCC bool, opts SessionOptions) error {
 	sessionName = NormalizeSessionName(sessionName)
 	if useCC {
-		return createCrewSessionCC(sessionName, crewPath, rigName, memberName, branchName, initPrompt)
+		return createCrewSessionCC(sessionName, crewPath, rigName, memberName, branchName, opts)
 	}
-	return createCrewSessionNative(sessionName, crewPath, rigName, memberName, branchName, initPrompt)
+	return createCrewSessionNative(sessionName, crewPath, rigName, memberName, branchName, opts)
 }
 
-func createCrewSessionNative(sessionName, crewPath, rigName, memberName, branchName string, initPrompt string) error {
+func createCrewSessionNative(sessionName, crewPath, rigName, memberName, branchName string, opts SessionOptions) error {
 	// Create session with first window
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-n", "Claude Code", "-c", crewPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-session", "-d", "-s", sessionName, "-n", "Claude Code", "-c", crewPath); err != nil {
 		return fmt.Errorf("failed to create crew session: %w", err)
 	}
 
 	// Start Claude Code
 	sendKeys(sessionName+":1", "cd "+crewPath)
+	for _, keys := range ExportCommands(opts.Env) {
+		sendKeys(sessionName+":1", keys)
+	}
 	time.Sleep(100 * time.Millisecond)
 	sendKeys(sessionName+":1", "claude")
 
 	// Send initial prompt if configured
-	if initPrompt != "" {
+	if opts.InitPrompt != "" {
 		time.Sleep(2 * time.Second) // Wait for Claude Code to start
-		sendKeys(sessionName+":1", initPrompt)
+		sendKeys(sessionName+":1", opts.InitPrompt)
 	}
 
 	// Create second window
-	cmd = exec.Command("tmux", "new-window", "-t", sessionName, "-n", "Terminal", "-c", crewPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-window", "-t", sessionName, "-n", "Terminal", "-c", crewPath); err != nil {
 		return err
 	}
 
-	sendKeys(sessionName+":2", "cd "+crewPath)
-	sendKeys(sessionName+":2", fmt.Sprintf("echo '# %s on %s (branch: %s)'", memberName, rigName, branchName))
-	sendKeys(sessionName+":2", "git status")
+	header := fmt.Sprintf("# %s on %s (branch: %s)", memberName, rigName, branchName)
+	for _, keys := range terminalCommands(crewPath, header, opts) {
+		sendKeys(sessionName+":2", keys)
+	}
 
 	// Select first window
-	cmd = exec.Command("tmux", "select-window", "-t", sessionName+":1")
-	return cmd.Run()
+	_, err := run("select-window", "-t", sessionName+":1")
+	return err
 }
 
-func createCrewSessionCC(sessionName, crewPath, rigName, memberName, branchName string, initPrompt string) error {
+func createCrewSessionCC(sessionName, crewPath, rigName, memberName, branchName string, opts SessionOptions) error {
 	// Determine emoji based on crew type
 	emoji := "👤"
 	if strings.HasPrefix(memberName, "polecat_") {
@@ -233,42 +481,176 @@ func createCrewSessionCC(sessionName, crewPath, rigName, memberName, branchName
 	}
 	windowName := emoji + " " + sessionName
 
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-n", windowName, "-c", crewPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("new-session", "-d", "-s", sessionName, "-n", windowName, "-c", crewPath); err != nil {
 		return fmt.Errorf("failed to create crew session: %w", err)
 	}
 
-	exec.Command("tmux", "set-window-option", "-t", sessionName, "automatic-rename", "off").Run()
+	run("set-window-option", "-t", sessionName, "automatic-rename", "off")
 
-	cmd = exec.Command("tmux", "split-window", "-h", "-t", sessionName, "-c", crewPath)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("split-window", "-h", "-t", sessionName, "-c", crewPath); err != nil {
 		return err
 	}
 
-	exec.Command("tmux", "select-pane", "-t", sessionName+":.1", "-T", "Claude Code").Run()
-	exec.Command("tmux", "select-pane", "-t", sessionName+":.2", "-T", "Terminal").Run()
-	exec.Command("tmux", "resize-pane", "-t", sessionName+":.1", "-x", "70%").Run()
-	exec.Command("tmux", "select-pane", "-t", sessionName+":.1").Run()
+	run("select-pane", "-t", sessionName+":.1", "-T", "Claude Code")
+	run("select-pane", "-t", sessionName+":.2", "-T", "Terminal")
+	run("resize-pane", "-t", sessionName+":.1", "-x", "70%")
+	run("select-pane", "-t", sessionName+":.1")
 
 	sendKeys(sessionName+":.1", "cd "+crewPath)
+	for _, keys := range ExportCommands(opts.Env) {
+		sendKeys(sessionName+":.1", keys)
+	}
 	time.Sleep(100 * time.Millisecond)
 	sendKeys(sessionName+":.1", "claude")
 
 	// Send initial prompt if configured
-	if initPrompt != "" {
+	if opts.InitPrompt != "" {
 		time.Sleep(2 * time.Second) // Wait for Claude Code to start
-		sendKeys(sessionName+":.1", initPrompt)
+		sendKeys(sessionName+":.1", opts.InitPrompt)
+	}
+
+	header := fmt.Sprintf("# %s on %s (branch: %s)", memberName, rigName, branchName)
+	for _, keys := range terminalCommands(crewPath, header, opts) {
+		sendKeys(sessionName+":.2", keys)
+	}
+
+	return nil
+}
+
+// Pane names accepted by PaneTarget
+const (
+	PaneClaude   = "claude"
+	PaneTerminal = "terminal"
+)
+
+// PaneTarget returns the tmux target for a named pane in a rig or crew session.
+// Native sessions use a window per pane; CC sessions split a single window.
+func PaneTarget(sessionName, pane string, useCC bool) (string, error) {
+	sessionName = NormalizeSessionName(sessionName)
+
+	var index string
+	switch pane {
+	case PaneClaude:
+		index = "1"
+	case PaneTerminal, "":
+		index = "2"
+	default:
+		return "", fmt.Errorf("unknown pane %q (expected %s or %s)", pane, PaneClaude, PaneTerminal)
+	}
+
+	if useCC {
+		return sessionName + ":." + index, nil
+	}
+	return sessionName + ":" + index, nil
+}
+
+// SelectPane makes a named pane the active one in a rig or crew session, so
+// attaching lands there. Native sessions select its window; CC sessions
+// select the pane within their single window.
+func SelectPane(sessionName, pane string, useCC bool) error {
+	args, err := selectPaneArgs(sessionName, pane, useCC)
+	if err != nil {
+		return err
+	}
+	if output, err := run(args...); err != nil {
+		return fmt.Errorf("failed to select %s in %s: %w\n%s", pane, sessionName, err, string(output))
 	}
+	return nil
+}
 
-	sendKeys(sessionName+":.2", "cd "+crewPath)
-	sendKeys(sessionName+":.2", fmt.Sprintf("echo '# %s on %s (branch: %s)'", memberName, rigName, branchName))
-	sendKeys(sessionName+":.2", "git status")
+func selectPaneArgs(sessionName, pane string, useCC bool) ([]string, error) {
+	target, err := PaneTarget(sessionName, pane, useCC)
+	if err != nil {
+		return nil, err
+	}
+	if useCC {
+		return []string{"select-pane", "-t", target}, nil
+	}
+	return []string{"select-window", "-t", target}, nil
+}
 
+// SendCommand types a command into the target pane and presses enter
+func SendCommand(target, command string) error {
+	for _, args := range sendCommandArgs(target, command) {
+		if output, err := run(args...); err != nil {
+			return fmt.Errorf("failed to send command to %s: %w\n%s", target, err, string(output))
+		}
+	}
 	return nil
 }
 
+// sendCommandArgs returns the tmux invocations used by SendCommand. The text is
+// sent literally so words like "Enter" or "Up" aren't treated as key names.
+func sendCommandArgs(target, command string) [][]string {
+	return [][]string{
+		{"send-keys", "-t", target, "-l", command},
+		{"send-keys", "-t", target, "Enter"},
+	}
+}
+
+// CapturePane returns the visible contents of the target pane
+func CapturePane(target string) (string, error) {
+	output, err := run(capturePaneArgs(target)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane %s: %w\n%s", target, err, string(output))
+	}
+	return string(output), nil
+}
+
+func capturePaneArgs(target string) []string {
+	return []string{"capture-pane", "-p", "-t", target}
+}
+
+// terminalCommands returns the keys typed into a new session's terminal pane:
+// cd into the workspace, export the environment, print a header, show git
+// status, then run the setup script if there is one
+func terminalCommands(dir, header string, opts SessionOptions) []string {
+	commands := []string{"cd " + dir}
+	commands = append(commands, ExportCommands(opts.Env)...)
+	commands = append(commands,
+		fmt.Sprintf("echo '%s'", header),
+		"git status",
+	)
+	if opts.SetupScript != "" {
+		commands = append(commands, shellQuote(opts.SetupScript))
+	}
+	return commands
+}
+
+// ExportCommands returns a shell export command for each variable, sorted by
+// name, with values quoted so spaces and quotes survive
+func ExportCommands(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	commands := make([]string, 0, len(keys))
+	for _, key := range keys {
+		commands = append(commands, "export "+key+"="+shellQuote(env[key]))
+	}
+	return commands
+}
+
+// shellQuote single-quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func sendKeys(target, keys string) {
-	exec.Command("tmux", "send-keys", "-t", target, keys, "C-m").Run()
+	run("send-keys", "-t", target, keys, "C-m")
+}
+
+// command builds the tmux invocation for args. Every tmux call goes through
+// it, so tests can replace it to capture the commands instead of running them.
+var command = func(args ...string) *exec.Cmd {
+	return exec.Command("tmux", args...)
+}
+
+// run runs tmux with args and returns its combined output
+func run(args ...string) ([]byte, error) {
+	return command(args...).CombinedOutput()
 }
 
 // GetCurrentSession returns the current tmux session name, or empty string if not in tmux
@@ -276,8 +658,7 @@ func GetCurrentSession() string {
 	if os.Getenv("TMUX") == "" {
 		return ""
 	}
-	cmd := exec.Command("tmux", "display-message", "-p", "#S")
-	output, err := cmd.Output()
+	output, err := command("display-message", "-p", "#S").Output()
 	if err != nil {
 		return ""
 	}
@@ -1,6 +1,12 @@
 package tmux
 
-import "testing"
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestNormalizeSessionName(t *testing.T) {
 	tests := []struct {
@@ -22,3 +28,598 @@ func TestNormalizeSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionSet(t *testing.T) {
+	set := NewSessionSet([]string{"notes", "my_app", "notes@tracy"})
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"notes", true},
+		{"notes@tracy", true},
+		{"my_app", true},
+		{"my.app", true},
+		{"myapp", false},
+		{"notes@alex", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := set.Has(tt.name); result != tt.expected {
+				t.Errorf("Has(%q) = %v, want %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPaneTarget(t *testing.T) {
+	tests := []struct {
+		session  string
+		pane     string
+		useCC    bool
+		expected string
+		wantErr  bool
+	}{
+		{"notes", "", false, "notes:2", false},
+		{"notes", PaneTerminal, false, "notes:2", false},
+		{"notes", PaneClaude, false, "notes:1", false},
+		{"notes@tracy", PaneTerminal, true, "notes@tracy:.2", false},
+		{"notes@tracy", PaneClaude, true, "notes@tracy:.1", false},
+		{"my.app", PaneClaude, false, "my_app:1", false},
+		{"notes", "editor", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.session+"/"+tt.pane, func(t *testing.T) {
+			result, err := PaneTarget(tt.session, tt.pane, tt.useCC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PaneTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("PaneTarget(%q, %q, %v) = %q, want %q", tt.session, tt.pane, tt.useCC, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSendCommandArgs(t *testing.T) {
+	result := sendCommandArgs("notes:2", "make test")
+	expected := [][]string{
+		{"send-keys", "-t", "notes:2", "-l", "make test"},
+		{"send-keys", "-t", "notes:2", "Enter"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("sendCommandArgs() = %v, want %v", result, expected)
+	}
+}
+
+func TestCapturePaneArgs(t *testing.T) {
+	result := capturePaneArgs("notes@tracy:.1")
+	expected := []string{"capture-pane", "-p", "-t", "notes@tracy:.1"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("capturePaneArgs() = %v, want %v", result, expected)
+	}
+}
+
+func TestCapturePane(t *testing.T) {
+	// Skip if tmux is not available
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available, skipping integration test")
+	}
+
+	// Use a private server so the user's sessions are untouched
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	defer exec.Command("tmux", "kill-server").Run()
+
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "peek", "-x", "80", "-y", "10", "cat").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := SendCommand("peek", "hello from the pane"); err != nil {
+		t.Fatalf("SendCommand() error = %v", err)
+	}
+
+	var output string
+	for i := 0; i < 20; i++ {
+		var err error
+		output, err = CapturePane("peek")
+		if err != nil {
+			t.Fatalf("CapturePane() error = %v", err)
+		}
+		if strings.Contains(output, "hello from the pane") {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Errorf("Expected captured pane to contain sent text, got %q", output)
+}
+
+func TestTerminalCommands(t *testing.T) {
+	t.Run("without setup script", func(t *testing.T) {
+		result := terminalCommands("/home/me/git/notes", "# notes terminal", SessionOptions{})
+		expected := []string{
+			"cd /home/me/git/notes",
+			"echo '# notes terminal'",
+			"git status",
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("terminalCommands() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("with setup script", func(t *testing.T) {
+		opts := SessionOptions{SetupScript: "/home/me/crew/notes/tracy/.rig/setup.sh"}
+		result := terminalCommands("/home/me/crew/notes/tracy", "# tracy on notes (branch: tracy/work)", opts)
+		expected := []string{
+			"cd /home/me/crew/notes/tracy",
+			"echo '# tracy on notes (branch: tracy/work)'",
+			"git status",
+			"'/home/me/crew/notes/tracy/.rig/setup.sh'",
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("terminalCommands() = %v, want %v", result, expected)
+		}
+	})
+}
+
+func TestTerminalCommandsWithEnv(t *testing.T) {
+	opts := SessionOptions{
+		Env:         map[string]string{"PORT": "3000"},
+		SetupScript: "/repo/.rig/setup.sh",
+	}
+	result := terminalCommands("/repo", "# repo terminal", opts)
+	expected := []string{
+		"cd /repo",
+		"export PORT='3000'",
+		"echo '# repo terminal'",
+		"git status",
+		"'/repo/.rig/setup.sh'",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("terminalCommands() = %v, want %v", result, expected)
+	}
+}
+
+func TestExportCommands(t *testing.T) {
+	env := map[string]string{
+		"PORT":         "3000",
+		"GREETING":     "hello world",
+		"QUOTED":       "it's here",
+		"EMPTY":        "",
+		"NO_EXPANSION": "$HOME `id`",
+	}
+	expected := []string{
+		"export EMPTY=''",
+		"export GREETING='hello world'",
+		"export NO_EXPANSION='$HOME `id`'",
+		"export PORT='3000'",
+		`export QUOTED='it'\''s here'`,
+	}
+
+	result := ExportCommands(env)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ExportCommands() = %v, want %v", result, expected)
+	}
+
+	if result := ExportCommands(nil); len(result) != 0 {
+		t.Errorf("Expected no commands for empty env, got %v", result)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/plain/path", "'/plain/path'"},
+		{"/with space/setup.sh", "'/with space/setup.sh'"},
+		{"it's", `'it'\''s'`},
+		{"", "''"},
+	}
+
+	for _, tt := range tests {
+		if result := shellQuote(tt.input); result != tt.expected {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestSessionHealthArgs(t *testing.T) {
+	expected := []string{"list-panes", "-s", "-t", "my_app", "-F", "#{pane_dead}"}
+	if got := sessionHealthArgs("my_app"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("sessionHealthArgs() = %v, want %v", got, expected)
+	}
+}
+
+func TestLivePanes(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected int
+	}{
+		{"", 0},
+		{"1\n", 0},
+		{"1\n1\n", 0},
+		{"0\n", 1},
+		{"0\n1\n0\n", 2},
+	}
+
+	for _, tt := range tests {
+		if got := livePanes(tt.output); got != tt.expected {
+			t.Errorf("livePanes(%q) = %d, want %d", tt.output, got, tt.expected)
+		}
+	}
+}
+
+func TestSessionHealthy(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available, skipping")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { exec.Command("tmux", "kill-server").Run() })
+
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "alive").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if !SessionHealthy("alive") {
+		t.Error("Expected session with a running shell to be healthy")
+	}
+
+	// A pane that exits with remain-on-exit set leaves a dead session behind
+	exec.Command("tmux", "set-option", "-g", "remain-on-exit", "on").Run()
+	exec.Command("tmux", "new-session", "-d", "-s", "crashed", "true").Run()
+	time.Sleep(200 * time.Millisecond)
+	if !SessionExists("crashed") {
+		t.Skip("tmux removed the exited session, skipping")
+	}
+	if SessionHealthy("crashed") {
+		t.Error("Expected session with only dead panes to be unhealthy")
+	}
+
+	if SessionHealthy("missing") {
+		t.Error("Expected missing session to be unhealthy")
+	}
+}
+
+func TestAttachPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		inTmux   bool
+		useCC    bool
+		expected [][]string
+	}{
+		{"outside tmux", false, false, [][]string{{"attach-session", "-t", "app"}}},
+		{"outside tmux with CC", false, true, [][]string{{"-CC", "attach-session", "-t", "app"}}},
+		{"inside tmux", true, false, [][]string{
+			{"switch-client", "-t", "app"},
+			{"attach-session", "-t", "app"},
+		}},
+		{"inside tmux with CC", true, true, [][]string{
+			{"switch-client", "-t", "app"},
+			{"-CC", "attach-session", "-t", "app"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attachPlan("app", tt.inTmux, tt.useCC); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("attachPlan() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvWithout(t *testing.T) {
+	env := []string{"HOME=/home/me", "TMUX=/tmp/tmux-1/default,1,0", "TMUX_PANE=%1"}
+	expected := []string{"HOME=/home/me", "TMUX_PANE=%1"}
+	if got := envWithout(env, "TMUX"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("envWithout() = %v, want %v", got, expected)
+	}
+}
+
+func TestRenameSessionArgs(t *testing.T) {
+	expected := []string{"rename-session", "-t", "my_app", "my_app@tracy"}
+	if got := renameSessionArgs("my.app", "my.app@tracy"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("renameSessionArgs() = %v, want %v", got, expected)
+	}
+}
+
+func TestRenameSession(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available, skipping")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { exec.Command("tmux", "kill-server").Run() })
+
+	if err := exec.Command("tmux", "new-session", "-d", "-s", "old_name").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	if err := RenameSession("old.name", "new.name"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+	if SessionExists("old_name") || !SessionExists("new_name") {
+		t.Error("Expected old_name to be renamed to new_name")
+	}
+
+	if err := RenameSession("missing", "other"); err == nil {
+		t.Error("Expected error renaming a missing session")
+	}
+}
+
+func TestParseSession(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Session
+	}{
+		{"notes|1700000000|2|1", Session{Name: "notes", Created: time.Unix(1700000000, 0), Windows: 2, Attached: 1}},
+		{"notes@tracy|1700000100|3|0", Session{Name: "notes@tracy", Created: time.Unix(1700000100, 0), Windows: 3, Attached: 0}},
+		{"odd|name|1700000000|1|2", Session{Name: "odd|name", Created: time.Unix(1700000000, 0), Windows: 1, Attached: 2}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSession(tt.line)
+		if err != nil {
+			t.Errorf("parseSession(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parseSession(%q) = %+v, want %+v", tt.line, got, tt.expected)
+		}
+	}
+
+	for _, line := range []string{"notes", "notes|x|2|1", "notes|1700000000|2|yes"} {
+		if _, err := parseSession(line); err == nil {
+			t.Errorf("parseSession(%q) expected error", line)
+		}
+	}
+}
+
+func TestSelectPaneArgs(t *testing.T) {
+	tests := []struct {
+		pane     string
+		useCC    bool
+		expected []string
+	}{
+		{PaneClaude, false, []string{"select-window", "-t", "my_app:1"}},
+		{PaneTerminal, false, []string{"select-window", "-t", "my_app:2"}},
+		{PaneClaude, true, []string{"select-pane", "-t", "my_app:.1"}},
+		{PaneTerminal, true, []string{"select-pane", "-t", "my_app:.2"}},
+	}
+
+	for _, tt := range tests {
+		got, err := selectPaneArgs("my.app", tt.pane, tt.useCC)
+		if err != nil {
+			t.Errorf("selectPaneArgs(%s, %v) error = %v", tt.pane, tt.useCC, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("selectPaneArgs(%s, %v) = %v, want %v", tt.pane, tt.useCC, got, tt.expected)
+		}
+	}
+
+	if _, err := selectPaneArgs("my.app", "editor", false); err == nil {
+		t.Error("Expected error for unknown pane")
+	}
+}
+
+func TestRepoWindows(t *testing.T) {
+	repos := []string{"/home/me/git/api", "/home/me/git/docs"}
+
+	native := repoWindows("web", repos, false)
+	expectedNative := []repoWindow{
+		{"/home/me/git/api", []string{"new-window", "-t", "web", "-n", "api", "-c", "/home/me/git/api"}, "web:3"},
+		{"/home/me/git/docs", []string{"new-window", "-t", "web", "-n", "docs", "-c", "/home/me/git/docs"}, "web:4"},
+	}
+	if !reflect.DeepEqual(native, expectedNative) {
+		t.Errorf("repoWindows() = %v, want %v", native, expectedNative)
+	}
+
+	cc := repoWindows("web", repos, true)
+	expectedCC := []repoWindow{
+		{"/home/me/git/api", []string{"split-window", "-v", "-t", "web:.2", "-c", "/home/me/git/api"}, "web:.3"},
+		{"/home/me/git/docs", []string{"split-window", "-v", "-t", "web:.3", "-c", "/home/me/git/docs"}, "web:.4"},
+	}
+	if !reflect.DeepEqual(cc, expectedCC) {
+		t.Errorf("repoWindows() in CC mode = %v, want %v", cc, expectedCC)
+	}
+
+	if got := repoWindows("web", nil, false); len(got) != 0 {
+		t.Errorf("Expected no windows without repos, got %v", got)
+	}
+}
+
+// stubRun records the tmux commands run instead of running them. fail makes
+// the command with that name fail.
+func stubRun(t *testing.T, fail string) *[][]string {
+	t.Helper()
+
+	var commands [][]string
+	origCommand := command
+	command = func(args ...string) *exec.Cmd {
+		commands = append(commands, args)
+		if args[0] == fail {
+			return exec.Command("sh", "-c", "echo no server running; exit 1")
+		}
+		return exec.Command("true")
+	}
+	t.Cleanup(func() { command = origCommand })
+	return &commands
+}
+
+// keys is the send-keys invocation sendKeys runs
+func keys(target, text string) []string {
+	return []string{"send-keys", "-t", target, text, "C-m"}
+}
+
+func TestCreateCrewSessionCommands(t *testing.T) {
+	const session, dir = "notes@tracy", "/crew/notes/tracy"
+	opts := SessionOptions{Env: map[string]string{"A": "1"}}
+	header := "echo '# tracy on notes (branch: tracy/work)'"
+
+	tests := []struct {
+		name     string
+		useCC    bool
+		expected [][]string
+	}{
+		{
+			name:  "native",
+			useCC: false,
+			expected: [][]string{
+				{"new-session", "-d", "-s", session, "-n", "Claude Code", "-c", dir},
+				keys(session+":1", "cd "+dir),
+				keys(session+":1", "export A='1'"),
+				keys(session+":1", "claude"),
+				{"new-window", "-t", session, "-n", "Terminal", "-c", dir},
+				keys(session+":2", "cd "+dir),
+				keys(session+":2", "export A='1'"),
+				keys(session+":2", header),
+				keys(session+":2", "git status"),
+				{"select-window", "-t", session + ":1"},
+			},
+		},
+		{
+			name:  "cc",
+			useCC: true,
+			expected: [][]string{
+				{"new-session", "-d", "-s", session, "-n", "👤 " + session, "-c", dir},
+				{"set-window-option", "-t", session, "automatic-rename", "off"},
+				{"split-window", "-h", "-t", session, "-c", dir},
+				{"select-pane", "-t", session + ":.1", "-T", "Claude Code"},
+				{"select-pane", "-t", session + ":.2", "-T", "Terminal"},
+				{"resize-pane", "-t", session + ":.1", "-x", "70%"},
+				{"select-pane", "-t", session + ":.1"},
+				keys(session+":.1", "cd "+dir),
+				keys(session+":.1", "export A='1'"),
+				keys(session+":.1", "claude"),
+				keys(session+":.2", "cd "+dir),
+				keys(session+":.2", "export A='1'"),
+				keys(session+":.2", header),
+				keys(session+":.2", "git status"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := stubRun(t, "")
+			if err := CreateCrewSession(session, dir, "notes", "tracy", "tracy/work", tt.useCC, opts); err != nil {
+				t.Fatalf("CreateCrewSession() error = %v", err)
+			}
+			if !reflect.DeepEqual(*commands, tt.expected) {
+				t.Errorf("CreateCrewSession() ran:\n%q\nwant:\n%q", *commands, tt.expected)
+			}
+		})
+	}
+
+	// Nothing else runs once the session can't be created
+	commands := stubRun(t, "new-session")
+	err := CreateCrewSession(session, dir, "notes", "tracy", "tracy/work", false, opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to create crew session") {
+		t.Errorf("Expected session creation error, got %v", err)
+	}
+	if len(*commands) != 1 {
+		t.Errorf("Expected only new-session to run, got %q", *commands)
+	}
+}
+
+func TestSessionCommands(t *testing.T) {
+	commands := stubRun(t, "")
+	if !SessionExists("my.app@tracy") {
+		t.Error("Expected SessionExists() to report the session")
+	}
+	if err := KillSession("my.app@tracy"); err != nil {
+		t.Errorf("KillSession() error = %v", err)
+	}
+	expected := [][]string{
+		{"has-session", "-t", "my_app@tracy"},
+		{"kill-session", "-t", "my_app@tracy"},
+	}
+	if !reflect.DeepEqual(*commands, expected) {
+		t.Errorf("Ran:\n%q\nwant:\n%q", *commands, expected)
+	}
+
+	// Inside tmux, attaching falls back to a new client when switching fails
+	t.Setenv("TMUX", "/tmp/tmux-1/default,1,0")
+	commands = stubRun(t, "switch-client")
+	if err := AttachSession("my.app@tracy", true); err != nil {
+		t.Errorf("AttachSession() error = %v", err)
+	}
+	expected = [][]string{
+		{"switch-client", "-t", "my_app@tracy"},
+		{"-CC", "attach-session", "-t", "my_app@tracy"},
+	}
+	if !reflect.DeepEqual(*commands, expected) {
+		t.Errorf("AttachSession() ran:\n%q\nwant:\n%q", *commands, expected)
+	}
+}
+
+func TestCreateRigSessionCommands(t *testing.T) {
+	const session, dir = "my_app", "/git/my.app"
+	opts := SessionOptions{Repos: []string{"/git/api"}}
+	header := "echo '# my_app terminal'"
+	apiHeader := "echo '# api (my_app rig)'"
+
+	tests := []struct {
+		name     string
+		useCC    bool
+		expected [][]string
+	}{
+		{
+			name:  "native",
+			useCC: false,
+			expected: [][]string{
+				{"new-session", "-d", "-s", session, "-n", "Claude Code", "-c", dir},
+				keys(session+":1", "cd "+dir),
+				keys(session+":1", "claude"),
+				{"new-window", "-t", session, "-n", "Terminal", "-c", dir},
+				keys(session+":2", "cd "+dir),
+				keys(session+":2", header),
+				keys(session+":2", "git status"),
+				{"new-window", "-t", session, "-n", "api", "-c", "/git/api"},
+				keys(session+":3", "cd /git/api"),
+				keys(session+":3", apiHeader),
+				keys(session+":3", "git status"),
+				{"select-window", "-t", session + ":1"},
+			},
+		},
+		{
+			name:  "cc",
+			useCC: true,
+			expected: [][]string{
+				{"new-session", "-d", "-s", session, "-n", "🏗️  " + session, "-c", dir},
+				{"set-window-option", "-t", session, "automatic-rename", "off"},
+				{"split-window", "-h", "-t", session, "-c", dir},
+				{"select-pane", "-t", session + ":.1", "-T", "Claude Code"},
+				{"select-pane", "-t", session + ":.2", "-T", "Terminal"},
+				{"resize-pane", "-t", session + ":.1", "-x", "70%"},
+				{"select-pane", "-t", session + ":.1"},
+				keys(session+":.1", "cd "+dir),
+				keys(session+":.1", "claude"),
+				keys(session+":.2", "cd "+dir),
+				keys(session+":.2", header),
+				keys(session+":.2", "git status"),
+				{"split-window", "-v", "-t", session + ":.2", "-c", "/git/api"},
+				keys(session+":.3", "cd /git/api"),
+				keys(session+":.3", apiHeader),
+				keys(session+":.3", "git status"),
+				{"select-pane", "-t", session + ":.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := stubRun(t, "")
+			if err := CreateRigSession("my.app", dir, tt.useCC, opts); err != nil {
+				t.Fatalf("CreateRigSession() error = %v", err)
+			}
+			if !reflect.DeepEqual(*commands, tt.expected) {
+				t.Errorf("CreateRigSession() ran:\n%q\nwant:\n%q", *commands, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+)
+
+// Quiet suppresses informational output when set (rig --quiet)
+var Quiet bool
+
+// Printf prints informational output unless Quiet is set
+func Printf(format string, a ...any) {
+	if Quiet {
+		return
+	}
+	fmt.Printf(format, a...)
+}
+
+// Println prints informational output unless Quiet is set
+func Println(a ...any) {
+	if Quiet {
+		return
+	}
+	fmt.Println(a...)
+}
+
+// Warnf prints a warning to stderr, even when Quiet is set
+func Warnf(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, "⚠️  %s\n", fmt.Sprintf(format, a...))
+}
+
+// ColorEnabled reports whether output may use ANSI colors: stdout is a
+// terminal and NO_COLOR (https://no-color.org) isn't set
+func ColorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file;
+// replaced in tests
+var isTerminal = func(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Green colors s for good states, like running or done
+func Green(s string) string {
+	return colorize("32", s)
+}
+
+// Yellow colors s for states that need attention, like in progress
+func Yellow(s string) string {
+	return colorize("33", s)
+}
+
+// Red colors s for bad states, like dirty or blocked
+func Red(s string) string {
+	return colorize("31", s)
+}
+
+func colorize(code, s string) string {
+	if s == "" || !ColorEnabled() {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func capture(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	origStdout := os.Stdout
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = origStdout
+
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestQuiet(t *testing.T) {
+	t.Cleanup(func() { Quiet = false })
+
+	if got := capture(t, func() { Printf("✓ %s\n", "done"); Println("next") }); got != "✓ done\nnext\n" {
+		t.Errorf("output = %q, want both lines", got)
+	}
+
+	Quiet = true
+	if got := capture(t, func() { Printf("✓ %s\n", "done"); Println("next") }); got != "" {
+		t.Errorf("quiet output = %q, want none", got)
+	}
+}
+
+func TestWarnf(t *testing.T) {
+	t.Cleanup(func() { Quiet = false })
+	Quiet = true
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	origStderr := os.Stderr
+	os.Stderr = w
+	stdout := capture(t, func() { Warnf("branch %s already exists", "feat/login") })
+	w.Close()
+	os.Stderr = origStderr
+	stderr, _ := io.ReadAll(r)
+
+	// Warnings go to stderr, even when quiet
+	if stdout != "" {
+		t.Errorf("stdout = %q, want no output", stdout)
+	}
+	if string(stderr) != "⚠️  branch feat/login already exists\n" {
+		t.Errorf("stderr = %q, want the warning", stderr)
+	}
+}
+
+func TestColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	// Piped output has no color
+	if got := capture(t, func() { fmt.Print(Green("running")) }); got != "running" {
+		t.Errorf("piped output = %q, want no color", got)
+	}
+
+	origIsTerminal := isTerminal
+	isTerminal = func(f *os.File) bool { return true }
+	t.Cleanup(func() { isTerminal = origIsTerminal })
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "green", got: Green("running"), want: "\x1b[32mrunning\x1b[0m"},
+		{name: "yellow", got: Yellow("In Progress"), want: "\x1b[33mIn Progress\x1b[0m"},
+		{name: "red", got: Red("dirty"), want: "\x1b[31mdirty\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s on a terminal = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if got := Red("dirty"); got != "dirty" {
+		t.Errorf("Red() with NO_COLOR = %q, want no color", got)
+	}
+}
@@ -2,11 +2,14 @@ package work
 
 import (
 	"bufio"
+	"embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 )
 
 // Work represents a feature work item
@@ -39,6 +42,21 @@ func InferWorkFromBranch(branchName string) string {
 	return ""
 }
 
+// ReviewBranch returns the branch a reviewer of a work item works on
+// build-frontend -> review/build-frontend
+func ReviewBranch(workName string) string {
+	return "review/" + workName
+}
+
+// InferReviewFromBranch extracts work name from a review branch name
+// review/build-frontend -> build-frontend
+func InferReviewFromBranch(branchName string) string {
+	if strings.HasPrefix(branchName, "review/") {
+		return strings.TrimPrefix(branchName, "review/")
+	}
+	return ""
+}
+
 // GetWorkPath returns the full path to a work directory
 func GetWorkPath(repoPath, workName string) string {
 	return filepath.Join(repoPath, "work", workName)
@@ -65,23 +83,28 @@ func Create(repoPath, workName string) error {
 	}
 
 	// Files to create (will skip if they exist)
-	files := map[string]string{
-		"spec.md":      getSpecTemplate(workName),
-		"design.md":    getDesignTemplate(workName),
-		"breakdown.md": getBreakdownTemplate(workName),
-		"progress.md":  getProgressTemplate(workName),
+	files := map[string]func(string) string{
+		"spec.md":      getSpecTemplate,
+		"design.md":    getDesignTemplate,
+		"breakdown.md": getBreakdownTemplate,
+		"progress.md":  getProgressTemplate,
 	}
 
 	createdFiles := []string{}
 	skippedFiles := []string{}
 
-	for filename, content := range files {
+	for filename, builtin := range files {
 		filePath := filepath.Join(workPath, filename)
 		if _, err := os.Stat(filePath); err == nil {
 			skippedFiles = append(skippedFiles, filename)
 			continue
 		}
 
+		content, err := scaffoldContent(repoPath, filename, workName, builtin)
+		if err != nil {
+			return err
+		}
+
 		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 			return fmt.Errorf("failed to create %s: %w", filename, err)
 		}
@@ -92,13 +115,99 @@ func Create(repoPath, workName string) error {
 	if err := EnsureDefaultFormula(repoPath); err != nil {
 		return fmt.Errorf("failed to install default formula: %w", err)
 	}
+	if err := EnsureReviewFormula(repoPath); err != nil {
+		return fmt.Errorf("failed to install review formula: %w", err)
+	}
 
 	return nil
 }
 
+// ArchiveDir is the directory under work/ that holds archived work
+const ArchiveDir = ".archive"
+
+// GetArchivePath returns the path a work directory is moved to when archived
+func GetArchivePath(repoPath, workName string) string {
+	return filepath.Join(repoPath, "work", ArchiveDir, workName)
+}
+
+// List returns the names of active work directories, skipping formulas,
+// archived work and other hidden directories
+func List(repoPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(repoPath, "work"))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read work directory: %w", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "formula" || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// Archive moves work/<name>/ to work/.archive/<name>/
+func Archive(repoPath, workName string) error {
+	if workName == "" || workName == "formula" || strings.ContainsAny(workName, "/\\") || strings.HasPrefix(workName, ".") {
+		return fmt.Errorf("invalid work name: %q", workName)
+	}
+
+	workPath := GetWorkPath(repoPath, workName)
+	if info, err := os.Stat(workPath); err != nil || !info.IsDir() {
+		return fmt.Errorf("work not found: work/%s", workName)
+	}
+
+	archivePath := GetArchivePath(repoPath, workName)
+	if _, err := os.Stat(archivePath); err == nil {
+		return fmt.Errorf("already archived: work/%s/%s", ArchiveDir, workName)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.Rename(workPath, archivePath); err != nil {
+		return fmt.Errorf("failed to archive work: %w", err)
+	}
+	return nil
+}
+
+// builtinFormulas holds the formulas rig ships with, one <name>.md per formula
+//
+//go:embed formulas/*.md
+var builtinFormulas embed.FS
+
+// FormulaContent returns the content of a built-in formula
+func FormulaContent(name string) (string, bool) {
+	data, err := builtinFormulas.ReadFile("formulas/" + name + ".md")
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
 // EnsureDefaultFormula installs the default build formula if it doesn't exist
 func EnsureDefaultFormula(repoPath string) error {
-	formulaPath := GetFormulaPath(repoPath, "build")
+	return ensureFormula(repoPath, "build")
+}
+
+// EnsureReviewFormula installs the built-in review formula if it doesn't exist
+func EnsureReviewFormula(repoPath string) error {
+	return ensureFormula(repoPath, "review")
+}
+
+// ensureFormula installs a built-in formula unless one with that name
+// already exists
+func ensureFormula(repoPath, formulaName string) error {
+	content, ok := FormulaContent(formulaName)
+	if !ok {
+		return fmt.Errorf("no built-in formula: %s", formulaName)
+	}
+	formulaPath := GetFormulaPath(repoPath, formulaName)
 
 	// Skip if already exists
 	if _, err := os.Stat(formulaPath); err == nil {
@@ -111,15 +220,21 @@ func EnsureDefaultFormula(repoPath string) error {
 		return fmt.Errorf("failed to create formula directory: %w", err)
 	}
 
-	// Write default formula
-	if err := os.WriteFile(formulaPath, []byte(getDefaultFormulaContent()), 0644); err != nil {
+	// Write formula
+	if err := os.WriteFile(formulaPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write formula: %w", err)
 	}
 
 	return nil
 }
 
-// ParseProgress reads and parses a progress.md file
+// ErrMalformedProgress is returned by ParseProgress for a progress file
+// without a Status line
+var ErrMalformedProgress = errors.New("malformed progress file")
+
+// ParseProgress reads and parses a progress.md file. A missing file gives an
+// error matching os.ErrNotExist; one without a Status line gives
+// ErrMalformedProgress.
 func ParseProgress(path string) (*Progress, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -190,6 +305,9 @@ func ParseProgress(path string) (*Progress, error) {
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("error reading progress file: %w", err)
 	}
+	if progress.Status == "" {
+		return nil, fmt.Errorf("%w: %s has no '## Status:' line", ErrMalformedProgress, path)
+	}
 
 	progress.Notes = strings.Join(notesLines, "\n")
 	return progress, nil
@@ -205,44 +323,67 @@ func (p *Progress) GetCurrentTask() string {
 	return ""
 }
 
-// GenerateHook creates a hook.md file for a work item
-func GenerateHook(repoPath, workName, formulaName string) error {
-	workPath := GetWorkPath(repoPath, workName)
-	hookPath := filepath.Join(workPath, "hook.md")
-	formulaPath := GetFormulaPath(repoPath, formulaName)
+// HookOptions controls optional content in a generated hook
+type HookOptions struct {
+	InlineFormula bool // include the formula's phase headings in the hook
+}
 
-	// Validate formula exists
-	if _, err := os.Stat(formulaPath); os.IsNotExist(err) {
-		return fmt.Errorf("formula not found: %s", formulaPath)
+// ParseFormulaPhases returns the "Phase N: ..." headings of a formula file
+func ParseFormulaPhases(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read formula: %w", err)
 	}
 
-	// Generate hook content
-	content := fmt.Sprintf(`# Hook: %s
+	phases := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "### Phase ") {
+			phases = append(phases, strings.TrimPrefix(line, "### "))
+		}
+	}
+
+	return phases, nil
+}
+
+// hookData holds the fields available to the hook template
+type hookData struct {
+	WorkName    string
+	FormulaPath string // repo-relative path to the formula
+	Phases      []string
+}
+
+// hookTemplate renders hook.md
+var hookTemplate = template.Must(template.New("hook").Parse(`# Hook: {{.WorkName}}
 
 ## Your Assignment
 
-You are working on: **%s**
+You are working on: **{{.WorkName}}**
 
 ## Instructions
 
-1. **Read the workflow formula**: Open and read work/formula/%s.md
+1. **Read the workflow formula**: Open and read {{.FormulaPath}}
    - This defines the phases you'll follow
 
-2. **Read the spec**: Open and read work/%s/spec.md
+2. **Read the spec**: Open and read work/{{.WorkName}}/spec.md
    - This describes what you're building
 
 3. **Follow the formula**: Execute each phase in order
-   - Update work/%s/progress.md as you complete tasks
+   - Update work/{{.WorkName}}/progress.md as you complete tasks
    - Commit your progress after each phase
    - Each commit should follow the pattern described in the formula
+{{if .Phases}}
+## Formula Phases
 
+{{range .Phases}}- {{.}}
+{{end}}{{end}}
 ## Context Files
 
-- Formula: work/formula/%s.md
-- Spec: work/%s/spec.md
-- Design: work/%s/design.md
-- Breakdown: work/%s/breakdown.md
-- Progress: work/%s/progress.md
+- Formula: {{.FormulaPath}}
+- Spec: work/{{.WorkName}}/spec.md
+- Design: work/{{.WorkName}}/design.md
+- Breakdown: work/{{.WorkName}}/breakdown.md
+- Progress: work/{{.WorkName}}/progress.md
 
 ## Important Notes
 
@@ -252,10 +393,135 @@ You are working on: **%s**
 - Ask questions if requirements are unclear
 
 Ready? Start by reading the formula and spec files above.
-`, workName, workName, formulaName, workName, workName, formulaName, workName, workName, workName, workName)
+`))
+
+// reviewHookTemplate renders a reviewer's review-hook.md
+var reviewHookTemplate = template.Must(template.New("review-hook").Parse(`# Review Hook: {{.WorkName}}
+
+## Your Assignment
+
+You are reviewing: **{{.WorkName}}**
+
+Someone else is building this on feat/{{.WorkName}}. Your workspace is a
+copy of their branch for reading: don't change the implementation, report
+what you find instead.
+
+## Instructions
+
+1. **Read the review formula**: Open and read {{.FormulaPath}}
+   - This defines how to review
+
+2. **Read the spec**: Open and read work/{{.WorkName}}/spec.md
+   - This describes what should have been built
+
+3. **Review the changes**: Compare the branch against the base branch
+   - Use git log and git diff to see what the builder changed
+   - Check the changes against the spec and work/{{.WorkName}}/progress.md
+
+4. **Write up your review**: Add a Review section to work/{{.WorkName}}/progress.md
+   - Commit it on this review branch so the builder can read it
+{{if .Phases}}
+## Formula Phases
+
+{{range .Phases}}- {{.}}
+{{end}}{{end}}
+## Context Files
+
+- Formula: {{.FormulaPath}}
+- Spec: work/{{.WorkName}}/spec.md
+- Progress: work/{{.WorkName}}/progress.md
+
+Ready? Start by reading the formula and spec files above.
+`))
+
+// FormulaFileName is the name an ad-hoc formula is copied to in a work directory
+const FormulaFileName = "formula.md"
+
+// ReviewHookFileName is the name of a reviewer's hook in a work directory,
+// kept apart from the builder's hook.md
+const ReviewHookFileName = "review-hook.md"
+
+// ReviewFormulaFileName is the name a reviewer's formula is copied to in a
+// work directory
+const ReviewFormulaFileName = "review-formula.md"
+
+// GenerateHook creates a hook.md file for a work item
+func GenerateHook(repoPath, workName, formulaName string, opts HookOptions) error {
+	formulaPath := GetFormulaPath(repoPath, formulaName)
+
+	// Validate formula exists
+	if _, err := os.Stat(formulaPath); os.IsNotExist(err) {
+		return fmt.Errorf("formula not found: %s", formulaPath)
+	}
+
+	return writeHook(repoPath, workName, formulaPath, opts)
+}
+
+// GenerateHookFromFile creates a hook.md file using an ad-hoc formula file,
+// copying it into the work directory so the assignee can read it
+func GenerateHookFromFile(repoPath, workName, formulaFile string, opts HookOptions) error {
+	data, err := os.ReadFile(formulaFile)
+	if err != nil {
+		return fmt.Errorf("failed to read formula file: %w", err)
+	}
+
+	formulaPath := filepath.Join(GetWorkPath(repoPath, workName), FormulaFileName)
+	if err := os.WriteFile(formulaPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to copy formula file: %w", err)
+	}
+
+	return writeHook(repoPath, workName, formulaPath, opts)
+}
+
+// GenerateReviewHook creates a review-hook.md file for a reviewer of a work
+// item, copying the formula file into the work directory
+func GenerateReviewHook(repoPath, workName, formulaFile string, opts HookOptions) error {
+	data, err := os.ReadFile(formulaFile)
+	if err != nil {
+		return fmt.Errorf("failed to read formula file: %w", err)
+	}
+
+	formulaPath := filepath.Join(GetWorkPath(repoPath, workName), ReviewFormulaFileName)
+	if err := os.WriteFile(formulaPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to copy formula file: %w", err)
+	}
+
+	hookPath := filepath.Join(GetWorkPath(repoPath, workName), ReviewHookFileName)
+	return renderHook(reviewHookTemplate, hookPath, repoPath, workName, formulaPath, opts)
+}
+
+// writeHook renders hook.md for a work item pointing at the given formula
+func writeHook(repoPath, workName, formulaPath string, opts HookOptions) error {
+	hookPath := filepath.Join(GetWorkPath(repoPath, workName), "hook.md")
+	return renderHook(hookTemplate, hookPath, repoPath, workName, formulaPath, opts)
+}
+
+// renderHook renders a hook template for a work item into hookPath
+func renderHook(tmpl *template.Template, hookPath, repoPath, workName, formulaPath string, opts HookOptions) error {
+
+	relFormula, err := filepath.Rel(repoPath, formulaPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve formula path: %w", err)
+	}
+	data := hookData{WorkName: workName, FormulaPath: filepath.ToSlash(relFormula)}
+
+	// Summarize the formula's phases if asked
+	if opts.InlineFormula {
+		phases, err := ParseFormulaPhases(formulaPath)
+		if err != nil {
+			return err
+		}
+		data.Phases = phases
+	}
+
+	// Generate hook content
+	var content strings.Builder
+	if err := tmpl.Execute(&content, data); err != nil {
+		return fmt.Errorf("failed to render hook: %w", err)
+	}
 
 	// Write hook file
-	if err := os.WriteFile(hookPath, []byte(content), 0644); err != nil {
+	if err := os.WriteFile(hookPath, []byte(content.String()), 0644); err != nil {
 		return fmt.Errorf("failed to write hook file: %w", err)
 	}
 
@@ -289,6 +555,45 @@ func ListFormulas(repoPath string) ([]string, error) {
 
 // Templates
 
+// TemplatesDir is the directory under work/ holding scaffolding overrides
+const TemplatesDir = ".templates"
+
+// templateData is what override templates can reference: {{.Name}} is the
+// work name as given (build-frontend) and {{.Title}} the heading form used by
+// the built-in templates (Build Frontend)
+type templateData struct {
+	Name  string
+	Title string
+}
+
+// scaffoldContent returns the content for a scaffolded file, using
+// work/.templates/<filename> when present and the built-in template otherwise
+func scaffoldContent(repoPath, filename, workName string, builtin func(string) string) (string, error) {
+	overridePath := filepath.Join(repoPath, "work", TemplatesDir, filename)
+	data, err := os.ReadFile(overridePath)
+	if os.IsNotExist(err) {
+		return builtin(workName), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", overridePath, err)
+	}
+
+	tmpl, err := template.New(filename).Parse(string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", overridePath, err)
+	}
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, templateData{
+		Name:  workName,
+		Title: strings.Title(strings.ReplaceAll(workName, "-", " ")),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", overridePath, err)
+	}
+	return out.String(), nil
+}
+
 func getSpecTemplate(workName string) string {
 	return fmt.Sprintf(`# Spec: %s
 
@@ -372,97 +677,3 @@ func getProgressTemplate(workName string) string {
 ## Notes
 `, strings.Title(strings.ReplaceAll(workName, "-", " ")))
 }
-
-func getDefaultFormulaContent() string {
-	return `
-# Feature Implementation Formula
-
-Autonomous end-to-end feature implementation with built-in quality gates.
-Takes a spec, designs the approach, implements the solution, validates
-with tests, and commits to local git repo.
-
-## Process
-
-### Phase 1: Spec Review (Read-Only)
-1. Read the spec thoroughly
-2. Identify what exists vs what's new
-3. List dependencies on other systems/modules
-4. Flag critical gaps:
-   - Missing acceptance criteria
-   - Unclear requirements
-   - Ambiguous edge cases
-
-**Gate:** If critical gaps exist, create ` + "`CLARIFICATIONS.md`" + ` and STOP. Otherwise continue.
-
-### Phase 2: Design
-1. Survey existing codebase for patterns to follow
-2. Identify files to create/modify
-3. Design module structure and interfaces
-4. Plan test strategy (unit, integration, e2e)
-5. Update ` + "`design.md`" + ` with:
-   - Files to change
-   - New abstractions needed
-   - Testing approach
-   - Risk areas
-6. **Commit progress:** ` + "`git commit -am \"docs: complete design phase\"`" + `
-
-**Gate:** Review design. If major concerns, revise. Otherwise continue.
-
-### Phase 3: Implementation Planning
-1. Break design into tasks in ` + "`breakdown.md`" + `. Each task should:
-   - Be completable in one session
-   - Have clear done criteria
-   - Be independently testable
-   - Produce a commit
-2. Update progress.md checklist with specific tasks
-3. **Commit progress:** ` + "`git commit -am \"docs: create implementation breakdown\"`" + `
-
-### Phase 4: Implementation
-For each task:
-1. Mark task as in progress in ` + "`progress.md`" + `
-2. Work on task until done criteria met
-3. Run relevant tests
-4. **Commit with message:** ` + "`feat: [task description]`" + `
-5. Mark task complete in ` + "`progress.md`" + `
-
-**Gate:** After each task, verify tests pass. If fail, fix before next task.
-
-### Phase 5: Review
-1. Read all changed code
-2. Check against spec acceptance criteria
-3. Verify test coverage
-4. Look for:
-   - Performance issues
-   - Security concerns
-   - Error handling gaps
-   - Documentation needs
-5. Create review notes in ` + "`progress.md`" + `
-6. **Commit progress:** ` + "`git commit -am \"docs: complete code review\"`" + `
-
-**Gate:** If major issues, fix and re-review. Otherwise continue.
-
-### Phase 6: Final Steps
-1. Run full test suite
-2. Update any necessary documentation
-3. Final verification against spec
-4. Update ` + "`progress.md`" + ` status to "Ready for Merge"
-5. **Final commit:** ` + "`git commit -am \"docs: mark work ready for merge\"`" + `
-
-## Important Notes
-
-- **Commit intermediate progress at each phase** - This ensures work is always recoverable
-- **Keep progress.md updated** - This is your state tracking mechanism
-- **Each phase should leave work in a consistent state** - Anyone should be able to pick up from any phase
-- **When complete, remind user to:**
-  - Push feature branch: ` + "`git push -u origin feat/<feature-name>`" + `
-  - Cleanup crew workspace: ` + "`rig crew remove <worker-name>`" + `
-  - Create pull request if needed
-
-## Outputs
-- Updated ` + "`design.md`" + ` - Design document
-- Updated ` + "`breakdown.md`" + ` - Implementation tasks
-- Updated ` + "`progress.md`" + ` - Progress tracking with status
-- Feature implementation with test coverage
-- Git commits following conventional commits
-`
-}
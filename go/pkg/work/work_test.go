@@ -1,8 +1,11 @@
 package work
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -113,6 +116,25 @@ About the work
 	}
 }
 
+func TestParseProgressErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := ParseProgress(filepath.Join(tmpDir, "missing.md"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for a missing file, got %v", err)
+	}
+
+	malformed := filepath.Join(tmpDir, "progress.md")
+	os.WriteFile(malformed, []byte("# Progress\n\nJust some notes\n"), 0644)
+	_, err = ParseProgress(malformed)
+	if !errors.Is(err, ErrMalformedProgress) {
+		t.Errorf("Expected ErrMalformedProgress without a Status line, got %v", err)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected a malformed file not to look missing")
+	}
+}
+
 func TestGetCurrentTask(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -200,7 +222,7 @@ func TestGenerateHook(t *testing.T) {
 	}
 
 	// Generate hook
-	err := GenerateHook(tmpDir, workName, formulaName)
+	err := GenerateHook(tmpDir, workName, formulaName, HookOptions{})
 	if err != nil {
 		t.Fatalf("GenerateHook() error = %v", err)
 	}
@@ -229,6 +251,165 @@ func TestGenerateHook(t *testing.T) {
 	}
 }
 
+func TestGenerateHookPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	workName := "add-auth"
+	formulaName := "hotfix"
+
+	if err := os.MkdirAll(GetWorkPath(tmpDir, workName), 0755); err != nil {
+		t.Fatalf("Failed to create work directory: %v", err)
+	}
+	formulaPath := GetFormulaPath(tmpDir, formulaName)
+	if err := os.MkdirAll(filepath.Dir(formulaPath), 0755); err != nil {
+		t.Fatalf("Failed to create formula directory: %v", err)
+	}
+	if err := os.WriteFile(formulaPath, []byte("# Hotfix Formula"), 0644); err != nil {
+		t.Fatalf("Failed to create formula file: %v", err)
+	}
+
+	if err := GenerateHook(tmpDir, workName, formulaName, HookOptions{}); err != nil {
+		t.Fatalf("GenerateHook() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(GetWorkPath(tmpDir, workName), "hook.md"))
+	if err != nil {
+		t.Fatalf("Failed to read hook file: %v", err)
+	}
+
+	expected := []string{
+		"# Hook: add-auth\n",
+		"You are working on: **add-auth**",
+		"Open and read work/formula/hotfix.md",
+		"Open and read work/add-auth/spec.md",
+		"Update work/add-auth/progress.md as you complete tasks",
+		"- Formula: work/formula/hotfix.md\n",
+		"- Spec: work/add-auth/spec.md\n",
+		"- Design: work/add-auth/design.md\n",
+		"- Breakdown: work/add-auth/breakdown.md\n",
+		"- Progress: work/add-auth/progress.md\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Hook content missing %q", want)
+		}
+	}
+
+	// Names must never leak into each other's paths
+	for _, wrong := range []string{"work/formula/add-auth.md", "work/hotfix/", "<no value>"} {
+		if strings.Contains(string(content), wrong) {
+			t.Errorf("Hook content unexpectedly contains %q", wrong)
+		}
+	}
+}
+
+func TestGenerateHookFromFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "repo")
+	workPath := GetWorkPath(repoPath, "add-auth")
+	if err := os.MkdirAll(workPath, 0755); err != nil {
+		t.Fatalf("Failed to create work directory: %v", err)
+	}
+
+	formulaFile := filepath.Join(tmpDir, "one-off.md")
+	formula := "# One-off\n\n### Phase 1: Spike\n\n### Phase 2: Write it up\n"
+	if err := os.WriteFile(formulaFile, []byte(formula), 0644); err != nil {
+		t.Fatalf("Failed to create formula file: %v", err)
+	}
+
+	if err := GenerateHookFromFile(repoPath, "add-auth", formulaFile, HookOptions{InlineFormula: true}); err != nil {
+		t.Fatalf("GenerateHookFromFile() error = %v", err)
+	}
+
+	// The formula is copied next to the spec
+	copied, err := os.ReadFile(filepath.Join(workPath, FormulaFileName))
+	if err != nil {
+		t.Fatalf("Formula was not copied: %v", err)
+	}
+	if string(copied) != formula {
+		t.Errorf("Copied formula = %q, want %q", copied, formula)
+	}
+
+	content, err := os.ReadFile(filepath.Join(workPath, "hook.md"))
+	if err != nil {
+		t.Fatalf("Failed to read hook file: %v", err)
+	}
+	for _, want := range []string{
+		"Open and read work/add-auth/formula.md",
+		"- Formula: work/add-auth/formula.md\n",
+		"- Phase 1: Spike\n- Phase 2: Write it up\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Hook content missing %q", want)
+		}
+	}
+	if strings.Contains(string(content), "work/formula/") {
+		t.Errorf("Hook should not reference the formula directory, got:\n%s", content)
+	}
+
+	if err := GenerateHookFromFile(repoPath, "add-auth", filepath.Join(tmpDir, "missing.md"), HookOptions{}); err == nil {
+		t.Error("Expected error for missing formula file, got nil")
+	}
+}
+
+func TestGenerateReviewHook(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "repo")
+	workPath := GetWorkPath(repoPath, "add-auth")
+	if err := os.MkdirAll(workPath, 0755); err != nil {
+		t.Fatalf("Failed to create work directory: %v", err)
+	}
+
+	// The builder's hook must survive
+	builderHook := "# Hook: add-auth\n"
+	os.WriteFile(filepath.Join(workPath, "hook.md"), []byte(builderHook), 0644)
+
+	formulaFile := filepath.Join(tmpDir, "review.md")
+	formula := "# Review\n\n### Phase 1: Read the spec\n"
+	if err := os.WriteFile(formulaFile, []byte(formula), 0644); err != nil {
+		t.Fatalf("Failed to create formula file: %v", err)
+	}
+
+	if err := GenerateReviewHook(repoPath, "add-auth", formulaFile, HookOptions{InlineFormula: true}); err != nil {
+		t.Fatalf("GenerateReviewHook() error = %v", err)
+	}
+
+	copied, err := os.ReadFile(filepath.Join(workPath, ReviewFormulaFileName))
+	if err != nil || string(copied) != formula {
+		t.Errorf("Copied formula = %q (%v), want %q", copied, err, formula)
+	}
+
+	content, err := os.ReadFile(filepath.Join(workPath, ReviewHookFileName))
+	if err != nil {
+		t.Fatalf("Failed to read review hook: %v", err)
+	}
+	for _, want := range []string{
+		"# Review Hook: add-auth",
+		"You are reviewing: **add-auth**",
+		"don't change the implementation",
+		"Open and read work/add-auth/review-formula.md",
+		"Add a Review section to work/add-auth/progress.md",
+		"- Phase 1: Read the spec\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Review hook missing %q, got:\n%s", want, content)
+		}
+	}
+
+	if hook, _ := os.ReadFile(filepath.Join(workPath, "hook.md")); string(hook) != builderHook {
+		t.Errorf("Expected the builder's hook to be untouched, got %q", hook)
+	}
+}
+
+func TestInferReviewFromBranch(t *testing.T) {
+	if got := InferReviewFromBranch(ReviewBranch("add-auth")); got != "add-auth" {
+		t.Errorf("InferReviewFromBranch(ReviewBranch(add-auth)) = %q, want add-auth", got)
+	}
+	for _, branch := range []string{"feat/add-auth", "main", ""} {
+		if got := InferReviewFromBranch(branch); got != "" {
+			t.Errorf("InferReviewFromBranch(%q) = %q, want empty", branch, got)
+		}
+	}
+}
+
 func TestGenerateHookMissingFormula(t *testing.T) {
 	tmpDir := t.TempDir()
 	workName := "test-feature"
@@ -241,12 +422,70 @@ func TestGenerateHookMissingFormula(t *testing.T) {
 	}
 
 	// Try to generate hook with missing formula
-	err := GenerateHook(tmpDir, workName, formulaName)
+	err := GenerateHook(tmpDir, workName, formulaName, HookOptions{})
 	if err == nil {
 		t.Error("Expected error for missing formula, got nil")
 	}
 }
 
+func TestParseFormulaPhases(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := EnsureDefaultFormula(tmpDir); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+
+	phases, err := ParseFormulaPhases(GetFormulaPath(tmpDir, "build"))
+	if err != nil {
+		t.Fatalf("ParseFormulaPhases() error = %v", err)
+	}
+
+	expected := []string{
+		"Phase 1: Spec Review (Read-Only)",
+		"Phase 2: Design",
+		"Phase 3: Implementation Planning",
+		"Phase 4: Implementation",
+		"Phase 5: Review",
+		"Phase 6: Final Steps",
+	}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("ParseFormulaPhases() = %v, want %v", phases, expected)
+	}
+
+	if _, err := ParseFormulaPhases(GetFormulaPath(tmpDir, "missing")); err == nil {
+		t.Error("Expected error for missing formula, got nil")
+	}
+}
+
+func TestGenerateHookInlineFormula(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := EnsureDefaultFormula(tmpDir); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+	workPath := GetWorkPath(tmpDir, "test-feature")
+	if err := os.MkdirAll(workPath, 0755); err != nil {
+		t.Fatalf("Failed to create work directory: %v", err)
+	}
+	hookPath := filepath.Join(workPath, "hook.md")
+
+	// Default hooks stay lightweight
+	if err := GenerateHook(tmpDir, "test-feature", "build", HookOptions{}); err != nil {
+		t.Fatalf("GenerateHook() error = %v", err)
+	}
+	content, _ := os.ReadFile(hookPath)
+	if strings.Contains(string(content), "Formula Phases") {
+		t.Errorf("Expected no phase summary by default, got:\n%s", content)
+	}
+
+	if err := GenerateHook(tmpDir, "test-feature", "build", HookOptions{InlineFormula: true}); err != nil {
+		t.Fatalf("GenerateHook() error = %v", err)
+	}
+	content, _ = os.ReadFile(hookPath)
+	if !strings.Contains(string(content), "## Formula Phases\n\n- Phase 1: Spec Review (Read-Only)\n") ||
+		!strings.Contains(string(content), "- Phase 6: Final Steps\n") {
+		t.Errorf("Expected phase summary in hook, got:\n%s", content)
+	}
+}
+
 func TestListFormulas(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -295,3 +534,162 @@ func TestListFormulas(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateInstallsFormulas(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := Create(repoPath, "add-auth"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	formulas, err := ListFormulas(repoPath)
+	if err != nil {
+		t.Fatalf("ListFormulas() error = %v", err)
+	}
+	if !reflect.DeepEqual(formulas, []string{"build", "review"}) {
+		t.Errorf("ListFormulas() = %v, want [build review]", formulas)
+	}
+
+	review, err := os.ReadFile(GetFormulaPath(repoPath, "review"))
+	if err != nil {
+		t.Fatalf("Failed to read review formula: %v", err)
+	}
+	if !strings.Contains(string(review), "# Code Review Formula") {
+		t.Errorf("Unexpected review formula:\n%s", review)
+	}
+
+	// An edited formula is left alone
+	os.WriteFile(GetFormulaPath(repoPath, "review"), []byte("# Ours\n"), 0644)
+	if err := EnsureReviewFormula(repoPath); err != nil {
+		t.Fatalf("EnsureReviewFormula() error = %v", err)
+	}
+	if content, _ := os.ReadFile(GetFormulaPath(repoPath, "review")); string(content) != "# Ours\n" {
+		t.Errorf("Expected existing review formula to be kept, got %q", content)
+	}
+}
+
+func TestFormulaContent(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := EnsureDefaultFormula(repoPath); err != nil {
+		t.Fatalf("EnsureDefaultFormula() error = %v", err)
+	}
+
+	build, ok := FormulaContent("build")
+	if !ok || !strings.HasPrefix(build, "# Feature Implementation Formula\n") {
+		t.Fatalf("FormulaContent(build) = %q, %v", build, ok)
+	}
+	written, err := os.ReadFile(GetFormulaPath(repoPath, "build"))
+	if err != nil {
+		t.Fatalf("Failed to read build formula: %v", err)
+	}
+	if string(written) != build {
+		t.Errorf("Written build formula doesn't match the embedded one:\n%s", written)
+	}
+
+	if _, ok := FormulaContent("missing"); ok {
+		t.Error("Expected no built-in formula named missing")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	repoPath := t.TempDir()
+	for _, name := range []string{"build-frontend", "add-auth"} {
+		if err := Create(repoPath, name); err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+	}
+
+	if err := Archive(repoPath, "build-frontend"); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	if _, err := os.Stat(GetWorkPath(repoPath, "build-frontend")); !os.IsNotExist(err) {
+		t.Error("Expected work directory to be moved away")
+	}
+	archived := filepath.Join(repoPath, "work", ".archive", "build-frontend", "spec.md")
+	if _, err := os.Stat(archived); err != nil {
+		t.Errorf("Expected archived spec at %s: %v", archived, err)
+	}
+
+	names, err := List(repoPath)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(names) != 1 || names[0] != "add-auth" {
+		t.Errorf("List() = %v, want [add-auth]", names)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, name := range []string{"build-frontend", "missing", "formula", ".archive", "../escape", ""} {
+			if err := Archive(repoPath, name); err == nil {
+				t.Errorf("Expected error archiving %q", name)
+			}
+		}
+	})
+
+	t.Run("already archived", func(t *testing.T) {
+		Create(repoPath, "build-frontend")
+		if err := Archive(repoPath, "build-frontend"); err == nil {
+			t.Error("Expected error when archive already holds the name")
+		}
+	})
+}
+
+func TestListNoWorkDir(t *testing.T) {
+	names, err := List(t.TempDir())
+	if err != nil || len(names) != 0 {
+		t.Errorf("List() = %v, %v; want empty, nil", names, err)
+	}
+}
+
+func TestCreateWithTemplates(t *testing.T) {
+	t.Run("built-in templates", func(t *testing.T) {
+		repoPath := t.TempDir()
+		if err := Create(repoPath, "build-frontend"); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		data, _ := os.ReadFile(filepath.Join(GetWorkPath(repoPath, "build-frontend"), "spec.md"))
+		if !strings.HasPrefix(string(data), "# Spec: Build Frontend\n") {
+			t.Errorf("Expected built-in spec, got:\n%s", data)
+		}
+	})
+
+	t.Run("override templates", func(t *testing.T) {
+		repoPath := t.TempDir()
+		templatesDir := filepath.Join(repoPath, "work", ".templates")
+		os.MkdirAll(templatesDir, 0755)
+		os.WriteFile(filepath.Join(templatesDir, "spec.md"), []byte("# {{.Title}}\n\nTicket: {{.Name}}\n"), 0644)
+
+		if err := Create(repoPath, "build-frontend"); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		workPath := GetWorkPath(repoPath, "build-frontend")
+		data, _ := os.ReadFile(filepath.Join(workPath, "spec.md"))
+		if string(data) != "# Build Frontend\n\nTicket: build-frontend\n" {
+			t.Errorf("Expected override spec, got:\n%s", data)
+		}
+
+		// Files without an override fall back to the built-ins
+		data, _ = os.ReadFile(filepath.Join(workPath, "design.md"))
+		if !strings.HasPrefix(string(data), "# Design: Build Frontend\n") {
+			t.Errorf("Expected built-in design, got:\n%s", data)
+		}
+
+		names, _ := List(repoPath)
+		if len(names) != 1 || names[0] != "build-frontend" {
+			t.Errorf("Expected templates dir to be excluded from List(), got %v", names)
+		}
+	})
+
+	t.Run("invalid override template", func(t *testing.T) {
+		repoPath := t.TempDir()
+		templatesDir := filepath.Join(repoPath, "work", ".templates")
+		os.MkdirAll(templatesDir, 0755)
+		os.WriteFile(filepath.Join(templatesDir, "progress.md"), []byte("# {{.Unknown}}\n"), 0644)
+
+		if err := Create(repoPath, "build-frontend"); err == nil {
+			t.Error("Expected error for template referencing an unknown field")
+		}
+	})
+}